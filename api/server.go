@@ -28,7 +28,9 @@ func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error)
 
 	})
 	s.echoWebServer.GET("/ready", func(c echo.Context) error {
-
+		if !s.bindingService.IsHealthy() {
+			return c.String(503, "not ready")
+		}
 		return c.String(200, "ready")
 
 	})
@@ -39,6 +41,10 @@ func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error)
 	s.echoWebServer.GET("/bindings/stats", func(c echo.Context) error {
 		return c.JSONPretty(200, s.bindingService.Stats(), "\t")
 	})
+	s.echoWebServer.GET("/bindings/:name/tap", s.tap)
+	s.echoWebServer.GET("/clients", func(c echo.Context) error {
+		return c.JSONPretty(200, s.bindingService.Clients(c.Request().Context()), "\t")
+	})
 	errCh := make(chan error, 1)
 	go func() {
 		errCh <- s.echoWebServer.Start(fmt.Sprintf("0.0.0.0:%d", port))
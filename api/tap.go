@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultTapBuffer     = 100
+	tapHeartbeatInterval = 15 * time.Second
+)
+
+// tap streams the messages samples of a binding as server sent events until the client disconnects
+func (s *Server) tap(c echo.Context) error {
+	stage, err := tap.ParseStage(c.QueryParam("stage"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	every, err := parseQueryInt(c, "every", 1, tap.MaxEvery)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	buffer, err := parseQueryInt(c, "buffer", defaultTapBuffer, tap.MaxBuffer)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	sub, err := s.bindingService.Tap(c.Param("name"), stage, every, buffer)
+	if err != nil {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	defer tap.Unsubscribe(sub)
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+	heartbeat := time.NewTicker(tapHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case sample, ok := <-sub.Samples():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(sample)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return nil
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(w, ": dropped %d\n\n", sub.Dropped()); err != nil {
+				return nil
+			}
+			w.Flush()
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// parseQueryInt returns the query parameter value, which must be a number between 1 and max, the client sets it without authentication
+func parseQueryInt(c echo.Context, key string, defaultValue, max int) (int, error) {
+	value := c.QueryParam(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, fmt.Errorf("invalid %s value %s, must be a number between 1 and %d", key, value, max)
+	}
+	return n, nil
+}
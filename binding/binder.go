@@ -5,24 +5,32 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
 	"github.com/kubemq-io/kubemq-bridges/sources"
 	"github.com/kubemq-io/kubemq-bridges/targets"
 )
 
 type Binder struct {
 	name              string
+	sourceKind        string
 	log               *logger.Logger
 	sources           []sources.Source
 	targetsMiddleware []middleware.Middleware
 	targets           []targets.Target
+	validator         *middleware.ValidateMiddleware
+	script            *middleware.ScriptMiddleware
+	wasm              *middleware.WasmMiddleware
+	aggregators       []*middleware.AggregateMiddleware
+	enricher          *middleware.EnrichMiddleware
 }
 
 func NewBinder() *Binder {
 	return &Binder{}
 }
-func (b *Binder) buildMiddleware(target targets.Target, cfg config.BindingConfig, exporter *metrics.Exporter) (middleware.Middleware, error) {
+func (b *Binder) buildMiddleware(target targets.Target, index int, cfg config.BindingConfig, exporter *metrics.Exporter) (middleware.Middleware, error) {
 
 	retry, err := middleware.NewRetryMiddleware(cfg.Properties, b.log)
 	if err != nil {
@@ -32,28 +40,95 @@ func (b *Binder) buildMiddleware(target targets.Target, cfg config.BindingConfig
 	if err != nil {
 		return nil, err
 	}
-	var md middleware.Middleware
+	log, err := middleware.NewLogMiddleware(cfg.Name, cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	converter, err := middleware.NewConvertMiddleware(cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	compressor, err := middleware.NewCompressMiddleware(cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	secure, err := middleware.NewSecureMiddleware(cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	aggregator, err := middleware.NewAggregateMiddleware(cfg.Properties, b.log)
+	if err != nil {
+		return nil, err
+	}
+	b.aggregators = append(b.aggregators, aggregator)
+	rules, err := tap.ParseRules(cfg.Properties, "tap_")
+	if err != nil {
+		return nil, err
+	}
+	// the source stage tap sees the messages as received from the source, the target stage tap as sent to the target.
+	// received messages are verified, decrypted, decompressed and split before validation, enrichment, scripts, wasm modules and conversion,
+	// then aggregated, compressed, encrypted and signed
+	list := []middleware.MiddlewareFunc{
+		middleware.Tap(cfg.Name, tap.StageTarget, index, rules),
+		middleware.RateLimiter(rateLimiter),
+		middleware.Retry(retry),
+		middleware.Sign(secure),
+		middleware.Encrypt(secure),
+		middleware.Compress(compressor),
+		middleware.Aggregate(aggregator),
+		middleware.Convert(converter),
+		middleware.Wasm(b.wasm),
+		middleware.Script(b.script),
+		middleware.Enrich(b.enricher),
+		middleware.Validate(b.validator),
+		middleware.Split(aggregator),
+		middleware.Decompress(compressor),
+		middleware.Decrypt(secure),
+		middleware.Verify(secure),
+		middleware.Log(log),
+	}
 	if exporter != nil {
 		met, err := middleware.NewMetricsMiddleware(cfg, exporter)
 		if err != nil {
 			return nil, err
 		}
-		md = middleware.Chain(target, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Metric(met))
-	} else {
-		md = middleware.Chain(target, middleware.RateLimiter(rateLimiter), middleware.Retry(retry))
+		list = append(list, middleware.Metric(met))
 	}
-
-	return md, nil
+	// the source message marks the requests of the target chains, so invalid messages are dead-lettered once for all the targets
+	list = append(list, middleware.Tap(cfg.Name, tap.StageSource, index, rules), middleware.Source())
+	return middleware.Chain(target, list...), nil
 }
 func (b *Binder) Init(ctx context.Context, cfg config.BindingConfig, exporter *metrics.Exporter, logLevel string) error {
 	b.name = cfg.Name
+	b.sourceKind = cfg.Sources.Kind
 	b.log = logger.NewLogger(cfg.Name, logLevel)
-	for _, connection := range cfg.Targets.Connections {
+	// the schema registry, the script, the wasm module instances and the enrichment cache are shared by all the binding targets
+	validator, err := middleware.NewValidateMiddleware(cfg.Name, cfg.Properties, b.log)
+	if err != nil {
+		return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
+	}
+	b.validator = validator
+	script, err := middleware.NewScriptMiddleware(cfg.Properties, b.log)
+	if err != nil {
+		return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
+	}
+	b.script = script
+	wasm, err := middleware.NewWasmMiddleware(ctx, cfg.Properties)
+	if err != nil {
+		return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
+	}
+	b.wasm = wasm
+	enricher, err := middleware.NewEnrichMiddleware(cfg.Name, cfg.Properties, b.log)
+	if err != nil {
+		return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
+	}
+	b.enricher = enricher
+	for i, connection := range cfg.Targets.Connections {
 		target, err := targets.Init(ctx, cfg.Targets.Kind, connection, cfg.Name, b.log)
 		if err != nil {
 			return fmt.Errorf("error loading targets conntector on binding %s, %w", b.name, err)
 		}
-		md, err := b.buildMiddleware(target, cfg, exporter)
+		md, err := b.buildMiddleware(target, i, cfg, exporter)
 		if err != nil {
 			return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
 		}
@@ -90,6 +165,16 @@ func (b *Binder) Start(ctx context.Context) error {
 	b.log.Infof("binding %s started successfully", b.name)
 	return nil
 }
+
+// Health returns the health status of all the binding sources connections
+func (b *Binder) Health() []*health.Status {
+	var list []*health.Status
+	for _, source := range b.sources {
+		list = append(list, source.Health()...)
+	}
+	return list
+}
+
 func (b *Binder) Stop() error {
 	for _, source := range b.sources {
 		err := source.Stop()
@@ -97,12 +182,36 @@ func (b *Binder) Stop() error {
 			return err
 		}
 	}
+	// pending batches are sent before the targets are stopped
+	for _, aggregator := range b.aggregators {
+		aggregator.Close()
+	}
 	for _, target := range b.targets {
 		err := target.Stop()
 		if err != nil {
 			return err
 		}
 	}
+	if b.validator != nil {
+		if err := b.validator.Close(); err != nil {
+			return err
+		}
+	}
+	if b.script != nil {
+		if err := b.script.Close(); err != nil {
+			return err
+		}
+	}
+	if b.wasm != nil {
+		if err := b.wasm.Close(); err != nil {
+			return err
+		}
+	}
+	if b.enricher != nil {
+		if err := b.enricher.Close(); err != nil {
+			return err
+		}
+	}
 	b.log.Infof("binding %s stopped successfully", b.name)
 	return nil
 }
@@ -0,0 +1,140 @@
+package binding
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-go"
+	pb "github.com/kubemq-io/protobuf/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// deadLetterServer is a kubemq server which receives the dead letter queue messages
+type deadLetterServer struct {
+	pb.KubemqServer
+	messages chan *pb.QueueMessage
+}
+
+func (d *deadLetterServer) Ping(ctx context.Context, empty *pb.Empty) (*pb.PingResult, error) {
+	return &pb.PingResult{Host: "test"}, nil
+}
+
+func (d *deadLetterServer) SendQueueMessage(ctx context.Context, msg *pb.QueueMessage) (*pb.SendQueueMessageResult, error) {
+	d.messages <- msg
+	return &pb.SendQueueMessageResult{MessageID: msg.MessageID}, nil
+}
+
+type mockTarget struct {
+	requests int32
+}
+
+func (m *mockTarget) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	return nil
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	atomic.AddInt32(&m.requests, 1)
+	return request, nil
+}
+
+func (m *mockTarget) Stop() error {
+	return nil
+}
+
+func TestBinder_DeadLetterOnce(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	deadLetters := &deadLetterServer{messages: make(chan *pb.QueueMessage, 10)}
+	pb.RegisterKubemqServer(server, deadLetters)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.json"), []byte(`{"type":"object","required":["id"]}`), 0600))
+	cfg := config.BindingConfig{
+		Name: "binding",
+		Properties: config.Metadata{
+			"validate_registry_dir":        dir,
+			"validate_channel_schemas":     `{"orders":"order"}`,
+			"validate_on_invalid":          "dead-letter",
+			"validate_dead_letter_channel": "invalid",
+			"validate_dead_letter_address": listener.Addr().String(),
+		},
+	}
+	b := NewBinder()
+	b.log = logger.NewLogger("binding")
+	b.validator, err = middleware.NewValidateMiddleware(cfg.Name, cfg.Properties, b.log)
+	require.NoError(t, err)
+	defer func() {
+		_ = b.validator.Close()
+	}()
+	b.script, err = middleware.NewScriptMiddleware(cfg.Properties, b.log)
+	require.NoError(t, err)
+	b.wasm, err = middleware.NewWasmMiddleware(ctx, cfg.Properties)
+	require.NoError(t, err)
+	b.enricher, err = middleware.NewEnrichMiddleware(cfg.Name, cfg.Properties, b.log)
+	require.NoError(t, err)
+	targets := []*mockTarget{{}, {}}
+	var chains []middleware.Middleware
+	for i, target := range targets {
+		md, err := b.buildMiddleware(target, i, cfg, nil)
+		require.NoError(t, err)
+		chains = append(chains, md)
+	}
+
+	// the source sends the same message to both targets, as a replicating source does
+	send := func(event *kubemq.Event) []error {
+		errs := make([]error, len(chains))
+		wg := sync.WaitGroup{}
+		for i, chain := range chains {
+			wg.Add(1)
+			go func(i int, chain middleware.Middleware) {
+				defer wg.Done()
+				_, errs[i] = chain.Do(ctx, event)
+			}(i, chain)
+		}
+		wg.Wait()
+		return errs
+	}
+	for _, err := range send(&kubemq.Event{Channel: "orders", Body: []byte(`{"name":"order"}`)}) {
+		require.NoError(t, err)
+	}
+	msg := <-deadLetters.messages
+	require.Equal(t, "invalid", msg.Channel)
+	require.Equal(t, `{"name":"order"}`, string(msg.Body))
+	for _, target := range targets {
+		require.EqualValues(t, 0, atomic.LoadInt32(&target.requests))
+	}
+
+	// another invalid message with the same body is dead-lettered on its own
+	for _, err := range send(&kubemq.Event{Channel: "orders", Body: []byte(`{"name":"order"}`)}) {
+		require.NoError(t, err)
+	}
+	<-deadLetters.messages
+	select {
+	case msg := <-deadLetters.messages:
+		require.Failf(t, "dead letter sent more than once", "body %s", string(msg.Body))
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	for _, err := range send(&kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`)}) {
+		require.NoError(t, err)
+	}
+	for _, target := range targets {
+		require.EqualValues(t, 1, atomic.LoadInt32(&target.requests))
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
 	"net/http"
 	"sync"
 	"time"
@@ -36,6 +39,9 @@ func New() (*Service, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to to initialized metrics exporter, %w", err)
 	}
+	if err := s.exporter.RegisterConnections(s.connectionReports); err != nil {
+		return nil, fmt.Errorf("failed to to initialized connections metrics, %w", err)
+	}
 	return s, nil
 }
 
@@ -104,10 +110,16 @@ func (s *Service) Add(ctx context.Context, cfg config.BindingConfig, logLevel st
 	s.bindingStatus.Store(cfg.Name, status)
 	err := binder.Init(ctx, cfg, s.exporter, logLevel)
 	if err != nil {
+		_ = binder.Stop()
+		status.Error = err.Error()
+		s.bindingStatus.Store(cfg.Name, status)
 		return err
 	}
 	err = binder.Start(ctx)
 	if err != nil {
+		_ = binder.Stop()
+		status.Error = err.Error()
+		s.bindingStatus.Store(cfg.Name, status)
 		return err
 	}
 	s.bindings.Store(cfg.Name, binder)
@@ -137,13 +149,67 @@ func (s *Service) PrometheusHandler() http.Handler {
 func (s *Service) Stats() []*metrics.Report {
 	return s.exporter.Store.List()
 }
+func (s *Service) Clients(ctx context.Context) []*pool.Stats {
+	return pool.List(ctx)
+}
+
+// Tap subscribes to every Nth message of a running binding stage
+func (s *Service) Tap(name, stage string, every, buffer int) (*tap.Subscriber, error) {
+	if _, ok := s.bindings.Load(name); !ok {
+		return nil, fmt.Errorf("binding %s not found", name)
+	}
+	return tap.Subscribe(name, stage, every, buffer), nil
+}
 func (s *Service) GetStatus() []*Status {
 	var list []*Status
 	for _, binding := range s.cfg.Bindings {
-		val, ok := s.bindingStatus.Load(binding.Name)
+		status, ok := s.getStatus(binding.Name)
 		if ok {
-			list = append(list, val.(*Status))
+			list = append(list, status)
 		}
 	}
 	return list
 }
+
+func (s *Service) getStatus(name string) (*Status, bool) {
+	val, ok := s.bindingStatus.Load(name)
+	if !ok {
+		return nil, false
+	}
+	var connections []*health.Status
+	if binder, ok := s.bindings.Load(name); ok {
+		connections = binder.(*Binder).Health()
+	}
+	return val.(*Status).withHealth(connections), true
+}
+
+func (s *Service) connectionReports() []*metrics.ConnectionReport {
+	var list []*metrics.ConnectionReport
+	s.bindings.Range(func(key, value interface{}) bool {
+		binder := value.(*Binder)
+		for _, connection := range binder.Health() {
+			list = append(list, &metrics.ConnectionReport{
+				Binding:    binder.name,
+				SourceKind: binder.sourceKind,
+				Connection: connection.Name,
+				Reconnects: connection.Reconnects,
+			})
+		}
+		return true
+	})
+	return list
+}
+
+// IsHealthy returns true when all the configured bindings are ready and none of their connections failed
+func (s *Service) IsHealthy() bool {
+	if s.cfg == nil {
+		return false
+	}
+	for _, binding := range s.cfg.Bindings {
+		status, ok := s.getStatus(binding.Name)
+		if !ok || !status.Healthy {
+			return false
+		}
+	}
+	return true
+}
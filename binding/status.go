@@ -2,11 +2,15 @@ package binding
 
 import (
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 )
 
 type Status struct {
 	Binding      string            `json:"binding"`
 	Ready        bool              `json:"ready"`
+	Healthy      bool              `json:"healthy"`
+	Error        string            `json:"error,omitempty"`
+	Connections  []*health.Status  `json:"connections"`
 	SourceType   string            `json:"source_type"`
 	SourceConfig []config.Metadata `json:"source_config"`
 	TargetType   string            `json:"target_type"`
@@ -23,3 +27,16 @@ func newStatus(cfg config.BindingConfig) *Status {
 		TargetConfig: cfg.Targets.Connections,
 	}
 }
+
+// withHealth returns a copy of the status with the current health of the binding connections, a binding is healthy when it is ready and none of its connections failed
+func (s *Status) withHealth(connections []*health.Status) *Status {
+	status := *s
+	status.Connections = connections
+	status.Healthy = status.Ready
+	for _, connection := range connections {
+		if !connection.Healthy() {
+			status.Healthy = false
+		}
+	}
+	return &status
+}
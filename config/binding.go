@@ -9,6 +9,7 @@ type BindingConfig struct {
 	Sources    Spec     `json:"sources"`
 	Targets    Spec     `json:"targets"`
 	Properties Metadata `json:"properties"`
+	file       string
 }
 
 func (b BindingConfig) Validate() error {
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+)
+
+const clusterKey = "cluster"
+
+// ClusterConfig is a named connection profile, bindings connections refer to it with the cluster key and override only their own fields
+type ClusterConfig struct {
+	Name       string   `json:"name"`
+	Connection Metadata `json:"connection"`
+	file       string
+}
+
+func (c ClusterConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("cluster must have name")
+	}
+	if _, ok := c.Connection[clusterKey]; ok {
+		return fmt.Errorf("cluster %s connection cannot refer to another cluster", c.Name)
+	}
+	return nil
+}
+
+// merge returns a new connection of the cluster profile values overridden by the connection values
+func (c ClusterConfig) merge(connection Metadata) Metadata {
+	merged := NewMetadata()
+	for key, value := range c.Connection {
+		merged[key] = value
+	}
+	for key, value := range connection {
+		merged[key] = value
+	}
+	return merged
+}
+
+func (c *Config) validateClusters() (map[string]ClusterConfig, error) {
+	clusters := map[string]ClusterConfig{}
+	for _, cluster := range c.Clusters {
+		if err := cluster.Validate(); err != nil {
+			return nil, err
+		}
+		if existed, ok := clusters[cluster.Name]; ok {
+			if existed.file != "" || cluster.file != "" {
+				return nil, fmt.Errorf("duplicated cluster names found: %s, defined in %s and in %s", cluster.Name, existed.file, cluster.file)
+			}
+			return nil, fmt.Errorf("duplicated cluster names found: %s", cluster.Name)
+		}
+		clusters[cluster.Name] = cluster
+	}
+	return clusters, nil
+}
+
+func resolveConnections(clusters map[string]ClusterConfig, connections []Metadata) error {
+	for i, connection := range connections {
+		name, ok := connection[clusterKey]
+		if !ok || name == "" {
+			continue
+		}
+		cluster, ok := clusters[name]
+		if !ok {
+			return fmt.Errorf("connection %d refers to unknown cluster %s", i, name)
+		}
+		connections[i] = cluster.merge(connection)
+	}
+	return nil
+}
+
+// resolveClusters merges the cluster profiles into every binding connection that refers to one
+func (c *Config) resolveClusters() error {
+	clusters, err := c.validateClusters()
+	if err != nil {
+		return err
+	}
+	for _, binding := range c.Bindings {
+		if err := resolveConnections(clusters, binding.Sources.Connections); err != nil {
+			return fmt.Errorf("binding %s sources error, %w", binding.Name, err)
+		}
+		if err := resolveConnections(clusters, binding.Targets.Connections); err != nil {
+			return fmt.Errorf("binding %s targets error, %w", binding.Name, err)
+		}
+	}
+	return nil
+}
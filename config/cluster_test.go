@@ -0,0 +1,137 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestConfig_ResolveClusters(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *Config
+		wantSources     []Metadata
+		wantTargets     []Metadata
+		wantErr         bool
+		wantErrContains string
+	}{
+		{
+			name: "merge cluster profiles",
+			cfg: &Config{
+				Clusters: []ClusterConfig{
+					{
+						Name: "dr",
+						Connection: Metadata{
+							"address":        "dr-cluster:50000",
+							"auth_token":     "token",
+							"auto_reconnect": "true",
+						},
+					},
+				},
+				Bindings: []BindingConfig{
+					{
+						Name: "b1",
+						Sources: Spec{
+							Kind: "source.events",
+							Connections: []Metadata{
+								{
+									"cluster": "dr",
+									"channel": "events.a",
+								},
+								{
+									"address": "localhost:50000",
+									"channel": "events.b",
+								},
+							},
+						},
+						Targets: Spec{
+							Kind: "target.events",
+							Connections: []Metadata{
+								{
+									"cluster":    "dr",
+									"channel":    "events.c",
+									"auth_token": "other-token",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantSources: []Metadata{
+				{
+					"cluster":        "dr",
+					"address":        "dr-cluster:50000",
+					"auth_token":     "token",
+					"auto_reconnect": "true",
+					"channel":        "events.a",
+				},
+				{
+					"address": "localhost:50000",
+					"channel": "events.b",
+				},
+			},
+			wantTargets: []Metadata{
+				{
+					"cluster":        "dr",
+					"address":        "dr-cluster:50000",
+					"auth_token":     "other-token",
+					"auto_reconnect": "true",
+					"channel":        "events.c",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown cluster",
+			cfg: &Config{
+				Bindings: []BindingConfig{
+					{
+						Name: "b1",
+						Sources: Spec{
+							Kind:        "source.events",
+							Connections: []Metadata{{"cluster": "dr", "channel": "events.a"}},
+						},
+						Targets: Spec{
+							Kind:        "target.events",
+							Connections: []Metadata{{"channel": "events.b"}},
+						},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "unknown cluster dr",
+		},
+		{
+			name: "duplicated clusters",
+			cfg: &Config{
+				Clusters: []ClusterConfig{
+					{Name: "dr", Connection: Metadata{"address": "a:50000"}, file: "a.yaml"},
+					{Name: "dr", Connection: Metadata{"address": "b:50000"}, file: "b.yaml"},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "b.yaml",
+		},
+		{
+			name: "cluster without name",
+			cfg: &Config{
+				Clusters: []ClusterConfig{
+					{Connection: Metadata{"address": "a:50000"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErrContains)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantSources, tt.cfg.Bindings[0].Sources.Connections)
+			require.EqualValues(t, tt.wantTargets, tt.cfg.Bindings[0].Targets.Connections)
+		})
+	}
+}
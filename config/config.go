@@ -5,17 +5,14 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"github.com/fsnotify/fsnotify"
 	"github.com/kubemq-io/kubemq-bridges/global"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/ghodss/yaml"
-	"github.com/spf13/viper"
 )
 
 const defaultApiPort = global.DefaultApiPort
@@ -28,6 +25,8 @@ type Config struct {
 	Bindings []BindingConfig `json:"bindings" json:"bindings"`
 	ApiPort  int             `json:"apiPort" yaml:"apiPort"`
 	LogLevel string          `json:"logLevel" yaml:"logLevel"`
+	Include  []string        `json:"include" yaml:"include"`
+	Clusters []ClusterConfig `json:"clusters" yaml:"clusters"`
 }
 
 func SetConfigFile(filename string) {
@@ -53,15 +52,27 @@ func (c *Config) Validate() error {
 	if c.ApiPort == 0 {
 		c.ApiPort = defaultApiPort
 	}
-	exitedBindings := map[string]string{}
+	if err := c.resolveClusters(); err != nil {
+		return err
+	}
 	for _, binding := range c.Bindings {
 		if err := binding.Validate(); err != nil {
 			return err
 		}
-		if _, ok := exitedBindings[binding.Name]; ok {
+	}
+	return c.validateDuplications()
+}
+
+func (c *Config) validateDuplications() error {
+	exitedBindings := map[string]BindingConfig{}
+	for _, binding := range c.Bindings {
+		if existed, ok := exitedBindings[binding.Name]; ok {
+			if existed.file != "" || binding.file != "" {
+				return fmt.Errorf("duplicated binding names found: %s, defined in %s and in %s", binding.Name, existed.file, binding.file)
+			}
 			return fmt.Errorf("duplicated binding names found: %s", binding.Name)
 		} else {
-			exitedBindings[binding.Name] = binding.Name
+			exitedBindings[binding.Name] = binding
 		}
 	}
 	return nil
@@ -139,52 +150,48 @@ func getConfigFile() (string, error) {
 	}
 }
 
-func load() (*Config, error) {
-	path, err := os.Executable()
+func load() (*Config, *fileLoader, error) {
+	paths, err := rootPaths()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	loadedConfigFile, err := getConfigFile()
-	if err != nil {
-		return nil, err
-	} else {
-		viper.SetConfigFile(filepath.Join(filepath.Dir(path), loadedConfigFile))
-	}
-	err = viper.ReadInConfig()
-	if err != nil {
-		return nil, err
+	loader := newFileLoader()
+	for _, path := range paths {
+		if err := loader.loadPath(path); err != nil {
+			return nil, nil, err
+		}
 	}
-	cfg := &Config{}
-	err = viper.Unmarshal(cfg)
-	if err != nil {
-		return nil, err
+	cfg := loader.cfg
+	if err := cfg.validateDuplications(); err != nil {
+		return nil, nil, err
 	}
-	logr.Infof("%d bindings loaded", len(cfg.Bindings))
-	return cfg, err
+	logr.Infof("%d bindings loaded from %d config files", len(cfg.Bindings), len(loader.files))
+	return cfg, loader, nil
 }
 
 func Load(cfgCh chan *Config) (*Config, error) {
-	path, err := os.Executable()
-	if err != nil {
-		return nil, err
-	}
-	viper.AddConfigPath(filepath.Dir(path))
-	cfg, err := load()
+	cfg, loader, err := load()
 	if err != nil {
 		return nil, err
 	}
-	viper.WatchConfig()
-	viper.OnConfigChange(func(e fsnotify.Event) {
-		cfg, err := load()
+	lastConf = cfg.copy()
+	var w *watcher
+	w, err = newWatcher(func() {
+		cfg, loader, err := load()
 		if err != nil {
 			logr.Errorf("error loading new configuration file: %s", err.Error())
 			return
 		}
+		w.watch(loader.files, loader.dirs)
 		if cfg.hash() != lastConf.hash() {
 			logr.Info("config file changed, reloading...")
 			lastConf = cfg.copy()
 			cfgCh <- cfg
 		}
 	})
-	return cfg, err
+	if err != nil {
+		return nil, err
+	}
+	w.watch(loader.files, loader.dirs)
+	return cfg, nil
 }
@@ -0,0 +1,33 @@
+package config
+
+const (
+	PropertyKindString = "string"
+	PropertyKindInt    = "int"
+	PropertyKindBool   = "bool"
+	PropertyKindList   = "list"
+	PropertyKindMap    = "map"
+)
+
+// Property describes a single key of a connection or of binding properties
+type Property struct {
+	Name        string
+	Kind        string
+	Description string
+	Default     string
+	Required    bool
+	Options     []string
+	Min         int
+	Max         int
+}
+
+// Connector describes a source or target kind and its connection keys
+type Connector struct {
+	Kind        string
+	Aliases     []string
+	Description string
+	Properties  []*Property
+}
+
+func (c *Connector) Kinds() []string {
+	return append([]string{c.Kind}, c.Aliases...)
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var configExtensions = []string{".yaml", ".yml", ".json"}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+func isConfigExtension(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, configExt := range configExtensions {
+		if ext == configExt {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns an absolute path of name, relative paths are resolved against base directory
+func resolvePath(base, name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(base, name)
+}
+
+func executableDir() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+// expandPath returns the config files of a path, which can be a file, a directory or a glob pattern
+func expandPath(path string) ([]string, error) {
+	if isGlob(path) {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config glob pattern %s, %w", path, err)
+		}
+		var files []string
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || info.IsDir() || !isConfigExtension(match) {
+				continue
+			}
+			files = append(files, match)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no config files found for pattern %s", path)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !isConfigExtension(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files found in directory %s", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// rootPaths returns the paths the configuration is loaded from, a directory or a glob config are used as is, otherwise the single config file is located as before
+func rootPaths() ([]string, error) {
+	dir, err := executableDir()
+	if err != nil {
+		return nil, err
+	}
+	if configFile != "" {
+		if isGlob(configFile) {
+			return []string{resolvePath(dir, configFile)}, nil
+		}
+		path := resolvePath(dir, configFile)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return []string{path}, nil
+		}
+	}
+	loadedConfigFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return []string{resolvePath(dir, loadedConfigFile)}, nil
+}
+
+type fileLoader struct {
+	cfg    *Config
+	loaded map[string]bool
+	files  []string
+	dirs   []string
+}
+
+func newFileLoader() *fileLoader {
+	return &fileLoader{
+		cfg:    &Config{},
+		loaded: map[string]bool{},
+	}
+}
+
+func (l *fileLoader) loadPath(path string) error {
+	if isGlob(path) {
+		l.dirs = append(l.dirs, filepath.Dir(path))
+	} else if info, err := os.Stat(path); err == nil && info.IsDir() {
+		l.dirs = append(l.dirs, path)
+	}
+	files, err := expandPath(path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := l.loadFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *fileLoader) loadFile(file string) error {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	if l.loaded[file] {
+		return nil
+	}
+	l.loaded[file] = true
+	l.files = append(l.files, file)
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("error reading config file %s, %w", file, err)
+	}
+	part := &Config{}
+	if err := v.Unmarshal(part); err != nil {
+		return fmt.Errorf("error parsing config file %s, %w", file, err)
+	}
+	if l.cfg.ApiPort == 0 {
+		l.cfg.ApiPort = part.ApiPort
+	}
+	if l.cfg.LogLevel == "" {
+		l.cfg.LogLevel = part.LogLevel
+	}
+	for _, cluster := range part.Clusters {
+		cluster.file = file
+		l.cfg.Clusters = append(l.cfg.Clusters, cluster)
+	}
+	for _, binding := range part.Bindings {
+		binding.file = file
+		l.cfg.Bindings = append(l.cfg.Bindings, binding)
+	}
+	for _, include := range part.Include {
+		if err := l.loadPath(resolvePath(filepath.Dir(file), include)); err != nil {
+			return fmt.Errorf("error loading include %s of config file %s, %w", include, file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func bindingYaml(names ...string) string {
+	str := "bindings:\n"
+	for _, name := range names {
+		str += `  - name: ` + name + `
+    sources:
+      kind: source.events
+      connections:
+        - address: localhost:50000
+          channel: events
+    targets:
+      kind: target.events
+      connections:
+        - address: localhost:50000
+          channel: events.target
+`
+	}
+	return str
+}
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestLoad_MultiFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		config       string
+		wantBindings []string
+		wantApiPort  int
+		wantClusters []ClusterConfig
+		wantErr      string
+	}{
+		{
+			name: "single file",
+			files: map[string]string{
+				"config.yaml": "apiPort: 9000\n" + bindingYaml("b1", "b2"),
+			},
+			config:       "config.yaml",
+			wantBindings: []string{"b1", "b2"},
+			wantApiPort:  9000,
+		},
+		{
+			name: "directory",
+			files: map[string]string{
+				"conf.d/a.yaml":    "apiPort: 9001\n" + bindingYaml("b1"),
+				"conf.d/b.yml":     bindingYaml("b2"),
+				"conf.d/c.json":    `{"bindings":[]}`,
+				"conf.d/readme.md": "not a config",
+			},
+			config:       "conf.d",
+			wantBindings: []string{"b1", "b2"},
+			wantApiPort:  9001,
+		},
+		{
+			name: "glob",
+			files: map[string]string{
+				"teams/a-bindings.yaml": bindingYaml("b1"),
+				"teams/b-bindings.yaml": bindingYaml("b2"),
+				"teams/other.yaml":      bindingYaml("b3"),
+			},
+			config:       "teams/*-bindings.yaml",
+			wantBindings: []string{"b1", "b2"},
+		},
+		{
+			name: "include directives",
+			files: map[string]string{
+				"config.yaml":        "include:\n  - teams/*.yaml\n  - shared.yaml\n" + bindingYaml("root"),
+				"teams/a.yaml":       bindingYaml("a"),
+				"teams/b.yaml":       "include:\n  - ../nested\n" + bindingYaml("b"),
+				"nested/nested.yaml": bindingYaml("nested"),
+				"shared.yaml":        "include:\n  - config.yaml\n" + bindingYaml("shared"),
+			},
+			config:       "config.yaml",
+			wantBindings: []string{"root", "a", "b", "nested", "shared"},
+		},
+		{
+			name: "clusters",
+			files: map[string]string{
+				"config.yaml":   "include:\n  - clusters.yaml\n" + bindingYaml("b1"),
+				"clusters.yaml": "clusters:\n  - name: DR-Cluster\n    connection:\n      address: dr:50000\n      max_reconnects: 10\n",
+			},
+			config:       "config.yaml",
+			wantBindings: []string{"b1"},
+			wantClusters: []ClusterConfig{
+				{
+					Name: "DR-Cluster",
+					Connection: Metadata{
+						"address":        "dr:50000",
+						"max_reconnects": "10",
+					},
+				},
+			},
+		},
+		{
+			name: "duplicated bindings across files",
+			files: map[string]string{
+				"conf.d/a.yaml": bindingYaml("b1"),
+				"conf.d/b.yaml": bindingYaml("b1"),
+			},
+			config:  "conf.d",
+			wantErr: "b.yaml",
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"config.yaml": "include:\n  - missing.yaml\n" + bindingYaml("b1"),
+			},
+			config:  "config.yaml",
+			wantErr: "missing.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+			SetConfigFile(filepath.Join(dir, tt.config))
+			defer SetConfigFile("")
+			cfg, _, err := load()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, binding := range cfg.Bindings {
+				names = append(names, binding.Name)
+			}
+			require.EqualValues(t, tt.wantBindings, names)
+			require.EqualValues(t, tt.wantApiPort, cfg.ApiPort)
+			for i, cluster := range tt.wantClusters {
+				require.EqualValues(t, cluster.Name, cfg.Clusters[i].Name)
+				require.EqualValues(t, cluster.Connection, cfg.Clusters[i].Connection)
+			}
+			require.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+func TestLoad_WatchIncludedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"config.yaml":  "include:\n  - teams\n" + bindingYaml("root"),
+		"teams/a.yaml": bindingYaml("a"),
+	})
+	SetConfigFile(filepath.Join(dir, "config.yaml"))
+	defer SetConfigFile("")
+	cfgCh := make(chan *Config, 1)
+	cfg, err := Load(cfgCh)
+	require.NoError(t, err)
+	require.Len(t, cfg.Bindings, 2)
+	writeConfigFiles(t, dir, map[string]string{
+		"teams/b.yaml": bindingYaml("b"),
+	})
+	select {
+	case newCfg := <-cfgCh:
+		require.Len(t, newCfg.Bindings, 3)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "config change was not detected")
+	}
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	encodingjson "encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+type schemaObject map[string]interface{}
+
+// Schema builds a json schema document of the bridges configuration file, including connection keys per each sources and targets kind
+func Schema(sources, targets []*Connector, properties []*Property) schemaObject {
+	definitions := schemaObject{
+		"binding":    bindingSchema(),
+		"cluster":    clusterSchema(),
+		"properties": propertiesSchema(properties),
+		"sources":    specSchema("sources", sources),
+		"targets":    specSchema("targets", targets),
+	}
+	for _, connector := range append(append([]*Connector{}, sources...), targets...) {
+		definitions[connector.Kind] = connectionSchema(connector)
+	}
+	return schemaObject{
+		"$schema":     schemaDraft,
+		"title":       "KubeMQ Bridges Configuration",
+		"description": "kubemq bridges bindings configuration file",
+		"type":        "object",
+		"properties": schemaObject{
+			"apiPort": schemaObject{
+				"description": "kubemq bridges api and health end-point port",
+				"type":        []string{"integer", "string"},
+				"default":     defaultApiPort,
+			},
+			"logLevel": schemaObject{
+				"description": "bridges service log level",
+				"type":        "string",
+				"enum":        []string{"debug", "info", "error", ""},
+			},
+			"bindings": schemaObject{
+				"description": "list of bindings between sources and targets",
+				"type":        "array",
+				"items":       ref("binding"),
+			},
+			"clusters": schemaObject{
+				"description": "list of named connection profiles, referred by connections with the cluster key",
+				"type":        "array",
+				"items":       ref("cluster"),
+			},
+			"include": schemaObject{
+				"description": "list of config files, directories or glob patterns to merge bindings from, relative to this file",
+				"type":        "array",
+				"items": schemaObject{
+					"type": "string",
+				},
+			},
+		},
+		"definitions": definitions,
+	}
+}
+
+// SchemaJSON returns the bridges configuration json schema as indented json
+func SchemaJSON(sources, targets []*Connector, properties []*Property) ([]byte, error) {
+	data, err := json.Marshal(Schema(sources, targets, properties))
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := encodingjson.Indent(buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func ref(name string) schemaObject {
+	return schemaObject{"$ref": fmt.Sprintf("#/definitions/%s", name)}
+}
+
+func bindingSchema() schemaObject {
+	return schemaObject{
+		"type":     "object",
+		"required": []string{"name", "sources", "targets"},
+		"properties": schemaObject{
+			"name": schemaObject{
+				"description": "unique binding name",
+				"type":        "string",
+				"minLength":   1,
+			},
+			"properties": ref("properties"),
+			"sources":    ref("sources"),
+			"targets":    ref("targets"),
+		},
+		"additionalProperties": false,
+	}
+}
+
+func clusterSchema() schemaObject {
+	return schemaObject{
+		"type":     "object",
+		"required": []string{"name", "connection"},
+		"properties": schemaObject{
+			"name": schemaObject{
+				"description": "unique cluster profile name",
+				"type":        "string",
+				"minLength":   1,
+			},
+			"connection": schemaObject{
+				"description": "connection properties shared by all the connections referring to this cluster, such as address, auth_token, client_id and reconnect settings",
+				"type":        "object",
+				"additionalProperties": schemaObject{
+					"type": []string{"string", "integer", "boolean"},
+				},
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+func propertiesSchema(properties []*Property) schemaObject {
+	return schemaObject{
+		"description":          "binding properties such middleware configurations",
+		"type":                 []string{"object", "null"},
+		"properties":           propertiesMap(properties),
+		"additionalProperties": false,
+	}
+}
+
+func specSchema(name string, connectors []*Connector) schemaObject {
+	var kinds []string
+	var conditions []schemaObject
+	for _, connector := range connectors {
+		kinds = append(kinds, connector.Kinds()...)
+		conditions = append(conditions, schemaObject{
+			"if": schemaObject{
+				"properties": schemaObject{
+					"kind": schemaObject{"enum": connector.Kinds()},
+				},
+			},
+			"then": schemaObject{
+				"properties": schemaObject{
+					"connections": schemaObject{
+						"items": ref(connector.Kind),
+					},
+				},
+			},
+		})
+	}
+	return schemaObject{
+		"type":     "object",
+		"required": []string{"kind", "connections"},
+		"properties": schemaObject{
+			"kind": schemaObject{
+				"description": fmt.Sprintf("%s kind type", name),
+				"type":        "string",
+				"enum":        kinds,
+			},
+			"name": schemaObject{
+				"description": fmt.Sprintf("%s name (will show up in logs)", name),
+				"type":        "string",
+			},
+			"connections": schemaObject{
+				"description": fmt.Sprintf("an array of connection properties for each of the %s", name),
+				"type":        "array",
+				"minItems":    1,
+				"items": schemaObject{
+					"type": "object",
+				},
+			},
+		},
+		"allOf":                conditions,
+		"additionalProperties": false,
+	}
+}
+
+func connectionSchema(connector *Connector) schemaObject {
+	properties := propertiesMap(connector.Properties)
+	properties[clusterKey] = schemaObject{
+		"description": "name of a cluster connection profile to merge this connection with",
+		"type":        "string",
+	}
+	s := schemaObject{
+		"description":          connector.Description,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	var required []string
+	for _, property := range connector.Properties {
+		if property.Required {
+			required = append(required, property.Name)
+		}
+	}
+	if len(required) > 0 {
+		// required keys can be set by the referred cluster profile
+		s["anyOf"] = []schemaObject{
+			{"required": []string{clusterKey}},
+			{"required": required},
+		}
+	}
+	return s
+}
+
+func propertiesMap(properties []*Property) schemaObject {
+	m := schemaObject{}
+	for _, property := range properties {
+		m[property.Name] = propertySchema(property)
+	}
+	return m
+}
+
+func propertySchema(p *Property) schemaObject {
+	s := schemaObject{
+		"description": p.Description,
+	}
+	switch p.Kind {
+	case PropertyKindInt:
+		// yaml numbers and quoted numbers are both accepted by the config loader
+		s["type"] = []string{"integer", "string"}
+		s["pattern"] = "^-?[0-9]+$"
+		if p.Max > p.Min {
+			s["minimum"] = p.Min
+			s["maximum"] = p.Max
+		}
+		if p.Default != "" {
+			if val, err := strconv.Atoi(p.Default); err == nil {
+				s["default"] = val
+			}
+		}
+	case PropertyKindBool:
+		s["type"] = []string{"boolean", "string"}
+		s["enum"] = []interface{}{true, false, "true", "false"}
+		if p.Default != "" {
+			if val, err := strconv.ParseBool(p.Default); err == nil {
+				s["default"] = val
+			}
+		}
+	default:
+		s["type"] = "string"
+		if p.Required {
+			s["minLength"] = 1
+		}
+		if len(p.Options) > 0 {
+			s["enum"] = p.Options
+		}
+		if p.Default != "" {
+			s["default"] = p.Default
+		}
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	source := &Connector{
+		Kind:        "source.test",
+		Aliases:     []string{"kubemq.test"},
+		Description: "test source",
+		Properties: []*Property{
+			{
+				Name:     "channel",
+				Kind:     PropertyKindString,
+				Required: true,
+			},
+			{
+				Name:    "sources",
+				Kind:    PropertyKindInt,
+				Default: "1",
+				Min:     1,
+				Max:     10,
+			},
+			{
+				Name:    "auto_reconnect",
+				Kind:    PropertyKindBool,
+				Default: "true",
+			},
+		},
+	}
+	target := &Connector{
+		Kind:        "target.test",
+		Description: "test target",
+	}
+	properties := []*Property{
+		{
+			Name:    "log_level",
+			Kind:    PropertyKindString,
+			Options: []string{"debug", "info"},
+		},
+	}
+	s := Schema([]*Connector{source}, []*Connector{target}, properties)
+	definitions := s["definitions"].(schemaObject)
+	require.Contains(t, definitions, "source.test")
+	require.Contains(t, definitions, "target.test")
+
+	conn := definitions["source.test"].(schemaObject)
+	require.EqualValues(t, []schemaObject{{"required": []string{"cluster"}}, {"required": []string{"channel"}}}, conn["anyOf"])
+	connProperties := conn["properties"].(schemaObject)
+	require.Contains(t, connProperties, "cluster")
+	require.EqualValues(t, 1, connProperties["sources"].(schemaObject)["default"])
+	require.EqualValues(t, 10, connProperties["sources"].(schemaObject)["maximum"])
+	require.EqualValues(t, true, connProperties["auto_reconnect"].(schemaObject)["default"])
+
+	sources := definitions["sources"].(schemaObject)
+	kinds := sources["properties"].(schemaObject)["kind"].(schemaObject)["enum"]
+	require.EqualValues(t, []string{"source.test", "kubemq.test"}, kinds)
+	require.Len(t, sources["allOf"], 1)
+
+	bindingProperties := definitions["properties"].(schemaObject)["properties"].(schemaObject)
+	require.EqualValues(t, []string{"debug", "info"}, bindingProperties["log_level"].(schemaObject)["enum"])
+
+	data, err := SchemaJSON([]*Connector{source}, []*Connector{target}, properties)
+	require.NoError(t, err)
+	require.NotEmpty(t, data)
+}
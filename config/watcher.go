@@ -0,0 +1,96 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const watchDebounce = 200 * time.Millisecond
+
+// watcher watches the directories of all loaded config files and calls onChange when any of them changes
+type watcher struct {
+	sync.Mutex
+	fsWatcher *fsnotify.Watcher
+	dirs      map[string]bool
+	files     map[string]bool
+	timer     *time.Timer
+	onChange  func()
+}
+
+func newWatcher(onChange func()) (*watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &watcher{
+		fsWatcher: fsWatcher,
+		dirs:      map[string]bool{},
+		files:     map[string]bool{},
+		onChange:  onChange,
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *watcher) watch(files, dirs []string) {
+	w.Lock()
+	defer w.Unlock()
+	w.files = map[string]bool{}
+	for _, file := range files {
+		w.files[file] = true
+		dirs = append(dirs, filepath.Dir(file))
+	}
+	for _, dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsWatcher.Add(dir); err != nil {
+			logr.Errorf("error watching config directory %s: %s", dir, err.Error())
+			continue
+		}
+		w.dirs[dir] = true
+	}
+}
+
+func (w *watcher) isRelevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	w.Lock()
+	defer w.Unlock()
+	name := filepath.Clean(event.Name)
+	// kubernetes config maps are updated by swapping the ..data symlink
+	if w.files[name] || isConfigExtension(name) || strings.HasPrefix(filepath.Base(name), "..") {
+		return true
+	}
+	return false
+}
+
+func (w *watcher) run() {
+	for {
+		select {
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if !w.isRelevant(event) {
+				continue
+			}
+			w.Lock()
+			if w.timer != nil {
+				w.timer.Stop()
+			}
+			w.timer = time.AfterFunc(watchDebounce, w.onChange)
+			w.Unlock()
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			logr.Errorf("config watcher error: %s", err.Error())
+		}
+	}
+}
@@ -29,6 +29,7 @@ var (
 	build       = flag.Bool("build", false, "build bridges configuration")
 	buildUrl    = flag.String("get", "", "get config file from url")
 	configFile  = flag.String("config", "config.yaml", "set config file name")
+	schema      = flag.Bool("schema", false, "print bridges configuration json schema")
 	svcFlag     = flag.String("service", "", "control the app service")
 	svcUsername = flag.String("username", "", "kubemq-targets service username")
 	svcPassword = flag.String("password", "", "kubemq-targets service password")
@@ -140,6 +141,13 @@ func preRun() {
 func main() {
 	log = logger.NewLogger("kubemq-bridges")
 	flag.Parse()
+	if *schema {
+		if err := printSchema(); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	config.SetConfigFile(*configFile)
 	app := newAppService()
 	if err := app.init(*svcFlag, *svcUsername, *svcPassword); err != nil {
@@ -23,6 +23,7 @@ var (
 var (
 	log        *logger.Logger
 	configFile = flag.String("config", "config.yaml", "set config file name")
+	schema     = flag.Bool("schema", false, "print bridges configuration json schema")
 )
 
 func run() error {
@@ -89,6 +90,13 @@ func run() error {
 func main() {
 	log = logger.NewLogger("kubemq-bridges")
 	flag.Parse()
+	if *schema {
+		if err := printSchema(); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	config.SetConfigFile(*configFile)
 	log.Infof("starting kubemq bridges connector version: %s", version)
 	if err := run(); err != nil {
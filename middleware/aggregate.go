@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/batch"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+var batchFormatMap = map[string]string{
+	"":                "",
+	batch.FormatJSON:  batch.FormatJSON,
+	batch.FormatLines: batch.FormatLines,
+}
+
+// pendingBatch is a batch of requests waiting to be sent, its callers wait for done to get the batch response
+type pendingBatch struct {
+	requests []interface{}
+	timer    *time.Timer
+	done     chan struct{}
+	resp     interface{}
+	err      error
+}
+
+type AggregateMiddleware struct {
+	sync.Mutex
+	format      string
+	count       int
+	window      time.Duration
+	wait        bool
+	tag         string
+	splitFormat string
+	next        Middleware
+	current     *pendingBatch
+	sending     sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+	log         *logger.Logger
+}
+
+func NewAggregateMiddleware(meta config.Metadata, log *logger.Logger) (*AggregateMiddleware, error) {
+	var err error
+	a := &AggregateMiddleware{
+		tag:  meta.ParseString("aggregate_tag", "batch-size"),
+		wait: meta.ParseBool("aggregate_wait", true),
+		log:  log,
+	}
+	a.format, err = meta.ParseStringMap("aggregate_format", batchFormatMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate format value, %w", err)
+	}
+	a.count, err = meta.ParseIntWithRange("aggregate_count", 100, 1, 1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate count value, %w", err)
+	}
+	window, err := meta.ParseIntWithRange("aggregate_window_milliseconds", 1000, 1, 60*60*1000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate window milliseconds value, %w", err)
+	}
+	a.window = time.Duration(window) * time.Millisecond
+	a.splitFormat, err = meta.ParseStringMap("split_format", batchFormatMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid split format value, %w", err)
+	}
+	// batches are sent on the middleware context, as the context of the request which opened a batch may end before it is sent
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+	return a, nil
+}
+
+// AggregateWaits returns true when the binding properties aggregate the messages and wait for their batches to be sent,
+// sources which process one message at a time send them concurrently or reject the aggregation
+func AggregateWaits(meta config.Metadata) bool {
+	return meta.ParseString("aggregate_format", "") != "" && meta.ParseBool("aggregate_wait", true)
+}
+
+// add adds the request to the current batch, which is sent when it is full or when its window ends
+func (a *AggregateMiddleware) add(ctx context.Context, request interface{}) (interface{}, error) {
+	if _, ok := message.Body(request); !ok {
+		return nil, fmt.Errorf("aggregation is not supported for message type %T", request)
+	}
+	a.Lock()
+	b := a.current
+	if b == nil {
+		b = &pendingBatch{
+			done: make(chan struct{}),
+		}
+		b.timer = time.AfterFunc(a.window, func() { a.flush(b) })
+		a.current = b
+	}
+	b.requests = append(b.requests, request)
+	if len(b.requests) >= a.count {
+		b.timer.Stop()
+		a.current = nil
+		a.sending.Add(1)
+		go a.send(b)
+	}
+	a.Unlock()
+	if !a.wait {
+		return nil, nil
+	}
+	select {
+	case <-b.done:
+		return b.resp, b.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush sends the batch when it is still the current batch
+func (a *AggregateMiddleware) flush(b *pendingBatch) {
+	a.Lock()
+	if a.current != b {
+		a.Unlock()
+		return
+	}
+	a.current = nil
+	a.sending.Add(1)
+	a.Unlock()
+	a.send(b)
+}
+
+// send sends the batch request, built from the first request with the joined bodies, and releases the batch callers
+func (a *AggregateMiddleware) send(b *pendingBatch) {
+	defer a.sending.Done()
+	defer close(b.done)
+	bodies := make([][]byte, len(b.requests))
+	for i, request := range b.requests {
+		bodies[i], _ = message.Body(request)
+	}
+	body, err := batch.Join(a.format, bodies)
+	if err != nil {
+		b.err = fmt.Errorf("error joining batch bodies, %w", err)
+	} else {
+		tags := message.CopyTags(message.Tags(b.requests[0]))
+		tags[a.tag] = strconv.Itoa(len(b.requests))
+		var request interface{}
+		if request, b.err = message.Clone(b.requests[0], body, tags); b.err == nil {
+			b.resp, b.err = a.next.Do(a.ctx, request)
+		}
+	}
+	if b.err != nil && !a.wait {
+		a.log.Errorf("error sending batch of %d messages, %s", len(b.requests), b.err.Error())
+	}
+}
+
+// split returns a request for each item of the request body
+func (a *AggregateMiddleware) split(ctx context.Context, request interface{}) ([]interface{}, error) {
+	body, ok := message.Body(request)
+	if !ok {
+		return nil, fmt.Errorf("splitting is not supported for message type %T", request)
+	}
+	bodies, err := batch.Split(a.splitFormat, body)
+	if err != nil {
+		return nil, err
+	}
+	tags := message.CopyTags(message.Tags(request))
+	delete(tags, a.tag)
+	var requests []interface{}
+	for _, item := range bodies {
+		replaced, err := message.Clone(request, item, message.CopyTags(tags))
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, replaced)
+	}
+	return requests, nil
+}
+
+// Close sends the current batch, waits for the batches being sent and cancels the middleware context
+func (a *AggregateMiddleware) Close() {
+	a.Lock()
+	b := a.current
+	a.Unlock()
+	if b != nil {
+		a.flush(b)
+	}
+	a.sending.Wait()
+	a.cancel()
+}
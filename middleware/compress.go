@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/compress"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+const (
+	defaultDecompressMaxSize = 64 * 1024 * 1024
+	// defaultCompressTag is namespaced, so the generic tags of the messages are not taken for a compression codec
+	defaultCompressTag = "kubemq-bridges-compression"
+)
+
+type CompressMiddleware struct {
+	codec      compress.Codec
+	minSize    int
+	tag        string
+	decompress bool
+	codecs     map[string]compress.Codec
+}
+
+func NewCompressMiddleware(meta config.Metadata) (*CompressMiddleware, error) {
+	var err error
+	c := &CompressMiddleware{
+		tag:        meta.ParseString("compress_tag", defaultCompressTag),
+		decompress: meta.ParseBool("decompress", true),
+		codecs:     map[string]compress.Codec{},
+	}
+	c.minSize, err = meta.ParseIntWithRange("compress_min_size", 1024, 0, 1024*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compress min size value, %w", err)
+	}
+	maxSize, err := meta.ParseIntWithRange("decompress_max_size", defaultDecompressMaxSize, 1, 1024*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid decompress max size value, %w", err)
+	}
+	for _, name := range compress.Codecs {
+		c.codecs[name], err = compress.New(name, maxSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if name := meta.ParseString("compress_codec", ""); name != "" {
+		codec, ok := c.codecs[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid compress codec value %s, supported codecs are %s", name, strings.Join(compress.Codecs, ", "))
+		}
+		c.codec = codec
+	}
+	return c, nil
+}
+
+// compressRequest returns a copy of the request with the body compressed and the codec set in the tag,
+// requests which are small, already compressed or do not shrink are returned as is
+func (c *CompressMiddleware) compressRequest(request interface{}) (interface{}, error) {
+	body, ok := message.Body(request)
+	if !ok || len(body) < c.minSize {
+		return request, nil
+	}
+	tags := message.Tags(request)
+	if tags[c.tag] != "" {
+		return request, nil
+	}
+	compressed, err := c.codec.Compress(body)
+	if err != nil {
+		return nil, fmt.Errorf("error compressing message body, %w", err)
+	}
+	if len(compressed) >= len(body) {
+		return request, nil
+	}
+	tags = message.CopyTags(tags)
+	tags[c.tag] = c.codec.Name()
+	return message.Clone(request, compressed, tags)
+}
+
+// decompressRequest returns a copy of the request with the body decompressed by the codec set in the tag, and the tag removed,
+// requests which tag is not set to a known codec are returned as is
+func (c *CompressMiddleware) decompressRequest(request interface{}) (interface{}, error) {
+	tags := message.Tags(request)
+	name := tags[c.tag]
+	codec, ok := c.codecs[name]
+	if !ok {
+		return request, nil
+	}
+	body, ok := message.Body(request)
+	if !ok {
+		return request, nil
+	}
+	decompressed, err := codec.Decompress(body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing %s message body, %w", name, err)
+	}
+	tags = message.CopyTags(tags)
+	delete(tags, c.tag)
+	return message.Clone(request, decompressed, tags)
+}
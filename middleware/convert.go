@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/convert"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+type ConvertMiddleware struct {
+	request  *convert.Converter
+	response *convert.Converter
+}
+
+func NewConvertMiddleware(meta config.Metadata) (*ConvertMiddleware, error) {
+	sourceFormat := meta.ParseString("convert_source_format", "")
+	targetFormat := meta.ParseString("convert_target_format", "")
+	if sourceFormat == "" && targetFormat == "" {
+		return &ConvertMiddleware{}, nil
+	}
+	if sourceFormat == "" || targetFormat == "" {
+		return nil, fmt.Errorf("invalid convert formats, both source and target formats must be set")
+	}
+	var importPaths []string
+	for _, path := range meta.ParseStringList("convert_import_paths") {
+		if path = strings.TrimSpace(path); path != "" {
+			importPaths = append(importPaths, path)
+		}
+	}
+	from, err := convert.NewCodec(sourceFormat, convert.Schema{
+		File:        meta.ParseString("convert_source_schema", ""),
+		MessageType: meta.ParseString("convert_source_message_type", ""),
+		ImportPaths: importPaths,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid convert source format, %w", err)
+	}
+	to, err := convert.NewCodec(targetFormat, convert.Schema{
+		File:        meta.ParseString("convert_target_schema", ""),
+		MessageType: meta.ParseString("convert_target_message_type", ""),
+		ImportPaths: importPaths,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid convert target format, %w", err)
+	}
+	request := convert.NewConverter(from, to)
+	return &ConvertMiddleware{
+		request:  request,
+		response: request.Reverse(),
+	}, nil
+}
+
+// convertRequest returns a copy of the request with the body converted to the target format
+func (c *ConvertMiddleware) convertRequest(request interface{}) (interface{}, error) {
+	body, ok := message.Body(request)
+	if !ok {
+		return nil, fmt.Errorf("convert is not supported for message type %T", request)
+	}
+	if len(body) == 0 {
+		return request, nil
+	}
+	converted, err := c.request.Convert(body)
+	if err != nil {
+		return nil, fmt.Errorf("error converting message body, %w", err)
+	}
+	return message.Clone(request, converted, message.Tags(request))
+}
+
+// convertResponse converts a query response body back to the source format
+func (c *ConvertMiddleware) convertResponse(response interface{}) (interface{}, error) {
+	queryResponse, ok := response.(*kubemq.QueryResponse)
+	if !ok || queryResponse == nil || len(queryResponse.Body) == 0 {
+		return response, nil
+	}
+	converted, err := c.response.Convert(queryResponse.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error converting query response body, %w", err)
+	}
+	return message.Clone(queryResponse, converted, queryResponse.Tags)
+}
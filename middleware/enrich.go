@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/targets/query"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+const (
+	enrichMergeTags = "tags"
+	enrichMergeBody = "body"
+
+	enrichOnErrorReject = "reject"
+	enrichOnErrorSkip   = "skip"
+)
+
+var enrichMergeMap = map[string]string{
+	"":              enrichMergeTags,
+	enrichMergeTags: enrichMergeTags,
+	enrichMergeBody: enrichMergeBody,
+}
+
+var enrichOnErrorMap = map[string]string{
+	"":                  enrichOnErrorReject,
+	enrichOnErrorReject: enrichOnErrorReject,
+	enrichOnErrorSkip:   enrichOnErrorSkip,
+}
+
+type EnrichMiddleware struct {
+	sync.Mutex
+	key        func(request interface{}) ([]byte, bool, error)
+	merge      string
+	tagPrefix  string
+	bodyField  string
+	onError    string
+	cache      *cache.Cache
+	connection config.Metadata
+	name       string
+	client     *query.Client
+	lookup     func(ctx context.Context, key []byte) (*kubemq.QueryResponse, error)
+	log        *logger.Logger
+}
+
+func NewEnrichMiddleware(name string, meta config.Metadata, log *logger.Logger) (*EnrichMiddleware, error) {
+	e := &EnrichMiddleware{
+		name: name,
+		log:  log,
+	}
+	channel := meta.ParseString("enrich_channel", "")
+	if channel == "" {
+		return e, nil
+	}
+	var err error
+	e.key, err = parseEnrichKey(meta.ParseString("enrich_key", "body"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich key value, %w", err)
+	}
+	e.merge, err = meta.ParseStringMap("enrich_merge", enrichMergeMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich merge value, %w", err)
+	}
+	e.tagPrefix = meta.ParseString("enrich_tag_prefix", "")
+	e.bodyField = meta.ParseString("enrich_body_field", "")
+	e.onError, err = meta.ParseStringMap("enrich_on_error", enrichOnErrorMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich on error value, %w", err)
+	}
+	ttl, err := meta.ParseIntWithRange("enrich_cache_ttl_seconds", 60, 0, 7*24*60*60)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich cache ttl seconds value, %w", err)
+	}
+	size, err := meta.ParseIntWithRange("enrich_cache_size", 10000, 1, 10000000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich cache size value, %w", err)
+	}
+	if ttl > 0 {
+		e.cache = cache.New(size, time.Duration(ttl)*time.Second)
+	}
+	timeout, err := meta.ParseIntWithRange("enrich_timeout_seconds", 5, 1, 60*60)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrich timeout seconds value, %w", err)
+	}
+	if _, _, err := meta.MustParseAddress("enrich_address", "localhost:50000"); err != nil {
+		return nil, fmt.Errorf("invalid enrich address value, %w", err)
+	}
+	// the lookups are sent by a query target of the lookup channel
+	e.connection = config.Metadata{
+		"address":         meta.ParseString("enrich_address", "localhost:50000"),
+		"channel":         channel,
+		"auth_token":      meta.ParseString("enrich_auth_token", ""),
+		"timeout_seconds": fmt.Sprintf("%d", timeout),
+		"client_id":       "enrich",
+	}
+	e.lookup = e.sendQuery
+	return e, nil
+}
+
+// parseEnrichKey returns the function which extracts the lookup key of a request, from its body, a field of its json body,
+// a tag, its metadata or its channel. The function returns false when the request has no key.
+func parseEnrichKey(value string) (func(request interface{}) ([]byte, bool, error), error) {
+	source, name, _ := strings.Cut(value, ":")
+	switch {
+	case source == "body" && name == "":
+		return func(request interface{}) ([]byte, bool, error) {
+			body, ok := message.Body(request)
+			return body, ok && len(body) > 0, nil
+		}, nil
+	case source == "body":
+		path := strings.Split(name, ".")
+		return func(request interface{}) ([]byte, bool, error) {
+			body, _ := message.Body(request)
+			return jsonField(body, path)
+		}, nil
+	case source == "tag" && name != "":
+		return func(request interface{}) ([]byte, bool, error) {
+			tag, ok := message.Tags(request)[name]
+			return []byte(tag), ok && tag != "", nil
+		}, nil
+	case source == "metadata" && name == "":
+		return func(request interface{}) ([]byte, bool, error) {
+			metadata := message.Metadata(request)
+			return []byte(metadata), metadata != "", nil
+		}, nil
+	case source == "channel" && name == "":
+		return func(request interface{}) ([]byte, bool, error) {
+			channel := message.Channel(request)
+			return []byte(channel), channel != "", nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown key %s, supported keys are body, body:<field>, tag:<name>, metadata and channel", value)
+	}
+}
+
+// jsonField returns the value of a field path of a json object, string values are returned without quotes
+func jsonField(body []byte, path []string) ([]byte, bool, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false, fmt.Errorf("message body is not json, %w", err)
+	}
+	for _, name := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		if value, ok = object[name]; !ok {
+			return nil, false, nil
+		}
+	}
+	switch val := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []byte(val), val != "", nil
+	default:
+		data, err := json.Marshal(val)
+		return data, err == nil, err
+	}
+}
+
+func (e *EnrichMiddleware) sendQuery(ctx context.Context, key []byte) (*kubemq.QueryResponse, error) {
+	e.Lock()
+	if e.client == nil {
+		client := query.New()
+		if err := client.Init(ctx, e.connection, e.name, e.log); err != nil {
+			e.Unlock()
+			return nil, err
+		}
+		e.client = client
+	}
+	client := e.client
+	e.Unlock()
+	resp, err := client.Do(ctx, &kubemq.Event{Body: key})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*kubemq.QueryResponse), nil
+}
+
+// response returns the lookup response of a key, from the cache when it holds the key
+func (e *EnrichMiddleware) response(ctx context.Context, key []byte) (*kubemq.QueryResponse, error) {
+	if e.cache != nil {
+		if resp, ok := e.cache.Get(string(key)); ok {
+			return resp.(*kubemq.QueryResponse), nil
+		}
+	}
+	resp, err := e.lookup(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("enrichment lookup failed, %w", err)
+	}
+	if e.cache != nil {
+		e.cache.Set(string(key), resp)
+	}
+	return resp, nil
+}
+
+// enrich returns a copy of the request with the lookup response of its key merged, requests without a key are returned as is
+func (e *EnrichMiddleware) enrich(ctx context.Context, request interface{}) (interface{}, error) {
+	body, ok := message.Body(request)
+	if !ok {
+		return nil, fmt.Errorf("enrichment is not supported for message type %T", request)
+	}
+	key, ok, err := e.key(request)
+	if err != nil || !ok {
+		return request, err
+	}
+	resp, err := e.response(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	tags := message.CopyTags(message.Tags(request))
+	switch e.merge {
+	case enrichMergeBody:
+		if body, err = mergeBody(body, resp.Body, e.bodyField); err != nil {
+			return nil, err
+		}
+	default:
+		for name, value := range resp.Tags {
+			tags[e.tagPrefix+name] = value
+		}
+	}
+	return message.Clone(request, body, tags)
+}
+
+// mergeBody merges the fields of the response json object into the body json object, or sets the response in a field of the body
+func mergeBody(body, response []byte, field string) ([]byte, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("message body is not a json object, %w", err)
+	}
+	if field != "" {
+		if !json.Valid(response) {
+			return nil, fmt.Errorf("enrichment response body is not json")
+		}
+		object[field] = response
+		return json.Marshal(object)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(response, &fields); err != nil {
+		return nil, fmt.Errorf("enrichment response body is not a json object, %w", err)
+	}
+	for name, value := range fields {
+		object[name] = value
+	}
+	return json.Marshal(object)
+}
+
+// Close releases the lookup connection
+func (e *EnrichMiddleware) Close() error {
+	e.Lock()
+	defer e.Unlock()
+	if e.client != nil {
+		err := e.client.Stop()
+		e.client = nil
+		return err
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
 )
 
 type LogLevelType string
@@ -24,6 +26,7 @@ var logLevelMap = map[string]string{
 
 type LogMiddleware struct {
 	minLevel LogLevelType
+	rules    tap.Rules
 	*logger.Logger
 }
 
@@ -32,8 +35,13 @@ func NewLogMiddleware(name string, meta config.Metadata) (*LogMiddleware, error)
 	if err != nil {
 		return nil, fmt.Errorf("invalid log level value, %w", err)
 	}
+	rules, err := tap.ParseRules(meta, "tap_")
+	if err != nil {
+		return nil, err
+	}
 	lm := &LogMiddleware{
 		minLevel: LogLevelTypeNoLog,
+		rules:    rules,
 		Logger:   logger.NewLogger(name, level),
 	}
 	switch level {
@@ -46,3 +54,12 @@ func NewLogMiddleware(name string, meta config.Metadata) (*LogMiddleware, error)
 	}
 	return lm, nil
 }
+
+// format returns the message contents as json, with the binding tap truncation and redaction rules applied
+func (lm *LogMiddleware) format(value interface{}) string {
+	data, err := json.Marshal(tap.NewMessage(lm.rules, value))
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -9,6 +9,7 @@ import (
 type MetricsMiddleware struct {
 	exporter     *metrics.Exporter
 	metricReport *metrics.Report
+	cached       bool
 }
 
 func NewMetricsMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter) (*MetricsMiddleware, error) {
@@ -28,14 +29,28 @@ func NewMetricsMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter)
 			ResponseVolume: 0,
 			ErrorsCount:    0,
 		},
+		cached: cacheEnabled(cfg),
 	}
 	return m, nil
 }
 
+// cacheEnabled returns true when a target connection of the binding caches query responses, so query responses which
+// are not cache hits are counted as cache misses
+func cacheEnabled(cfg config.BindingConfig) bool {
+	for _, connection := range cfg.Targets.Connections {
+		if connection.ParseString("cache_mode", "") != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *MetricsMiddleware) clearReport() {
 	m.metricReport.ErrorsCount = 0
 	m.metricReport.ResponseVolume = 0
 	m.metricReport.ResponseCount = 0
 	m.metricReport.RequestVolume = 0
 	m.metricReport.RequestCount = 0
+	m.metricReport.CacheHits = 0
+	m.metricReport.CacheMisses = 0
 }
@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"context"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
 	"github.com/kubemq-io/kubemq-bridges/pkg/retry"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
+	"github.com/kubemq-io/kubemq-go"
 	"reflect"
+	"time"
 )
 
 type Middleware interface {
@@ -24,8 +28,7 @@ func Log(log *LogMiddleware) MiddlewareFunc {
 			result, err := df.Do(ctx, request)
 			switch log.minLevel {
 			case "debug":
-
-				log.Infof("request: %v, response: %v, error:%+v", request, result, err)
+				log.Infof("request: %s, response: %s, error: %v", log.format(request), log.format(result), err)
 			case "info":
 				if err != nil {
 					log.Errorf("error processing request: %s", err.Error())
@@ -41,6 +44,35 @@ func Log(log *LogMiddleware) MiddlewareFunc {
 		})
 	}
 }
+
+// Tap publishes the binding messages of a stage, with their responses, to the binding tap subscribers
+func Tap(binding, stage string, target int, rules tap.Rules) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			if !tap.Active(binding) {
+				return df.Do(ctx, request)
+			}
+			start := time.Now()
+			resp, err := df.Do(ctx, request)
+			tap.Publish(binding, stage, func() *tap.Sample {
+				sample := &tap.Sample{
+					Binding:  binding,
+					Stage:    stage,
+					Target:   target,
+					Time:     start,
+					Duration: time.Since(start).String(),
+					Request:  tap.NewMessage(rules, request),
+					Response: tap.NewMessage(rules, resp),
+				}
+				if err != nil {
+					sample.Error = err.Error()
+				}
+				return sample
+			})
+			return resp, err
+		})
+	}
+}
 func RateLimiter(rl *RateLimitMiddleware) MiddlewareFunc {
 	return func(df Middleware) Middleware {
 		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -50,6 +82,207 @@ func RateLimiter(rl *RateLimitMiddleware) MiddlewareFunc {
 	}
 }
 
+// requestFunc returns a middleware which replaces the request by the result of fn, or a pass through middleware when disabled
+func requestFunc(enabled bool, fn func(request interface{}) (interface{}, error)) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if !enabled {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			replaced, err := fn(request)
+			if err != nil {
+				return nil, err
+			}
+			return df.Do(ctx, replaced)
+		})
+	}
+}
+
+// Compress compresses the request body before sending to the target
+func Compress(c *CompressMiddleware) MiddlewareFunc {
+	return requestFunc(c.codec != nil, c.compressRequest)
+}
+
+// Decompress decompresses the body of requests which were compressed by another bridge
+func Decompress(c *CompressMiddleware) MiddlewareFunc {
+	return requestFunc(c.decompress, c.decompressRequest)
+}
+
+// Encrypt encrypts the request body before sending to the target
+func Encrypt(s *SecureMiddleware) MiddlewareFunc {
+	return requestFunc(s.encrypter != nil, s.encrypt)
+}
+
+// Decrypt decrypts the body of requests which were encrypted by another bridge
+func Decrypt(s *SecureMiddleware) MiddlewareFunc {
+	return requestFunc(s.decrypter != nil, s.decrypt)
+}
+
+// Sign signs the request body before sending to the target
+func Sign(s *SecureMiddleware) MiddlewareFunc {
+	return requestFunc(s.signer != nil, s.sign)
+}
+
+// Verify rejects requests which are not signed by one of the verifying keys
+func Verify(s *SecureMiddleware) MiddlewareFunc {
+	return requestFunc(s.verifier != nil, s.verify)
+}
+
+// Convert converts the request body from the source format to the target format, and query responses back
+func Convert(c *ConvertMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if c.request == nil {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			converted, err := c.convertRequest(request)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := df.Do(ctx, converted)
+			if err != nil {
+				return resp, err
+			}
+			return c.convertResponse(resp)
+		})
+	}
+}
+
+type sourceMessageKey struct{}
+
+// Source sets the request in the context as the source message, so the target chains of a source message can tell they
+// process the same message. It is the outermost middleware, which gets the request as sent by the source to each target.
+func Source() MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			return df.Do(context.WithValue(ctx, sourceMessageKey{}, request), request)
+		})
+	}
+}
+
+// sourceMessage returns the source message of the request, nil when the request was not sent through the Source middleware
+func sourceMessage(ctx context.Context) interface{} {
+	return ctx.Value(sourceMessageKey{})
+}
+
+// Validate checks the request body against its schema, and rejects, dead-letters or tags the requests which fail validation
+func Validate(v *ValidateMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if v.registry == nil {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			validationErr := v.validate(request)
+			if validationErr == nil {
+				return df.Do(ctx, request)
+			}
+			switch v.onInvalid {
+			case onInvalidDeadLetter:
+				sent, err := v.deadLetterOnce(ctx, request, validationErr)
+				if err != nil {
+					return nil, err
+				}
+				if !sent {
+					return nil, nil
+				}
+				v.log.Errorf("message on channel %s sent to dead letter channel %s, %s", message.Channel(request), v.deadLetterChannel, validationErr.Error())
+				return nil, nil
+			case onInvalidTag:
+				tagged, err := v.tag(request, validationErr)
+				if err != nil {
+					return nil, err
+				}
+				return df.Do(ctx, tagged)
+			default:
+				return nil, validationErr
+			}
+		})
+	}
+}
+
+// messagesFunc returns a middleware which replaces the request by the requests fn returns, sending each of them in order,
+// or a pass through middleware when disabled. The response of the last request is returned, dropped requests have no response.
+func messagesFunc(enabled bool, fn func(ctx context.Context, request interface{}) ([]interface{}, error)) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if !enabled {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			requests, err := fn(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			var resp interface{}
+			for _, replaced := range requests {
+				resp, err = df.Do(ctx, replaced)
+				if err != nil {
+					return resp, err
+				}
+			}
+			return resp, nil
+		})
+	}
+}
+
+// withFields returns copies of the request with each of the fields
+func withFields(request interface{}, list []message.Fields) ([]interface{}, error) {
+	var requests []interface{}
+	for _, fields := range list {
+		replaced, err := message.WithFields(request, fields)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, replaced)
+	}
+	return requests, nil
+}
+
+// Script replaces the request by the messages the script returns
+func Script(s *ScriptMiddleware) MiddlewareFunc {
+	return messagesFunc(s.script != nil, s.process)
+}
+
+// Wasm replaces the request by the messages the webassembly module returns
+func Wasm(w *WasmMiddleware) MiddlewareFunc {
+	return messagesFunc(w.plugin != nil, w.process)
+}
+
+// Aggregate combines requests into batch requests of joined bodies, which are sent when full or when their window ends
+func Aggregate(a *AggregateMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if a.format == "" {
+			return df
+		}
+		a.next = df
+		return DoFunc(a.add)
+	}
+}
+
+// Split replaces the request by a request for each item of its body
+func Split(a *AggregateMiddleware) MiddlewareFunc {
+	return messagesFunc(a.splitFormat != "", a.split)
+}
+
+// Enrich merges the response of a lookup query of the request key into the request
+func Enrich(e *EnrichMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if e.key == nil {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			enriched, err := e.enrich(ctx, request)
+			if err != nil {
+				if e.onError != enrichOnErrorSkip {
+					return nil, err
+				}
+				e.log.Errorf("message on channel %s sent without enrichment, %s", message.Channel(request), err.Error())
+				enriched = request
+			}
+			return df.Do(ctx, enriched)
+		})
+	}
+}
+
 func Retry(r *RetryMiddleware) MiddlewareFunc {
 	return func(df Middleware) Middleware {
 		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -82,6 +315,13 @@ func Metric(m *MetricsMiddleware) MiddlewareFunc {
 			if err != nil {
 				m.metricReport.ErrorsCount = 1
 			}
+			if queryResponse, ok := resp.(*kubemq.QueryResponse); ok {
+				if queryResponse.CacheHit {
+					m.metricReport.CacheHits = 1
+				} else if m.cached {
+					m.metricReport.CacheMisses = 1
+				}
+			}
 			m.exporter.Report(m.metricReport)
 			return resp, err
 		})
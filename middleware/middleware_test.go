@@ -6,8 +6,15 @@ import (
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
+	"github.com/kubemq-io/kubemq-go"
 	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -17,10 +24,12 @@ type mockTarget struct {
 	setError    error
 	delay       time.Duration
 	executed    int
+	lastRequest interface{}
 }
 
 func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
 	time.Sleep(m.delay)
+	m.lastRequest = request
 	return m.setResponse, m.setError
 }
 
@@ -301,9 +310,23 @@ func TestClient_Retry(t *testing.T) {
 	}
 }
 
+var (
+	exporterOnce sync.Once
+	exporter     *metrics.Exporter
+	exporterErr  error
+)
+
+// testExporter returns an exporter shared by the tests, as its prometheus metrics are registered once
+func testExporter(t *testing.T) *metrics.Exporter {
+	exporterOnce.Do(func() {
+		exporter, exporterErr = metrics.NewExporter()
+	})
+	require.NoError(t, exporterErr)
+	return exporter
+}
+
 func TestClient_Metric(t *testing.T) {
-	exporter, err := metrics.NewExporter()
-	require.NoError(t, err)
+	exporter := testExporter(t)
 
 	tests := []struct {
 		name       string
@@ -484,6 +507,20 @@ func TestClient_Log(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid tap redact pattern",
+			mock: &mockTarget{
+				setResponse: nil,
+				setError:    nil,
+				delay:       0,
+				executed:    0,
+			},
+			meta: map[string]string{
+				"log_level":          "debug",
+				"tap_redact_pattern": "[",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -503,6 +540,36 @@ func TestClient_Log(t *testing.T) {
 	}
 }
 
+func TestClient_Tap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock := &mockTarget{
+		setResponse: &kubemq.QueryResponse{Executed: true, Body: []byte("response")},
+		setError:    nil,
+	}
+	md := Chain(mock, Tap("tap-binding", tap.StageSource, 1, tap.DefaultRules()))
+	_, err := md.Do(ctx, &kubemq.Event{Channel: "ch", Body: []byte("no subscribers")})
+	require.NoError(t, err)
+
+	sub := tap.Subscribe("tap-binding", tap.StageSource, 1, 10)
+	defer tap.Unsubscribe(sub)
+	resp, err := md.Do(ctx, &kubemq.Event{Channel: "ch", Body: []byte("request"), Tags: map[string]string{"token": "secret"}})
+	require.NoError(t, err)
+	require.Equal(t, mock.setResponse, resp)
+	select {
+	case sample := <-sub.Samples():
+		require.Equal(t, "tap-binding", sample.Binding)
+		require.Equal(t, tap.StageSource, sample.Stage)
+		require.Equal(t, 1, sample.Target)
+		require.Equal(t, "request", sample.Request.Body)
+		require.Equal(t, tap.Redacted, sample.Request.Tags["token"])
+		require.Equal(t, "response", sample.Response.Body)
+	case <-ctx.Done():
+		require.Fail(t, "no sample received")
+	}
+	require.Len(t, sub.Samples(), 0)
+}
+
 func TestClient_Chain(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -532,3 +599,1033 @@ func TestClient_Chain(t *testing.T) {
 	d := time.Since(start)
 	require.GreaterOrEqual(t, d.Milliseconds(), 2*time.Second.Milliseconds())
 }
+
+func TestClient_Convert(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	meta := config.Metadata{
+		"convert_source_format": "json",
+		"convert_target_format": "msgpack",
+	}
+	converter, err := NewConvertMiddleware(meta)
+	require.NoError(t, err)
+	packed, err := msgpack.Marshal(map[string]interface{}{"id": 1})
+	require.NoError(t, err)
+	mock := &mockTarget{
+		setResponse: &kubemq.QueryResponse{Executed: true, Body: packed},
+	}
+	md := Chain(mock, Convert(converter))
+	request := &kubemq.QueryReceive{Channel: "ch", Body: []byte(`{"id":1}`)}
+	resp, err := md.Do(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"id":1}`), request.Body)
+	require.Equal(t, packed, mock.lastRequest.(*kubemq.QueryReceive).Body)
+	require.Equal(t, []byte(`{"id":1}`), resp.(*kubemq.QueryResponse).Body)
+
+	_, err = md.Do(ctx, &kubemq.QueryReceive{Channel: "ch", Body: []byte("not-json")})
+	require.Error(t, err)
+
+	mock.lastRequest = nil
+	none, err := NewConvertMiddleware(config.Metadata{})
+	require.NoError(t, err)
+	request = &kubemq.QueryReceive{Channel: "ch", Body: []byte("not-json")}
+	_, err = Chain(mock, Convert(none)).Do(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, request, mock.lastRequest)
+}
+
+func TestNewConvertMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name: "valid",
+			meta: config.Metadata{
+				"convert_source_format": "cbor",
+				"convert_target_format": "json",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid - missing target format",
+			meta: config.Metadata{
+				"convert_source_format": "json",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid - bad format",
+			meta: config.Metadata{
+				"convert_source_format": "json",
+				"convert_target_format": "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid - missing schema",
+			meta: config.Metadata{
+				"convert_source_format": "protobuf",
+				"convert_target_format": "json",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConvertMiddleware(tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Validate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.json"), []byte(`{"type":"object","required":["id"]}`), 0600))
+	tests := []struct {
+		name         string
+		meta         config.Metadata
+		request      *kubemq.Event
+		wantErr      bool
+		wantTarget   bool
+		wantTag      bool
+		wantDeadSent bool
+	}{
+		{
+			name:       "valid by channel",
+			meta:       config.Metadata{"validate_channel_schemas": `{"orders":"order"}`},
+			request:    &kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`)},
+			wantTarget: true,
+		},
+		{
+			name:       "no schema",
+			meta:       config.Metadata{},
+			request:    &kubemq.Event{Channel: "orders", Body: []byte(`not-json`)},
+			wantTarget: true,
+		},
+		{
+			name:    "reject by tag",
+			meta:    config.Metadata{},
+			request: &kubemq.Event{Channel: "orders", Body: []byte(`{}`), Tags: map[string]string{"schema-id": "order"}},
+			wantErr: true,
+		},
+		{
+			name:    "reject unknown schema",
+			meta:    config.Metadata{},
+			request: &kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`), Tags: map[string]string{"schema-id": "customer"}},
+			wantErr: true,
+		},
+		{
+			name:       "tag",
+			meta:       config.Metadata{"validate_on_invalid": "tag", "validate_channel_schemas": `{"orders":"order"}`},
+			request:    &kubemq.Event{Channel: "orders", Body: []byte(`{}`)},
+			wantTarget: true,
+			wantTag:    true,
+		},
+		{
+			name: "dead-letter",
+			meta: config.Metadata{
+				"validate_on_invalid":          "dead-letter",
+				"validate_dead_letter_channel": "dead-letters",
+				"validate_channel_schemas":     `{"orders":"order"}`,
+			},
+			request:      &kubemq.Event{Channel: "orders", Body: []byte(`{}`)},
+			wantDeadSent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.meta["validate_registry_dir"] = dir
+			v, err := NewValidateMiddleware("test", tt.meta, logger.NewLogger("validate"))
+			require.NoError(t, err)
+			defer func() { _ = v.Close() }()
+			var dead *kubemq.QueueMessage
+			if v.send != nil {
+				v.send = func(ctx context.Context, msg *kubemq.QueueMessage) error {
+					dead = msg
+					return nil
+				}
+			}
+			mock := &mockTarget{}
+			_, err = Chain(mock, Validate(v)).Do(ctx, tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantTarget, mock.lastRequest != nil)
+			if tt.wantTag {
+				require.NotEmpty(t, mock.lastRequest.(*kubemq.Event).Tags["schema-error"])
+				require.Empty(t, tt.request.Tags["schema-error"])
+			}
+			require.Equal(t, tt.wantDeadSent, dead != nil)
+			if dead != nil {
+				require.Equal(t, "dead-letters", dead.Channel)
+				require.Equal(t, tt.request.Body, dead.Body)
+				require.NotEmpty(t, dead.Tags["schema-error"])
+			}
+		})
+	}
+}
+
+func TestNewValidateMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.json"), []byte(`{"type":"object"}`), 0600))
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"validate_registry_dir": dir, "validate_channel_schemas": `{"orders":"order"}`},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - missing directory",
+			meta:    config.Metadata{"validate_registry_dir": filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - unknown channel schema",
+			meta:    config.Metadata{"validate_registry_dir": dir, "validate_channel_schemas": `{"orders":"customer"}`},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad channel schemas",
+			meta:    config.Metadata{"validate_registry_dir": dir, "validate_channel_schemas": `orders`},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad on invalid",
+			meta:    config.Metadata{"validate_registry_dir": dir, "validate_on_invalid": "drop"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - dead-letter without channel",
+			meta:    config.Metadata{"validate_registry_dir": dir, "validate_on_invalid": "dead-letter"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidateMiddleware("test", tt.meta, logger.NewLogger("validate"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, v.Close())
+		})
+	}
+}
+
+func TestClient_Compress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	body := []byte(strings.Repeat("kubemq bridges ", 100))
+	sender, err := NewCompressMiddleware(config.Metadata{"compress_codec": "zstd", "compress_min_size": "100"})
+	require.NoError(t, err)
+	receiver, err := NewCompressMiddleware(config.Metadata{})
+	require.NoError(t, err)
+	mock := &mockTarget{}
+	send := Chain(mock, Compress(sender), Decompress(sender))
+	receive := Chain(mock, Compress(receiver), Decompress(receiver))
+
+	request := &kubemq.Event{Channel: "ch", Body: body, Tags: map[string]string{"key": "value"}}
+	_, err = send.Do(ctx, request)
+	require.NoError(t, err)
+	compressed := mock.lastRequest.(*kubemq.Event)
+	require.Equal(t, "zstd", compressed.Tags[defaultCompressTag])
+	require.Less(t, len(compressed.Body), len(body))
+	require.Equal(t, body, request.Body)
+	require.Empty(t, request.Tags[defaultCompressTag])
+
+	_, err = receive.Do(ctx, compressed)
+	require.NoError(t, err)
+	decompressed := mock.lastRequest.(*kubemq.Event)
+	require.Equal(t, body, decompressed.Body)
+	require.Equal(t, map[string]string{"key": "value"}, decompressed.Tags)
+
+	small := &kubemq.Event{Channel: "ch", Body: []byte("small")}
+	_, err = send.Do(ctx, small)
+	require.NoError(t, err)
+	require.Equal(t, small, mock.lastRequest)
+
+	// tags which are not the compression tag or not a known codec are passed as is
+	for _, tags := range []map[string]string{{"compression": "gzip"}, {defaultCompressTag: "none"}, {defaultCompressTag: "brotli"}} {
+		unknown := &kubemq.Event{Channel: "ch", Body: body, Tags: tags}
+		_, err = receive.Do(ctx, unknown)
+		require.NoError(t, err)
+		require.Equal(t, unknown, mock.lastRequest)
+	}
+	_, err = receive.Do(ctx, &kubemq.Event{Channel: "ch", Body: body, Tags: map[string]string{defaultCompressTag: "gzip"}})
+	require.Error(t, err)
+
+	off, err := NewCompressMiddleware(config.Metadata{"decompress": "false"})
+	require.NoError(t, err)
+	_, err = Chain(mock, Decompress(off)).Do(ctx, compressed)
+	require.NoError(t, err)
+	require.Equal(t, compressed, mock.lastRequest)
+}
+
+func TestNewCompressMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"compress_codec": "lz4", "compress_min_size": "0", "decompress_max_size": "1024"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - bad codec",
+			meta:    config.Metadata{"compress_codec": "brotli"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad min size",
+			meta:    config.Metadata{"compress_codec": "gzip", "compress_min_size": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad max size",
+			meta:    config.Metadata{"decompress_max_size": "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCompressMiddleware(tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Secure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+	aesKey := filepath.Join(dir, "aes-2024.key")
+	require.NoError(t, os.WriteFile(aesKey, []byte("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="), 0600))
+	hmacKey := filepath.Join(dir, "hmac.key")
+	require.NoError(t, os.WriteFile(hmacKey, []byte("c2lnbmluZy1zZWNyZXQ="), 0600))
+	sender, err := NewSecureMiddleware(config.Metadata{
+		"encrypt_algorithm": "aes-gcm",
+		"encrypt_key_files": aesKey,
+		"sign_algorithm":    "hmac-sha256",
+		"sign_key_files":    hmacKey,
+	})
+	require.NoError(t, err)
+	receiver, err := NewSecureMiddleware(config.Metadata{
+		"encrypt_algorithm": "aes-gcm",
+		"decrypt_key_files": aesKey,
+		"sign_algorithm":    "hmac-sha256",
+		"verify_key_files":  hmacKey,
+	})
+	require.NoError(t, err)
+	mock := &mockTarget{}
+	send := Chain(mock, Sign(sender), Encrypt(sender), Decrypt(sender), Verify(sender))
+	receive := Chain(mock, Sign(receiver), Encrypt(receiver), Decrypt(receiver), Verify(receiver))
+
+	request := &kubemq.QueryReceive{Channel: "ch", Body: []byte("payload"), Tags: map[string]string{"key": "value"}}
+	_, err = send.Do(ctx, request)
+	require.NoError(t, err)
+	sent := mock.lastRequest.(*kubemq.QueryReceive)
+	require.NotEqual(t, request.Body, sent.Body)
+	require.Equal(t, "aes-2024", sent.Tags["encryption-key-id"])
+	require.Equal(t, "hmac", sent.Tags["signature-key-id"])
+	require.NotEmpty(t, sent.Tags["signature"])
+
+	_, err = receive.Do(ctx, sent)
+	require.NoError(t, err)
+	received := mock.lastRequest.(*kubemq.QueryReceive)
+	require.Equal(t, []byte("payload"), received.Body)
+	require.Equal(t, map[string]string{"key": "value"}, received.Tags)
+
+	withTags := func(change func(tags map[string]string)) map[string]string {
+		tags := map[string]string{}
+		for key, value := range sent.Tags {
+			tags[key] = value
+		}
+		change(tags)
+		return tags
+	}
+	for name, change := range map[string]func(msg *kubemq.QueryReceive){
+		"body": func(msg *kubemq.QueryReceive) {
+			msg.Body = append([]byte{}, sent.Body...)
+			msg.Body[0] ^= 0xff
+		},
+		"tag": func(msg *kubemq.QueryReceive) {
+			msg.Tags = withTags(func(tags map[string]string) { tags["key"] = "other" })
+		},
+		"added tag": func(msg *kubemq.QueryReceive) {
+			msg.Tags = withTags(func(tags map[string]string) { tags["kubemq-bridges-compression"] = "gzip" })
+		},
+		"key id tag": func(msg *kubemq.QueryReceive) {
+			msg.Tags = withTags(func(tags map[string]string) { tags["encryption-key-id"] = "aes-2025" })
+		},
+		"metadata": func(msg *kubemq.QueryReceive) { msg.Metadata = "other" },
+	} {
+		tampered := *sent
+		change(&tampered)
+		_, err = receive.Do(ctx, &tampered)
+		require.Error(t, err, name)
+	}
+	_, err = receive.Do(ctx, request)
+	require.Error(t, err)
+
+	// the channel is not signed by default, as the targets may send to other channels than the source message channel
+	sent.Channel = "target-channel"
+	_, err = receive.Do(ctx, sent)
+	require.NoError(t, err)
+
+	sender.signChannel, receiver.signChannel = true, true
+	_, err = send.Do(ctx, request)
+	require.NoError(t, err)
+	sent = mock.lastRequest.(*kubemq.QueryReceive)
+	_, err = receive.Do(ctx, sent)
+	require.NoError(t, err)
+	sent.Channel = "target-channel"
+	_, err = receive.Do(ctx, sent)
+	require.Error(t, err)
+}
+
+func TestNewSecureMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	aesKey := filepath.Join(dir, "aes.key")
+	require.NoError(t, os.WriteFile(aesKey, []byte("MDEyMzQ1Njc4OWFiY2RlZg=="), 0600))
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"encrypt_algorithm": "aes-gcm", "encrypt_key_files": aesKey, "decrypt_key_files": aesKey},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - bad encrypt algorithm",
+			meta:    config.Metadata{"encrypt_algorithm": "des", "encrypt_key_files": aesKey},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - no encryption keys",
+			meta:    config.Metadata{"encrypt_algorithm": "aes-gcm"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad key size",
+			meta:    config.Metadata{"encrypt_algorithm": "nacl-box", "encrypt_key_files": aesKey},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad sign algorithm",
+			meta:    config.Metadata{"sign_algorithm": "rsa", "sign_key_files": aesKey},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - no signing keys",
+			meta:    config.Metadata{"sign_algorithm": "ed25519"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - missing key file",
+			meta:    config.Metadata{"sign_algorithm": "hmac-sha256", "verify_key_files": filepath.Join(dir, "missing.key")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSecureMiddleware(tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Script(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "orders.js")
+	require.NoError(t, os.WriteFile(file, []byte(`
+function process(msg) {
+  if (msg.tags["env"] === "test") {
+    return null;
+  }
+  if (msg.tags["split"] === "true") {
+    return msg.body.split(",").map(function (id) {
+      return {channel: msg.channel, metadata: msg.metadata, body: id, tags: {id: id}};
+    });
+  }
+  msg.tags["region"] = "eu";
+  return msg;
+}
+`), 0600))
+	s, err := NewScriptMiddleware(config.Metadata{"script_file": file}, logger.NewLogger("script"))
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	var received []*kubemq.QueueMessage
+	target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		received = append(received, request.(*kubemq.QueueMessage))
+		return len(received), nil
+	})
+	md := Chain(target, Script(s))
+
+	request := kubemq.NewQueueMessage().SetChannel("orders").SetMetadata("md").SetBody([]byte("1")).SetTags(map[string]string{"key": "value"})
+	resp, err := md.Do(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp)
+	require.Equal(t, map[string]string{"key": "value", "region": "eu"}, received[0].Tags)
+	require.Equal(t, "md", received[0].Metadata)
+	require.Equal(t, map[string]string{"key": "value"}, request.Tags)
+
+	resp, err = md.Do(ctx, kubemq.NewQueueMessage().SetChannel("orders").SetBody([]byte("1")).AddTag("env", "test"))
+	require.NoError(t, err)
+	require.Nil(t, resp)
+	require.Len(t, received, 1)
+
+	resp, err = md.Do(ctx, kubemq.NewQueueMessage().SetChannel("orders").SetMetadata("md").SetBody([]byte("2,3")).AddTag("split", "true"))
+	require.NoError(t, err)
+	require.Equal(t, 3, resp)
+	require.Len(t, received, 3)
+	require.Equal(t, []byte("2"), received[1].Body)
+	require.Equal(t, "md", received[1].Metadata)
+	require.Equal(t, map[string]string{"id": "3"}, received[2].Tags)
+
+	_, err = md.Do(ctx, "body")
+	require.Error(t, err)
+}
+
+func TestNewScriptMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	lua := filepath.Join(dir, "script.lua")
+	require.NoError(t, os.WriteFile(lua, []byte(`function process(msg) return msg end`), 0600))
+	txt := filepath.Join(dir, "script.txt")
+	require.NoError(t, os.WriteFile(txt, []byte(`function process(msg) return msg end`), 0600))
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"script_file": lua, "script_timeout_milliseconds": "100", "script_max_messages": "10"},
+			wantErr: false,
+		},
+		{
+			name:    "valid - language",
+			meta:    config.Metadata{"script_file": txt, "script_language": "lua"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - unknown extension",
+			meta:    config.Metadata{"script_file": txt},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad language",
+			meta:    config.Metadata{"script_file": lua, "script_language": "python"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - wrong language",
+			meta:    config.Metadata{"script_file": lua, "script_language": "javascript"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad timeout",
+			meta:    config.Metadata{"script_file": lua, "script_timeout_milliseconds": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - missing file",
+			meta:    config.Metadata{"script_file": filepath.Join(dir, "missing.lua")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewScriptMiddleware(tt.meta, logger.NewLogger("script"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, s.Close())
+		})
+	}
+}
+
+// echoWasmModule is a webassembly module which process function returns its input
+var echoWasmModule = []byte{0x0, 0x61, 0x73, 0x6d, 0x1, 0x0, 0x0, 0x0, 0x1, 0xc, 0x2, 0x60, 0x1, 0x7f, 0x1, 0x7f, 0x60, 0x2, 0x7f, 0x7f, 0x1, 0x7e,
+	0x3, 0x3, 0x2, 0x0, 0x1, 0x5, 0x3, 0x1, 0x0, 0x1, 0x6, 0x7, 0x1, 0x7f, 0x1, 0x41, 0x80, 0x8, 0xb, 0x7, 0x1c, 0x3, 0x6, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x2, 0x0, 0x5, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x0, 0x0, 0x7, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x0,
+	0x1, 0xa, 0x1a, 0x2, 0xb, 0x0, 0x23, 0x0, 0x23, 0x0, 0x20, 0x0, 0x6a, 0x24, 0x0, 0xb, 0xc, 0x0, 0x20, 0x0, 0xad, 0x42, 0x20, 0x86,
+	0x20, 0x1, 0xad, 0x84, 0xb}
+
+func TestClient_Wasm(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "echo.wasm")
+	require.NoError(t, os.WriteFile(file, echoWasmModule, 0600))
+	w, err := NewWasmMiddleware(ctx, config.Metadata{"wasm_module_file": file, "wasm_pool_size": "1"})
+	require.NoError(t, err)
+	defer func() { _ = w.Close() }()
+	mock := &mockTarget{setResponse: "response"}
+	md := Chain(mock, Wasm(w))
+
+	request := &kubemq.CommandReceive{Channel: "orders", Metadata: "md", Body: []byte{0x00, 0xff}, Tags: map[string]string{"key": "value"}}
+	resp, err := md.Do(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Equal(t, request, mock.lastRequest)
+	require.NotSame(t, request, mock.lastRequest)
+
+	_, err = md.Do(ctx, "body")
+	require.Error(t, err)
+}
+
+func TestNewWasmMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	module := filepath.Join(dir, "echo.wasm")
+	require.NoError(t, os.WriteFile(module, echoWasmModule, 0600))
+	invalid := filepath.Join(dir, "invalid.wasm")
+	require.NoError(t, os.WriteFile(invalid, []byte("module"), 0600))
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"wasm_module_file": module, "wasm_timeout_milliseconds": "100", "wasm_pool_size": "8", "wasm_max_memory_pages": "16"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - bad module",
+			meta:    config.Metadata{"wasm_module_file": invalid},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - missing module",
+			meta:    config.Metadata{"wasm_module_file": filepath.Join(dir, "missing.wasm")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad pool size",
+			meta:    config.Metadata{"wasm_module_file": module, "wasm_pool_size": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad max memory pages",
+			meta:    config.Metadata{"wasm_module_file": module, "wasm_max_memory_pages": "65537"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWasmMiddleware(context.Background(), tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+		})
+	}
+}
+
+func TestClient_Aggregate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a, err := NewAggregateMiddleware(config.Metadata{"aggregate_format": "json", "aggregate_count": "3", "aggregate_window_milliseconds": "100"}, logger.NewLogger("aggregate"))
+	require.NoError(t, err)
+	batches := make(chan *kubemq.Event, 10)
+	target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		batches <- request.(*kubemq.Event)
+		return "response", nil
+	})
+	md := Chain(target, Aggregate(a))
+
+	// a full batch is sent at once, and all its callers get the batch response
+	resps := make(chan interface{}, 3)
+	for _, body := range []string{`{"id":1}`, `{"id":2}`, `text`} {
+		go func(body string) {
+			resp, _ := md.Do(ctx, &kubemq.Event{Channel: "orders", Body: []byte(body), Tags: map[string]string{"key": "value"}})
+			resps <- resp
+		}(body)
+		time.Sleep(10 * time.Millisecond)
+	}
+	sent := <-batches
+	require.Equal(t, `[{"id":1},{"id":2},"text"]`, string(sent.Body))
+	require.Equal(t, map[string]string{"key": "value", "batch-size": "3"}, sent.Tags)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "response", <-resps)
+	}
+
+	// a partial batch is sent when its window ends
+	start := time.Now()
+	resp, err := md.Do(ctx, &kubemq.Event{Channel: "orders", Body: []byte(`{"id":4}`)})
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.Equal(t, `[{"id":4}]`, string((<-batches).Body))
+
+	_, err = md.Do(ctx, "body")
+	require.Error(t, err)
+
+	// a caller which leaves before the batch is sent does not fail the batch of the other callers
+	callerCtx, callerCancel := context.WithCancel(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := md.Do(callerCtx, &kubemq.Event{Channel: "orders", Body: []byte(`{"id":5}`)})
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	callerCancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+	resp, err = md.Do(ctx, &kubemq.Event{Channel: "orders", Body: []byte(`{"id":6}`)})
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Equal(t, `[{"id":5},{"id":6}]`, string((<-batches).Body))
+}
+
+func TestClient_AggregateNoWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	a, err := NewAggregateMiddleware(config.Metadata{"aggregate_format": "lines", "aggregate_count": "2", "aggregate_window_milliseconds": "60000", "aggregate_wait": "false"}, logger.NewLogger("aggregate"))
+	require.NoError(t, err)
+	batches := make(chan *kubemq.QueueMessage, 10)
+	release := make(chan struct{})
+	target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		<-release
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		batches <- request.(*kubemq.QueueMessage)
+		return "response", nil
+	})
+	md := Chain(target, Aggregate(a))
+
+	// the callers complete when their messages are added, and the batch is sent after their contexts end
+	for _, body := range []string{"a", "b", "c"} {
+		resp, err := md.Do(ctx, kubemq.NewQueueMessage().SetChannel("orders").SetBody([]byte(body)))
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+	cancel()
+	close(release)
+	sent := <-batches
+	require.Equal(t, "a\nb", string(sent.Body))
+	require.Equal(t, "2", sent.Tags["batch-size"])
+
+	// the pending batch is sent on close
+	a.Close()
+	require.Equal(t, "c", string((<-batches).Body))
+	require.Error(t, a.ctx.Err())
+}
+
+func TestClient_Split(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a, err := NewAggregateMiddleware(config.Metadata{"split_format": "json"}, logger.NewLogger("split"))
+	require.NoError(t, err)
+	var received []*kubemq.QueryReceive
+	target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		received = append(received, request.(*kubemq.QueryReceive))
+		return len(received), nil
+	})
+	md := Chain(target, Split(a))
+	resp, err := md.Do(ctx, &kubemq.QueryReceive{Channel: "orders", Body: []byte(`[{"id":1},"text"]`), Tags: map[string]string{"key": "value", "batch-size": "2"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, resp)
+	require.Equal(t, []byte(`{"id":1}`), received[0].Body)
+	require.Equal(t, []byte(`text`), received[1].Body)
+	require.Equal(t, map[string]string{"key": "value"}, received[1].Tags)
+
+	_, err = md.Do(ctx, &kubemq.QueryReceive{Channel: "orders", Body: []byte(`{"id":1}`)})
+	require.Error(t, err)
+}
+
+func TestAggregateWaits(t *testing.T) {
+	require.False(t, AggregateWaits(config.Metadata{}))
+	require.True(t, AggregateWaits(config.Metadata{"aggregate_format": "json"}))
+	require.False(t, AggregateWaits(config.Metadata{"aggregate_format": "json", "aggregate_wait": "false"}))
+	require.False(t, AggregateWaits(config.Metadata{"aggregate_wait": "true"}))
+}
+
+func TestNewAggregateMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"aggregate_format": "lines", "aggregate_count": "10", "aggregate_window_milliseconds": "500", "split_format": "json"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - bad aggregate format",
+			meta:    config.Metadata{"aggregate_format": "csv"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad count",
+			meta:    config.Metadata{"aggregate_format": "json", "aggregate_count": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad window",
+			meta:    config.Metadata{"aggregate_format": "json", "aggregate_window_milliseconds": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad split format",
+			meta:    config.Metadata{"split_format": "xml"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAggregateMiddleware(tt.meta, logger.NewLogger("aggregate"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Enrich(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lookups := 0
+	lookup := func(ctx context.Context, key []byte) (*kubemq.QueryResponse, error) {
+		lookups++
+		if string(key) == "unknown" {
+			return nil, fmt.Errorf("customer not found")
+		}
+		return &kubemq.QueryResponse{
+			Executed: true,
+			Body:     []byte(`{"tier":"gold"}`),
+			Tags:     map[string]string{"tier": "gold"},
+		}, nil
+	}
+	tests := []struct {
+		name     string
+		meta     config.Metadata
+		request  *kubemq.Event
+		wantBody string
+		wantTags map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "tag key - merge tags",
+			meta:     config.Metadata{"enrich_channel": "customers", "enrich_key": "tag:customer-id", "enrich_tag_prefix": "customer-"},
+			request:  &kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`), Tags: map[string]string{"customer-id": "c1"}},
+			wantBody: `{"id":1}`,
+			wantTags: map[string]string{"customer-id": "c1", "customer-tier": "gold"},
+		},
+		{
+			name:     "body field key - merge body",
+			meta:     config.Metadata{"enrich_channel": "customers", "enrich_key": "body:customer.id", "enrich_merge": "body"},
+			request:  &kubemq.Event{Channel: "orders", Body: []byte(`{"customer":{"id":7}}`)},
+			wantBody: `{"customer":{"id":7},"tier":"gold"}`,
+			wantTags: map[string]string{},
+		},
+		{
+			name:     "merge body field",
+			meta:     config.Metadata{"enrich_channel": "customers", "enrich_key": "metadata", "enrich_merge": "body", "enrich_body_field": "customer"},
+			request:  &kubemq.Event{Channel: "orders", Metadata: "c1", Body: []byte(`{"id":1}`)},
+			wantBody: `{"customer":{"tier":"gold"},"id":1}`,
+			wantTags: map[string]string{},
+		},
+		{
+			name:     "no key",
+			meta:     config.Metadata{"enrich_channel": "customers", "enrich_key": "tag:customer-id"},
+			request:  &kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`)},
+			wantBody: `{"id":1}`,
+		},
+		{
+			name:    "invalid - lookup error",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_key": "tag:customer-id"},
+			request: &kubemq.Event{Channel: "orders", Tags: map[string]string{"customer-id": "unknown"}},
+			wantErr: true,
+		},
+		{
+			name:     "lookup error - skip",
+			meta:     config.Metadata{"enrich_channel": "customers", "enrich_key": "tag:customer-id", "enrich_on_error": "skip"},
+			request:  &kubemq.Event{Channel: "orders", Body: []byte(`{"id":1}`), Tags: map[string]string{"customer-id": "unknown"}},
+			wantBody: `{"id":1}`,
+			wantTags: map[string]string{"customer-id": "unknown"},
+		},
+		{
+			name:    "invalid - body is not json",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_key": "body:id"},
+			request: &kubemq.Event{Channel: "orders", Body: []byte(`id`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEnrichMiddleware("test", tt.meta, logger.NewLogger("enrich"))
+			require.NoError(t, err)
+			e.lookup = lookup
+			mock := &mockTarget{}
+			_, err = Chain(mock, Enrich(e)).Do(ctx, tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			sent := mock.lastRequest.(*kubemq.Event)
+			require.Equal(t, tt.wantBody, string(sent.Body))
+			require.Equal(t, tt.wantTags, sent.Tags)
+		})
+	}
+
+	// responses are cached by key
+	e, err := NewEnrichMiddleware("test", config.Metadata{"enrich_channel": "customers", "enrich_key": "channel"}, logger.NewLogger("enrich"))
+	require.NoError(t, err)
+	e.lookup = lookup
+	md := Chain(&mockTarget{}, Enrich(e))
+	lookups = 0
+	for i := 0; i < 3; i++ {
+		_, err = md.Do(ctx, &kubemq.Event{Channel: "orders"})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, lookups)
+	require.NoError(t, e.Close())
+}
+
+func TestNewEnrichMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			meta:    config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "valid",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_key": "body:customer.id", "enrich_merge": "body", "enrich_cache_ttl_seconds": "0"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - bad key",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_key": "header:id"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad merge",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_merge": "metadata"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad on error",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_on_error": "retry"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad cache ttl",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_cache_ttl_seconds": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad address",
+			meta:    config.Metadata{"enrich_channel": "customers", "enrich_address": "localhost"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEnrichMiddleware("test", tt.meta, logger.NewLogger("enrich"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_MetricCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	exporter := testExporter(t)
+	cfg := config.BindingConfig{
+		Name:    "cache-binding",
+		Sources: config.Spec{Kind: "source.query"},
+		Targets: config.Spec{Kind: "target.query", Connections: []config.Metadata{{"cache_mode": "local"}}},
+	}
+	m, err := NewMetricsMiddleware(cfg, exporter)
+	require.NoError(t, err)
+	mock := &mockTarget{}
+	md := Chain(mock, Metric(m))
+	for _, cacheHit := range []bool{false, true, true} {
+		mock.setResponse = &kubemq.QueryResponse{Executed: true, CacheHit: cacheHit}
+		_, err := md.Do(ctx, &kubemq.QueryReceive{Channel: "ch"})
+		require.NoError(t, err)
+	}
+	report := exporter.Store.Get("cache-binding-source.query-target.query")
+	require.NotNil(t, report)
+	require.EqualValues(t, 2, report.CacheHits)
+	require.EqualValues(t, 1, report.CacheMisses)
+}
@@ -0,0 +1,444 @@
+package middleware
+
+import (
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"math"
+)
+
+// Properties returns the binding properties keys supported by the middlewares
+func Properties() []*config.Property {
+	return []*config.Property{
+		{
+			Name:        "log_level",
+			Kind:        config.PropertyKindString,
+			Description: "log level setting, empty value indicate no logging on this binding",
+			Options:     []string{"debug", "info", "error", ""},
+		},
+		{
+			Name:        "retry_attempts",
+			Kind:        config.PropertyKindInt,
+			Description: "how many retries before giving up on target execution",
+			Default:     "1",
+			Min:         1,
+			Max:         math.MaxInt32,
+		},
+		{
+			Name:        "retry_delay_milliseconds",
+			Kind:        config.PropertyKindInt,
+			Description: "how long to wait between retries in milliseconds",
+			Default:     "100",
+			Min:         0,
+			Max:         math.MaxInt32,
+		},
+		{
+			Name:        "retry_max_jitter_milliseconds",
+			Kind:        config.PropertyKindInt,
+			Description: "max delay jitter between retries",
+			Default:     "100",
+			Min:         1,
+			Max:         math.MaxInt32,
+		},
+		{
+			Name:        "retry_delay_type",
+			Kind:        config.PropertyKindString,
+			Description: "type of retry delay",
+			Default:     "back-off",
+			Options:     []string{"back-off", "fixed", "random", ""},
+		},
+		{
+			Name:        "rate_per_second",
+			Kind:        config.PropertyKindInt,
+			Description: "how many executions per second will be allowed, 0 - no limitation",
+			Default:     "0",
+			Min:         0,
+			Max:         math.MaxInt32,
+		},
+		{
+			Name:        "tap_max_body_size",
+			Kind:        config.PropertyKindInt,
+			Description: "max body size in bytes shown by the binding tap and debug log, 0 - no truncation",
+			Default:     "1024",
+			Min:         0,
+			Max:         1024 * 1024 * 1024,
+		},
+		{
+			Name:        "tap_redact_tags",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated tags keys which values are redacted by the binding tap and debug log",
+			Default:     "authorization,password,token,secret",
+		},
+		{
+			Name:        "tap_redact_pattern",
+			Kind:        config.PropertyKindString,
+			Description: "regular expression which matches are redacted from the body and metadata shown by the binding tap and debug log",
+			Default:     "",
+		},
+		{
+			Name:        "convert_source_format",
+			Kind:        config.PropertyKindString,
+			Description: "payload format of the source messages, empty value indicate no conversion",
+			Options:     []string{"json", "protobuf", "avro", "msgpack", "cbor", ""},
+		},
+		{
+			Name:        "convert_target_format",
+			Kind:        config.PropertyKindString,
+			Description: "payload format the messages are converted to before sending to the targets",
+			Options:     []string{"json", "protobuf", "avro", "msgpack", "cbor", ""},
+		},
+		{
+			Name:        "convert_source_schema",
+			Kind:        config.PropertyKindString,
+			Description: "schema file of the source format, .proto file or descriptor set for protobuf, .avsc file for avro",
+			Default:     "",
+		},
+		{
+			Name:        "convert_target_schema",
+			Kind:        config.PropertyKindString,
+			Description: "schema file of the target format, .proto file or descriptor set for protobuf, .avsc file for avro",
+			Default:     "",
+		},
+		{
+			Name:        "convert_source_message_type",
+			Kind:        config.PropertyKindString,
+			Description: "full name of the source protobuf message type, may be omitted when the schema defines one message type",
+			Default:     "",
+		},
+		{
+			Name:        "convert_target_message_type",
+			Kind:        config.PropertyKindString,
+			Description: "full name of the target protobuf message type, may be omitted when the schema defines one message type",
+			Default:     "",
+		},
+		{
+			Name:        "convert_import_paths",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated directories searched for the imports of .proto schema files",
+			Default:     "",
+		},
+		{
+			Name:        "validate_registry_dir",
+			Kind:        config.PropertyKindString,
+			Description: "schema registry directory of the schemas messages are validated against, empty value indicate no validation",
+			Default:     "",
+		},
+		{
+			Name:        "validate_import_paths",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated directories searched for the imports of .proto schema files, in addition to the registry directory",
+			Default:     "",
+		},
+		{
+			Name:        "validate_schema_tag",
+			Kind:        config.PropertyKindString,
+			Description: "message tag key which holds the schema id of the message",
+			Default:     "schema-id",
+		},
+		{
+			Name:        "validate_channel_schemas",
+			Kind:        config.PropertyKindMap,
+			Description: "json map of channel names to the schema ids of messages without a schema id tag",
+			Default:     "",
+		},
+		{
+			Name:        "validate_on_invalid",
+			Kind:        config.PropertyKindString,
+			Description: "set how a message which fails validation is handled",
+			Default:     "reject",
+			Options:     []string{"reject", "dead-letter", "tag"},
+		},
+		{
+			Name:        "validate_error_tag",
+			Kind:        config.PropertyKindString,
+			Description: "message tag key set with the validation error of dead-lettered and tagged messages",
+			Default:     "schema-error",
+		},
+		{
+			Name:        "validate_dead_letter_channel",
+			Kind:        config.PropertyKindString,
+			Description: "queue channel invalid messages are sent to in dead-letter mode",
+			Default:     "",
+		},
+		{
+			Name:        "validate_dead_letter_address",
+			Kind:        config.PropertyKindString,
+			Description: "kubemq server address (gRPC interface) of the dead letter channel",
+			Default:     "localhost:50000",
+		},
+		{
+			Name:        "validate_dead_letter_auth_token",
+			Kind:        config.PropertyKindString,
+			Description: "authentication token of the dead letter channel kubemq server",
+			Default:     "",
+		},
+		{
+			Name:        "compress_codec",
+			Kind:        config.PropertyKindString,
+			Description: "codec the message bodies are compressed with before sending to the targets, empty value indicate no compression",
+			Options:     []string{"gzip", "zstd", "snappy", "lz4", ""},
+		},
+		{
+			Name:        "compress_min_size",
+			Kind:        config.PropertyKindInt,
+			Description: "min body size in bytes to compress",
+			Default:     "1024",
+			Min:         0,
+			Max:         1024 * 1024 * 1024,
+		},
+		{
+			Name:        "compress_tag",
+			Kind:        config.PropertyKindString,
+			Description: "message tag key which holds the codec of compressed messages",
+			Default:     defaultCompressTag,
+		},
+		{
+			Name:        "decompress",
+			Kind:        config.PropertyKindBool,
+			Description: "decompress messages which hold a codec in the compress tag",
+			Default:     "true",
+		},
+		{
+			Name:        "decompress_max_size",
+			Kind:        config.PropertyKindInt,
+			Description: "max decompressed body size in bytes",
+			Default:     "67108864",
+			Min:         1,
+			Max:         1024 * 1024 * 1024,
+		},
+		{
+			Name:        "encrypt_algorithm",
+			Kind:        config.PropertyKindString,
+			Description: "algorithm of the message bodies encryption and decryption, empty value indicate no encryption",
+			Options:     []string{"aes-gcm", "nacl-box", ""},
+		},
+		{
+			Name:        "encrypt_key_files",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated key files, aes keys or recipient public keys, the message bodies are encrypted with the first key",
+			Default:     "",
+		},
+		{
+			Name:        "decrypt_key_files",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated key files, aes keys or recipient private keys, encrypted messages are decrypted with",
+			Default:     "",
+		},
+		{
+			Name:        "sign_algorithm",
+			Kind:        config.PropertyKindString,
+			Description: "algorithm of the message bodies signatures, empty value indicate no signing",
+			Options:     []string{"hmac-sha256", "ed25519", ""},
+		},
+		{
+			Name:        "sign_key_files",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated key files, hmac secrets or ed25519 private keys, the message bodies are signed with the first key",
+			Default:     "",
+		},
+		{
+			Name:        "sign_channel",
+			Kind:        config.PropertyKindBool,
+			Description: "sign the message channel, only when the targets send to the source message channel",
+			Default:     "false",
+		},
+		{
+			Name:        "verify_key_files",
+			Kind:        config.PropertyKindString,
+			Description: "comma separated key files, hmac secrets or ed25519 public keys, messages signatures are verified with",
+			Default:     "",
+		},
+		{
+			Name:        "script_file",
+			Kind:        config.PropertyKindString,
+			Description: "lua or javascript file which process function transforms, filters or splits the messages, empty value indicate no script",
+			Default:     "",
+		},
+		{
+			Name:        "script_language",
+			Kind:        config.PropertyKindString,
+			Description: "script language, empty value indicate the language of the script file extension",
+			Options:     []string{"lua", "javascript", ""},
+		},
+		{
+			Name:        "script_timeout_milliseconds",
+			Kind:        config.PropertyKindInt,
+			Description: "max run time of a script call in milliseconds",
+			Default:     "1000",
+			Min:         1,
+			Max:         60 * 60 * 1000,
+		},
+		{
+			Name:        "script_max_stack",
+			Kind:        config.PropertyKindInt,
+			Description: "max call stack depth of a script call",
+			Default:     "256",
+			Min:         16,
+			Max:         1024 * 1024,
+		},
+		{
+			Name:        "script_max_messages",
+			Kind:        config.PropertyKindInt,
+			Description: "max number of messages a script call may return",
+			Default:     "100",
+			Min:         1,
+			Max:         1024 * 1024,
+		},
+		{
+			Name:        "script_max_output_size",
+			Kind:        config.PropertyKindInt,
+			Description: "max total body size in bytes of the messages a script call may return",
+			Default:     "4194304",
+			Min:         1,
+			Max:         1024 * 1024 * 1024,
+		},
+		{
+			Name:        "script_max_heap_growth",
+			Kind:        config.PropertyKindInt,
+			Description: "max growth in bytes of the process heap while a script call runs, 0 - no limit",
+			Default:     "0",
+			Min:         0,
+			Max:         1024 * 1024 * 1024,
+		},
+		{
+			Name:        "wasm_module_file",
+			Kind:        config.PropertyKindString,
+			Description: "webassembly module file which process function transforms, filters or splits the messages, empty value indicate no module",
+			Default:     "",
+		},
+		{
+			Name:        "wasm_timeout_milliseconds",
+			Kind:        config.PropertyKindInt,
+			Description: "max run time of a module call in milliseconds",
+			Default:     "1000",
+			Min:         1,
+			Max:         60 * 60 * 1000,
+		},
+		{
+			Name:        "wasm_pool_size",
+			Kind:        config.PropertyKindInt,
+			Description: "max number of module instances, calls wait for an instance when all are in use",
+			Default:     "4",
+			Min:         1,
+			Max:         1024,
+		},
+		{
+			Name:        "wasm_max_memory_pages",
+			Kind:        config.PropertyKindInt,
+			Description: "max memory size of a module instance in 64KB pages",
+			Default:     "256",
+			Min:         1,
+			Max:         65536,
+		},
+		{
+			Name:        "aggregate_format",
+			Kind:        config.PropertyKindString,
+			Description: "format of the batch bodies messages are aggregated into, empty value indicate no aggregation",
+			Options:     []string{"json", "lines", ""},
+		},
+		{
+			Name:        "aggregate_count",
+			Kind:        config.PropertyKindInt,
+			Description: "max number of messages in a batch",
+			Default:     "100",
+			Min:         1,
+			Max:         1024 * 1024,
+		},
+		{
+			Name:        "aggregate_window_milliseconds",
+			Kind:        config.PropertyKindInt,
+			Description: "max time in milliseconds a batch waits for messages",
+			Default:     "1000",
+			Min:         1,
+			Max:         60 * 60 * 1000,
+		},
+		{
+			Name:        "aggregate_wait",
+			Kind:        config.PropertyKindBool,
+			Description: "wait for the batch to be sent before completing the source messages, false completes them when added and loses the messages of failed batches",
+			Default:     "true",
+		},
+		{
+			Name:        "aggregate_tag",
+			Kind:        config.PropertyKindString,
+			Description: "message tag key which holds the number of messages of a batch",
+			Default:     "batch-size",
+		},
+		{
+			Name:        "split_format",
+			Kind:        config.PropertyKindString,
+			Description: "format of the bodies split into a message per item, empty value indicate no splitting",
+			Options:     []string{"json", "lines", ""},
+		},
+		{
+			Name:        "enrich_channel",
+			Kind:        config.PropertyKindString,
+			Description: "query channel the enrichment lookups are sent to, empty value indicate no enrichment",
+			Default:     "",
+		},
+		{
+			Name:        "enrich_address",
+			Kind:        config.PropertyKindString,
+			Description: "kubemq server address (gRPC interface) of the enrichment channel",
+			Default:     "localhost:50000",
+		},
+		{
+			Name:        "enrich_auth_token",
+			Kind:        config.PropertyKindString,
+			Description: "authentication token of the enrichment channel kubemq server",
+			Default:     "",
+		},
+		{
+			Name:        "enrich_timeout_seconds",
+			Kind:        config.PropertyKindInt,
+			Description: "enrichment lookup query timeout in seconds",
+			Default:     "5",
+			Min:         1,
+			Max:         60 * 60,
+		},
+		{
+			Name:        "enrich_key",
+			Kind:        config.PropertyKindString,
+			Description: "message field of the lookup key, body, body:<json field path>, tag:<name>, metadata or channel",
+			Default:     "body",
+		},
+		{
+			Name:        "enrich_merge",
+			Kind:        config.PropertyKindString,
+			Description: "how the lookup response is merged into the message",
+			Options:     []string{"tags", "body"},
+		},
+		{
+			Name:        "enrich_tag_prefix",
+			Kind:        config.PropertyKindString,
+			Description: "prefix of the response tags merged into the message tags",
+			Default:     "",
+		},
+		{
+			Name:        "enrich_body_field",
+			Kind:        config.PropertyKindString,
+			Description: "message body json field the response body is set in, empty value indicate merging the response json fields",
+			Default:     "",
+		},
+		{
+			Name:        "enrich_on_error",
+			Kind:        config.PropertyKindString,
+			Description: "how a message which lookup fails is handled",
+			Options:     []string{"reject", "skip"},
+		},
+		{
+			Name:        "enrich_cache_ttl_seconds",
+			Kind:        config.PropertyKindInt,
+			Description: "time to live of the cached lookup responses in seconds, 0 indicate no caching",
+			Default:     "60",
+			Min:         0,
+			Max:         7 * 24 * 60 * 60,
+		},
+		{
+			Name:        "enrich_cache_size",
+			Kind:        config.PropertyKindInt,
+			Description: "max number of cached lookup responses",
+			Default:     "10000",
+			Min:         1,
+			Max:         10000000,
+		},
+	}
+}
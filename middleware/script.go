@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/script"
+)
+
+var scriptLanguageMap = map[string]string{
+	"":                        "",
+	script.LanguageLua:        script.LanguageLua,
+	script.LanguageJavaScript: script.LanguageJavaScript,
+	"js":                      script.LanguageJavaScript,
+}
+
+type ScriptMiddleware struct {
+	script *script.Script
+}
+
+func NewScriptMiddleware(meta config.Metadata, log *logger.Logger) (*ScriptMiddleware, error) {
+	s := &ScriptMiddleware{}
+	file := meta.ParseString("script_file", "")
+	if file == "" {
+		return s, nil
+	}
+	language, err := meta.ParseStringMap("script_language", scriptLanguageMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script language value, %w", err)
+	}
+	if language == "" {
+		if language, err = script.Language(file); err != nil {
+			return nil, fmt.Errorf("invalid script language value, %w", err)
+		}
+	}
+	timeout, err := meta.ParseIntWithRange("script_timeout_milliseconds", 1000, 1, 60*60*1000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script timeout milliseconds value, %w", err)
+	}
+	opts := script.Options{
+		Timeout: time.Duration(timeout) * time.Millisecond,
+	}
+	opts.MaxStack, err = meta.ParseIntWithRange("script_max_stack", 256, 16, 1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script max stack value, %w", err)
+	}
+	opts.MaxMessages, err = meta.ParseIntWithRange("script_max_messages", 100, 1, 1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script max messages value, %w", err)
+	}
+	opts.MaxOutputSize, err = meta.ParseIntWithRange("script_max_output_size", 4*1024*1024, 1, 1024*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script max output size value, %w", err)
+	}
+	opts.MaxHeapGrowth, err = meta.ParseIntWithRange("script_max_heap_growth", 0, 0, 1024*1024*1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script max heap growth value, %w", err)
+	}
+	s.script, err = script.New(file, language, opts, log)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// process returns the requests built from the messages the script returns for the request, none when the script drops it
+func (s *ScriptMiddleware) process(ctx context.Context, request interface{}) ([]interface{}, error) {
+	fields, ok := message.GetFields(request)
+	if !ok {
+		return nil, fmt.Errorf("scripts are not supported for message type %T", request)
+	}
+	result, err := s.script.Process(ctx, script.Message{
+		Channel:  fields.Channel,
+		Metadata: fields.Metadata,
+		Body:     fields.Body,
+		Tags:     fields.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list []message.Fields
+	for _, msg := range result {
+		list = append(list, message.Fields{
+			Channel:  msg.Channel,
+			Metadata: msg.Metadata,
+			Body:     msg.Body,
+			Tags:     msg.Tags,
+		})
+	}
+	return withFields(request, list)
+}
+
+// Close stops the script reloads
+func (s *ScriptMiddleware) Close() error {
+	if s.script != nil {
+		return s.script.Close()
+	}
+	return nil
+}
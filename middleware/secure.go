@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/secure"
+)
+
+const (
+	encryptionKeyIdTag = "encryption-key-id"
+	signatureTag       = "signature"
+	signatureKeyIdTag  = "signature-key-id"
+)
+
+var encryptAlgorithmMap = map[string]string{
+	"":                      "",
+	secure.AlgorithmAESGCM:  secure.AlgorithmAESGCM,
+	secure.AlgorithmNaClBox: secure.AlgorithmNaClBox,
+}
+
+var signAlgorithmMap = map[string]string{
+	"":                         "",
+	secure.AlgorithmHMACSHA256: secure.AlgorithmHMACSHA256,
+	secure.AlgorithmEd25519:    secure.AlgorithmEd25519,
+}
+
+type SecureMiddleware struct {
+	encrypter   *secure.Encrypter
+	decrypter   *secure.Decrypter
+	signer      *secure.Signer
+	verifier    *secure.Verifier
+	signChannel bool
+}
+
+func parseKeyFiles(meta config.Metadata, key string) []string {
+	var files []string
+	for _, file := range meta.ParseStringList(key) {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+func loadKeys(meta config.Metadata, key string, sizes []int) ([]secure.Key, error) {
+	files := parseKeyFiles(meta, key)
+	if len(files) == 0 {
+		return nil, nil
+	}
+	keys, err := secure.LoadKeys(files, sizes...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value, %w", strings.ReplaceAll(key, "_", " "), err)
+	}
+	return keys, nil
+}
+
+func NewSecureMiddleware(meta config.Metadata) (*SecureMiddleware, error) {
+	s := &SecureMiddleware{
+		signChannel: meta.ParseBool("sign_channel", false),
+	}
+	encryptAlgorithm, err := meta.ParseStringMap("encrypt_algorithm", encryptAlgorithmMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypt algorithm value, %w", err)
+	}
+	if encryptAlgorithm != "" {
+		sizes, _ := secure.EncryptionKeySizes(encryptAlgorithm)
+		encryptKeys, err := loadKeys(meta, "encrypt_key_files", sizes)
+		if err != nil {
+			return nil, err
+		}
+		decryptKeys, err := loadKeys(meta, "decrypt_key_files", sizes)
+		if err != nil {
+			return nil, err
+		}
+		if encryptKeys == nil && decryptKeys == nil {
+			return nil, fmt.Errorf("encrypt algorithm requires encrypt key files or decrypt key files")
+		}
+		if encryptKeys != nil {
+			if s.encrypter, err = secure.NewEncrypter(encryptAlgorithm, encryptKeys); err != nil {
+				return nil, err
+			}
+		}
+		if decryptKeys != nil {
+			if s.decrypter, err = secure.NewDecrypter(encryptAlgorithm, decryptKeys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	signAlgorithm, err := meta.ParseStringMap("sign_algorithm", signAlgorithmMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sign algorithm value, %w", err)
+	}
+	if signAlgorithm != "" {
+		signSizes, _ := secure.SigningKeySizes(signAlgorithm)
+		signKeys, err := loadKeys(meta, "sign_key_files", signSizes)
+		if err != nil {
+			return nil, err
+		}
+		verifySizes, _ := secure.VerifyingKeySizes(signAlgorithm)
+		verifyKeys, err := loadKeys(meta, "verify_key_files", verifySizes)
+		if err != nil {
+			return nil, err
+		}
+		if signKeys == nil && verifyKeys == nil {
+			return nil, fmt.Errorf("sign algorithm requires sign key files or verify key files")
+		}
+		if signKeys != nil {
+			if s.signer, err = secure.NewSigner(signAlgorithm, signKeys); err != nil {
+				return nil, err
+			}
+		}
+		if verifyKeys != nil {
+			if s.verifier, err = secure.NewVerifier(signAlgorithm, verifyKeys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// encrypt returns a copy of the request with the body encrypted and the key id set in the tags, encrypted requests are returned as is
+func (s *SecureMiddleware) encrypt(request interface{}) (interface{}, error) {
+	body, ok := message.Body(request)
+	if !ok {
+		return nil, fmt.Errorf("encryption is not supported for message type %T", request)
+	}
+	tags := message.Tags(request)
+	if tags[encryptionKeyIdTag] != "" {
+		return request, nil
+	}
+	keyId, encrypted, err := s.encrypter.Encrypt(body)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting message body, %w", err)
+	}
+	tags = message.CopyTags(tags)
+	tags[encryptionKeyIdTag] = keyId
+	return message.Clone(request, encrypted, tags)
+}
+
+// decrypt returns a copy of the request with the body decrypted by the key set in the tags, unencrypted requests are returned as is
+func (s *SecureMiddleware) decrypt(request interface{}) (interface{}, error) {
+	tags := message.Tags(request)
+	keyId := tags[encryptionKeyIdTag]
+	if keyId == "" {
+		return request, nil
+	}
+	body, _ := message.Body(request)
+	decrypted, err := s.decrypter.Decrypt(keyId, body)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting message body, %w", err)
+	}
+	tags = message.CopyTags(tags)
+	delete(tags, encryptionKeyIdTag)
+	return message.Clone(request, decrypted, tags)
+}
+
+// signedContent returns the canonical encoding of the request channel, metadata, body and sorted tags, without the signature tags,
+// each value is prefixed by its length so values cannot be moved between fields
+func (s *SecureMiddleware) signedContent(request interface{}) ([]byte, error) {
+	body, ok := message.Body(request)
+	if !ok {
+		return nil, fmt.Errorf("signing is not supported for message type %T", request)
+	}
+	buf := &bytes.Buffer{}
+	write := func(value []byte) {
+		buf.Write(binary.AppendUvarint(nil, uint64(len(value))))
+		buf.Write(value)
+	}
+	channel := ""
+	if s.signChannel {
+		channel = message.Channel(request)
+	}
+	write([]byte(channel))
+	write([]byte(message.Metadata(request)))
+	write(body)
+	tags := message.Tags(request)
+	var keys []string
+	for key := range tags {
+		if key != signatureTag && key != signatureKeyIdTag {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		write([]byte(key))
+		write([]byte(tags[key]))
+	}
+	return buf.Bytes(), nil
+}
+
+// sign returns a copy of the request with the signature of its content and the signing key id set in the tags
+func (s *SecureMiddleware) sign(request interface{}) (interface{}, error) {
+	content, err := s.signedContent(request)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := message.Body(request)
+	keyId, signature := s.signer.Sign(content)
+	tags := message.CopyTags(message.Tags(request))
+	tags[signatureTag] = base64.StdEncoding.EncodeToString(signature)
+	tags[signatureKeyIdTag] = keyId
+	return message.Clone(request, body, tags)
+}
+
+// verify returns a copy of the request without the signature tags, failing requests which are not signed or which signature does not match their content
+func (s *SecureMiddleware) verify(request interface{}) (interface{}, error) {
+	tags := message.Tags(request)
+	if tags[signatureTag] == "" || tags[signatureKeyIdTag] == "" {
+		return nil, fmt.Errorf("message signature is missing")
+	}
+	signature, err := base64.StdEncoding.DecodeString(tags[signatureTag])
+	if err != nil {
+		return nil, fmt.Errorf("invalid message signature, %w", err)
+	}
+	content, err := s.signedContent(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifier.Verify(tags[signatureKeyIdTag], content, signature); err != nil {
+		return nil, fmt.Errorf("message signature verification failed, %w", err)
+	}
+	tags = message.CopyTags(tags)
+	delete(tags, signatureTag)
+	delete(tags, signatureKeyIdTag)
+	body, _ := message.Body(request)
+	return message.Clone(request, body, tags)
+}
@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/schema"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+const (
+	onInvalidReject     = "reject"
+	onInvalidDeadLetter = "dead-letter"
+	onInvalidTag        = "tag"
+
+	// deadLetterTTL is how long a dead letter send is kept, so the target chains of a source message send it once
+	deadLetterTTL = time.Minute
+)
+
+var onInvalidMap = map[string]string{
+	"":                  onInvalidReject,
+	onInvalidReject:     onInvalidReject,
+	onInvalidDeadLetter: onInvalidDeadLetter,
+	onInvalidTag:        onInvalidTag,
+}
+
+type ValidateMiddleware struct {
+	sync.Mutex
+	registry          *schema.Registry
+	schemaTag         string
+	channelSchemas    map[string]string
+	onInvalid         string
+	errorTag          string
+	deadLetterChannel string
+	poolOpts          pool.Options
+	client            *kubemq.Client
+	send              func(ctx context.Context, msg *kubemq.QueueMessage) error
+	deadLetters       map[deadLetterKey]*deadLetterSend
+	log               *logger.Logger
+}
+
+// deadLetterKey is an invalid request of a source message, the same for each target chain the source message is sent to
+type deadLetterKey struct {
+	source interface{}
+	body   string
+}
+
+// deadLetterSend is the dead letter send of an invalid request, its callers wait for done to get the send error
+type deadLetterSend struct {
+	done    chan struct{}
+	err     error
+	expires time.Time
+}
+
+func NewValidateMiddleware(name string, meta config.Metadata, log *logger.Logger) (*ValidateMiddleware, error) {
+	v := &ValidateMiddleware{
+		log: log,
+	}
+	dir := meta.ParseString("validate_registry_dir", "")
+	if dir == "" {
+		return v, nil
+	}
+	var err error
+	v.onInvalid, err = meta.ParseStringMap("validate_on_invalid", onInvalidMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validate on invalid value, %w", err)
+	}
+	v.schemaTag = meta.ParseString("validate_schema_tag", "schema-id")
+	v.errorTag = meta.ParseString("validate_error_tag", "schema-error")
+	v.channelSchemas = map[string]string{}
+	if meta.Get("validate_channel_schemas") != "" {
+		v.channelSchemas, err = meta.MustParseJsonMap("validate_channel_schemas")
+		if err != nil {
+			return nil, fmt.Errorf("invalid validate channel schemas value, %w", err)
+		}
+	}
+	if v.onInvalid == onInvalidDeadLetter {
+		v.deadLetterChannel, err = meta.MustParseString("validate_dead_letter_channel")
+		if err != nil {
+			return nil, fmt.Errorf("dead-letter on invalid requires a dead letter channel, %w", err)
+		}
+		host, port, err := meta.MustParseAddress("validate_dead_letter_address", "localhost:50000")
+		if err != nil {
+			return nil, fmt.Errorf("invalid validate dead letter address value, %w", err)
+		}
+		v.poolOpts = pool.Options{
+			Host:      host,
+			Port:      port,
+			AuthToken: meta.ParseString("validate_dead_letter_auth_token", ""),
+			ClientId:  fmt.Sprintf("%s-dead-letter", name),
+		}
+		v.send = v.sendDeadLetter
+		v.deadLetters = map[deadLetterKey]*deadLetterSend{}
+	}
+	var importPaths []string
+	for _, path := range meta.ParseStringList("validate_import_paths") {
+		if path = strings.TrimSpace(path); path != "" {
+			importPaths = append(importPaths, path)
+		}
+	}
+	v.registry, err = schema.NewRegistry(dir, importPaths, log)
+	if err != nil {
+		return nil, err
+	}
+	for channel, id := range v.channelSchemas {
+		if _, ok := v.registry.Get(id); !ok {
+			_ = v.registry.Close()
+			return nil, fmt.Errorf("schema %s of channel %s not found in the schema registry", id, channel)
+		}
+	}
+	return v, nil
+}
+
+// schemaId returns the schema id set in the message tags, or the schema id of the message channel
+func (v *ValidateMiddleware) schemaId(request interface{}) string {
+	if id := message.Tags(request)[v.schemaTag]; id != "" {
+		return id
+	}
+	return v.channelSchemas[message.Channel(request)]
+}
+
+// validate returns the validation error of the request, nil for a valid request or a request without a schema
+func (v *ValidateMiddleware) validate(request interface{}) error {
+	id := v.schemaId(request)
+	if id == "" {
+		return nil
+	}
+	validator, ok := v.registry.Get(id)
+	if !ok {
+		return fmt.Errorf("schema %s not found", id)
+	}
+	body, ok := message.Body(request)
+	if !ok {
+		return fmt.Errorf("validation is not supported for message type %T", request)
+	}
+	if err := validator.Validate(body); err != nil {
+		return fmt.Errorf("schema %s validation failed, %w", id, err)
+	}
+	return nil
+}
+
+// tag returns a copy of the request with the validation error set in the error tag
+func (v *ValidateMiddleware) tag(request interface{}, validationErr error) (interface{}, error) {
+	body, _ := message.Body(request)
+	tags := message.CopyTags(message.Tags(request))
+	tags[v.errorTag] = validationErr.Error()
+	return message.Clone(request, body, tags)
+}
+
+// deadLetterOnce sends the invalid request to the dead letter channel once per source message, the other target chains of the
+// source message wait for the send and get its error. It returns true for the caller which sent it.
+func (v *ValidateMiddleware) deadLetterOnce(ctx context.Context, request interface{}, validationErr error) (bool, error) {
+	source := sourceMessage(ctx)
+	if source == nil || !reflect.TypeOf(source).Comparable() {
+		return true, v.deadLetter(ctx, request, validationErr)
+	}
+	body, _ := message.Body(request)
+	key := deadLetterKey{source: source, body: string(body)}
+	now := time.Now()
+	v.Lock()
+	for k, sent := range v.deadLetters {
+		if !sent.expires.IsZero() && now.After(sent.expires) {
+			delete(v.deadLetters, k)
+		}
+	}
+	if sent, ok := v.deadLetters[key]; ok {
+		v.Unlock()
+		select {
+		case <-sent.done:
+			return false, sent.err
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	sent := &deadLetterSend{done: make(chan struct{})}
+	v.deadLetters[key] = sent
+	v.Unlock()
+	err := v.deadLetter(ctx, request, validationErr)
+	v.Lock()
+	sent.err = err
+	sent.expires = time.Now().Add(deadLetterTTL)
+	v.Unlock()
+	close(sent.done)
+	return true, err
+}
+
+func (v *ValidateMiddleware) deadLetter(ctx context.Context, request interface{}, validationErr error) error {
+	body, _ := message.Body(request)
+	tags := message.CopyTags(message.Tags(request))
+	tags[v.errorTag] = validationErr.Error()
+	msg := kubemq.NewQueueMessage().
+		SetChannel(v.deadLetterChannel).
+		SetMetadata(message.Metadata(request)).
+		SetBody(body).
+		SetTags(tags)
+	if err := v.send(ctx, msg); err != nil {
+		return fmt.Errorf("error sending invalid message to dead letter channel %s, %w", v.deadLetterChannel, err)
+	}
+	return nil
+}
+
+func (v *ValidateMiddleware) sendDeadLetter(ctx context.Context, msg *kubemq.QueueMessage) error {
+	v.Lock()
+	if v.client == nil {
+		client, err := pool.GetClient(ctx, v.poolOpts)
+		if err != nil {
+			v.Unlock()
+			return err
+		}
+		v.client = client
+	}
+	client := v.client
+	v.Unlock()
+	result, err := client.SendQueueMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// Close stops the schema registry reloads and releases the dead letter connection
+func (v *ValidateMiddleware) Close() error {
+	v.Lock()
+	defer v.Unlock()
+	if v.client != nil {
+		pool.ReleaseClient(v.client)
+		v.client = nil
+	}
+	if v.registry != nil {
+		return v.registry.Close()
+	}
+	return nil
+}
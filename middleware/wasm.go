@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/wasm"
+)
+
+type WasmMiddleware struct {
+	plugin *wasm.Plugin
+}
+
+func NewWasmMiddleware(ctx context.Context, meta config.Metadata) (*WasmMiddleware, error) {
+	w := &WasmMiddleware{}
+	file := meta.ParseString("wasm_module_file", "")
+	if file == "" {
+		return w, nil
+	}
+	timeout, err := meta.ParseIntWithRange("wasm_timeout_milliseconds", 1000, 1, 60*60*1000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wasm timeout milliseconds value, %w", err)
+	}
+	opts := wasm.Options{
+		Timeout: time.Duration(timeout) * time.Millisecond,
+	}
+	opts.PoolSize, err = meta.ParseIntWithRange("wasm_pool_size", 4, 1, 1024)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wasm pool size value, %w", err)
+	}
+	maxMemoryPages, err := meta.ParseIntWithRange("wasm_max_memory_pages", 256, 1, 65536)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wasm max memory pages value, %w", err)
+	}
+	opts.MaxMemoryPages = uint32(maxMemoryPages)
+	w.plugin, err = wasm.New(ctx, file, opts)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// process returns the requests built from the messages the module returns for the request, none when the module drops it
+func (w *WasmMiddleware) process(ctx context.Context, request interface{}) ([]interface{}, error) {
+	fields, ok := message.GetFields(request)
+	if !ok {
+		return nil, fmt.Errorf("wasm modules are not supported for message type %T", request)
+	}
+	result, err := w.plugin.Process(ctx, wasm.Message{
+		Channel:  fields.Channel,
+		Metadata: fields.Metadata,
+		Body:     fields.Body,
+		Tags:     fields.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list []message.Fields
+	for _, msg := range result {
+		list = append(list, message.Fields{
+			Channel:  msg.Channel,
+			Metadata: msg.Metadata,
+			Body:     msg.Body,
+			Tags:     msg.Tags,
+		})
+	}
+	return withFields(request, list)
+}
+
+// Close closes the module instances
+func (w *WasmMiddleware) Close() error {
+	if w.plugin != nil {
+		return w.plugin.Close(context.Background())
+	}
+	return nil
+}
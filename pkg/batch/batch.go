@@ -0,0 +1,73 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// FormatJSON batches bodies in a json array, bodies which are not json are set as json strings
+	FormatJSON = "json"
+	// FormatLines batches bodies as newline delimited lines
+	FormatLines = "lines"
+)
+
+// Join returns the batch body of bodies in a format
+func Join(format string, bodies [][]byte) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		items := make([]json.RawMessage, len(bodies))
+		for i, body := range bodies {
+			if json.Valid(body) {
+				items[i] = bytes.TrimSpace(body)
+				continue
+			}
+			item, err := json.Marshal(string(body))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return json.Marshal(items)
+	case FormatLines:
+		return bytes.Join(bodies, []byte("\n")), nil
+	default:
+		return nil, fmt.Errorf("invalid batch format %s", format)
+	}
+}
+
+// Split returns the bodies of a batch body in a format, json string items are returned as their string value
+func Split(format string, body []byte) ([][]byte, error) {
+	switch format {
+	case FormatJSON:
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("batch body is not a json array, %w", err)
+		}
+		bodies := make([][]byte, len(items))
+		for i, item := range items {
+			if len(item) > 0 && item[0] == '"' {
+				var s string
+				if err := json.Unmarshal(item, &s); err != nil {
+					return nil, err
+				}
+				bodies[i] = []byte(s)
+				continue
+			}
+			bodies[i] = item
+		}
+		return bodies, nil
+	case FormatLines:
+		var bodies [][]byte
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			line = bytes.TrimSuffix(line, []byte("\r"))
+			if len(line) > 0 {
+				bodies = append(bodies, line)
+			}
+		}
+		return bodies, nil
+	default:
+		return nil, fmt.Errorf("invalid batch format %s", format)
+	}
+}
@@ -0,0 +1,86 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin_Split(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		bodies [][]byte
+		want   string
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			bodies: [][]byte{[]byte(`{"id":1}`), []byte(` [1,2] `), []byte(`text`), []byte(`"quoted"`)},
+			want:   `[{"id":1},[1,2],"text","quoted"]`,
+		},
+		{
+			name:   "lines",
+			format: FormatLines,
+			bodies: [][]byte{[]byte(`{"id":1}`), []byte(`text`)},
+			want:   "{\"id\":1}\ntext",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined, err := Join(tt.format, tt.bodies)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(joined))
+			split, err := Split(tt.format, joined)
+			require.NoError(t, err)
+			require.Len(t, split, len(tt.bodies))
+			require.Equal(t, []byte(`{"id":1}`), split[0])
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		body    string
+		want    [][]byte
+		wantErr bool
+	}{
+		{
+			name:   "json - empty array",
+			format: FormatJSON,
+			body:   `[]`,
+			want:   [][]byte{},
+		},
+		{
+			name:   "lines - empty and crlf lines",
+			format: FormatLines,
+			body:   "a\r\n\nb\n",
+			want:   [][]byte{[]byte("a"), []byte("b")},
+		},
+		{
+			name:    "invalid - json object",
+			format:  FormatJSON,
+			body:    `{"id":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - format",
+			format:  "csv",
+			body:    `a,b`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.format, []byte(tt.body))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package bridgespb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+// ToKubemq returns the kubemq message of a bridge message, in the form a source of the same type sends it to the targets
+func (x *Message) ToKubemq() interface{} {
+	switch x.GetType() {
+	case Type_TYPE_EVENT:
+		return &kubemq.Event{Id: x.GetId(), Channel: x.GetChannel(), Metadata: x.GetMetadata(), Body: x.GetBody(), Tags: x.GetTags()}
+	case Type_TYPE_EVENT_STORE:
+		return &kubemq.EventStoreReceive{Id: x.GetId(), Timestamp: time.Now().UTC(), Channel: x.GetChannel(), Metadata: x.GetMetadata(), Body: x.GetBody(), Tags: x.GetTags()}
+	case Type_TYPE_COMMAND:
+		return &kubemq.CommandReceive{Id: x.GetId(), Channel: x.GetChannel(), Metadata: x.GetMetadata(), Body: x.GetBody(), Tags: x.GetTags()}
+	case Type_TYPE_QUEUE:
+		return kubemq.NewQueueMessage().
+			SetId(x.GetId()).
+			SetChannel(x.GetChannel()).
+			SetMetadata(x.GetMetadata()).
+			SetBody(x.GetBody()).
+			SetTags(x.GetTags())
+	default:
+		return &kubemq.QueryReceive{Id: x.GetId(), Channel: x.GetChannel(), Metadata: x.GetMetadata(), Body: x.GetBody(), Tags: x.GetTags()}
+	}
+}
+
+// NewMessage returns the bridge message of a kubemq message
+func NewMessage(request interface{}) (*Message, error) {
+	switch val := request.(type) {
+	case *kubemq.Event:
+		return &Message{Id: val.Id, Type: Type_TYPE_EVENT, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	case *kubemq.EventStoreReceive:
+		return &Message{Id: val.Id, Type: Type_TYPE_EVENT_STORE, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	case *kubemq.CommandReceive:
+		return &Message{Id: val.Id, Type: Type_TYPE_COMMAND, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	case *kubemq.QueryReceive:
+		return &Message{Id: val.Id, Type: Type_TYPE_QUERY, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	case *kubemq.QueueMessage:
+		return &Message{Id: val.MessageID, Type: Type_TYPE_QUEUE, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	case *queues_stream.QueueMessage:
+		return &Message{Id: val.MessageID, Type: Type_TYPE_QUEUE, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}, nil
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+}
+
+// NewResponse returns the bridge response of a message from the targets result, a result which is not a query or a command response
+// returns an executed response without a body
+func NewResponse(id string, result interface{}, err error) *Response {
+	resp := &Response{Id: id, Executed: err == nil}
+	switch val := result.(type) {
+	case *kubemq.QueryResponse:
+		if val != nil {
+			resp.Executed = err == nil && val.Executed
+			resp.Error = val.Error
+			resp.Metadata = val.Metadata
+			resp.Body = val.Body
+			resp.Tags = val.Tags
+		}
+	case *kubemq.CommandResponse:
+		if val != nil {
+			resp.Executed = err == nil && val.Executed
+			resp.Error = val.Error
+			resp.Tags = val.Tags
+		}
+	}
+	if err != nil && resp.Error == "" {
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// ToQueryResponse returns the response as a query response, which can be returned to any source
+func (x *Response) ToQueryResponse() *kubemq.QueryResponse {
+	return &kubemq.QueryResponse{
+		QueryId:    x.GetId(),
+		Executed:   x.GetExecuted(),
+		ExecutedAt: time.Now(),
+		Metadata:   x.GetMetadata(),
+		Body:       x.GetBody(),
+		Error:      x.GetError(),
+		Tags:       x.GetTags(),
+	}
+}
@@ -0,0 +1,80 @@
+package bridgespb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_RoundTrip(t *testing.T) {
+	tags := map[string]string{"key": "value"}
+	tests := []struct {
+		name     string
+		msgType  Type
+		wantType interface{}
+	}{
+		{name: "unspecified", msgType: Type_TYPE_UNSPECIFIED, wantType: &kubemq.QueryReceive{}},
+		{name: "event", msgType: Type_TYPE_EVENT, wantType: &kubemq.Event{}},
+		{name: "event store", msgType: Type_TYPE_EVENT_STORE, wantType: &kubemq.EventStoreReceive{}},
+		{name: "command", msgType: Type_TYPE_COMMAND, wantType: &kubemq.CommandReceive{}},
+		{name: "query", msgType: Type_TYPE_QUERY, wantType: &kubemq.QueryReceive{}},
+		{name: "queue", msgType: Type_TYPE_QUEUE, wantType: &kubemq.QueueMessage{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &Message{Id: "1", Type: tt.msgType, Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags}
+			request := msg.ToKubemq()
+			require.IsType(t, tt.wantType, request)
+			got, err := NewMessage(request)
+			require.NoError(t, err)
+			if tt.msgType == Type_TYPE_UNSPECIFIED {
+				msg.Type = Type_TYPE_QUERY
+			}
+			require.Equal(t, msg, got)
+		})
+	}
+	_, err := NewMessage("bad")
+	require.Error(t, err)
+}
+
+func TestNewResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		err    error
+		want   *Response
+	}{
+		{
+			name:   "query response",
+			result: &kubemq.QueryResponse{Executed: true, Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+			want:   &Response{Id: "1", Executed: true, Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:   "failed query response",
+			result: &kubemq.QueryResponse{Body: []byte("body"), Error: "query error"},
+			err:    fmt.Errorf("query error"),
+			want:   &Response{Id: "1", Error: "query error", Body: []byte("body")},
+		},
+		{
+			name:   "command response",
+			result: &kubemq.CommandResponse{Executed: true, Tags: map[string]string{"key": "value"}},
+			want:   &Response{Id: "1", Executed: true, Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name: "no response",
+			want: &Response{Id: "1", Executed: true},
+		},
+		{
+			name: "error",
+			err:  fmt.Errorf("target error"),
+			want: &Response{Id: "1", Error: "target error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewResponse("1", tt.result, tt.err))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package bridgespb holds the Bridge gRPC service, which source.grpc serves and target.grpc calls
+package bridgespb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative bridges.proto
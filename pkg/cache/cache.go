@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type item struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache is a least recently used cache of a max number of items, which expire after a ttl
+type Cache struct {
+	sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: map[string]*list.Element{},
+		now:   time.Now,
+	}
+}
+
+// Get returns the value of a key which has not expired
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.Lock()
+	defer c.Unlock()
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	it := element.Value.(*item)
+	if !c.now().Before(it.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return it.value, true
+}
+
+// Set sets the value of a key, evicting the least recently used item when the cache is full
+func (c *Cache) Set(key string, value interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.items[key]; ok {
+		it := element.Value.(*item)
+		it.value, it.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(&item{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of items, including the expired items which were not evicted yet
+func (c *Cache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*item).key)
+}
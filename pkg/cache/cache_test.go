@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	c := New(2, time.Minute)
+	c.now = func() time.Time { return now }
+	c.Set("a", 1)
+	c.Set("b", 2)
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	// b is the least recently used item
+	c.Set("c", 3)
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	require.Equal(t, 2, c.Len())
+
+	c.Set("a", 4)
+	value, ok = c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 4, value)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("a")
+	require.False(t, ok)
+	_, ok = c.Get("c")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
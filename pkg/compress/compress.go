@@ -0,0 +1,183 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+const (
+	CodecGzip   = "gzip"
+	CodecZstd   = "zstd"
+	CodecSnappy = "snappy"
+	CodecLz4    = "lz4"
+)
+
+// Codecs lists the supported compression codecs
+var Codecs = []string{CodecGzip, CodecZstd, CodecSnappy, CodecLz4}
+
+// Codec compresses and decompresses payloads
+type Codec interface {
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	// Decompress decompresses a payload, failing when the decompressed size exceeds the codec max size
+	Decompress(data []byte) ([]byte, error)
+}
+
+// New returns the codec with the name, which decompresses payloads of up to maxSize bytes
+func New(name string, maxSize int) (Codec, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("invalid max size %d", maxSize)
+	}
+	switch strings.ToLower(name) {
+	case CodecGzip:
+		return gzipCodec{maxSize: maxSize}, nil
+	case CodecZstd:
+		return newZstdCodec(maxSize)
+	case CodecSnappy:
+		return snappyCodec{maxSize: maxSize}, nil
+	case CodecLz4:
+		return lz4Codec{maxSize: maxSize}, nil
+	default:
+		return nil, fmt.Errorf("invalid compression codec %s, supported codecs are %s", name, strings.Join(Codecs, ", "))
+	}
+}
+
+// readAll reads a decompressing reader up to maxSize bytes
+func readAll(r io.Reader, maxSize int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("decompressed size exceeds %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+type gzipCodec struct {
+	maxSize int
+}
+
+func (gzipCodec) Name() string {
+	return CodecGzip
+}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c gzipCodec) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readAll(r, c.maxSize)
+}
+
+// zstdCodec shares its encoder and decoder, which are safe for concurrent EncodeAll and DecodeAll calls
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// zstdCodecs caches the zstd codecs by max size, as their encoders and decoders keep goroutines and buffers for their lifetime
+var zstdCodecs sync.Map
+
+func newZstdCodec(maxSize int) (*zstdCodec, error) {
+	if codec, ok := zstdCodecs.Load(maxSize); ok {
+		return codec.(*zstdCodec), nil
+	}
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+	if err != nil {
+		return nil, err
+	}
+	codec, loaded := zstdCodecs.LoadOrStore(maxSize, &zstdCodec{
+		encoder: encoder,
+		decoder: decoder,
+	})
+	if loaded {
+		_ = encoder.Close()
+		decoder.Close()
+	}
+	return codec.(*zstdCodec), nil
+}
+
+func (c *zstdCodec) Name() string {
+	return CodecZstd
+}
+
+func (c *zstdCodec) Compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *zstdCodec) Decompress(data []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(data, nil)
+}
+
+type snappyCodec struct {
+	maxSize int
+}
+
+func (snappyCodec) Name() string {
+	return CodecSnappy
+}
+
+func (snappyCodec) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+func (c snappyCodec) Decompress(data []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if size > c.maxSize {
+		return nil, fmt.Errorf("decompressed size exceeds %d bytes", c.maxSize)
+	}
+	return snappy.Decode(nil, data)
+}
+
+type lz4Codec struct {
+	maxSize int
+}
+
+func (lz4Codec) Name() string {
+	return CodecLz4
+}
+
+func (lz4Codec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := lz4.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c lz4Codec) Decompress(data []byte) ([]byte, error) {
+	return readAll(lz4.NewReader(bytes.NewReader(data)), c.maxSize)
+}
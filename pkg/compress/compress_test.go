@@ -0,0 +1,42 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("kubemq bridges "), 1000)
+	for _, name := range Codecs {
+		t.Run(name, func(t *testing.T) {
+			codec, err := New(name, len(data))
+			require.NoError(t, err)
+			require.Equal(t, name, codec.Name())
+			compressed, err := codec.Compress(data)
+			require.NoError(t, err)
+			require.Less(t, len(compressed), len(data))
+			decompressed, err := codec.Decompress(compressed)
+			require.NoError(t, err)
+			require.Equal(t, data, decompressed)
+
+			_, err = codec.Decompress([]byte("not-compressed-data"))
+			require.Error(t, err)
+
+			small, err := New(name, len(data)-1)
+			require.NoError(t, err)
+			_, err = small.Decompress(compressed)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("GZIP", 1)
+	require.NoError(t, err)
+	_, err = New("brotli", 1)
+	require.Error(t, err)
+	_, err = New(CodecGzip, 0)
+	require.Error(t, err)
+}
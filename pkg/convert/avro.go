@@ -0,0 +1,47 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroCodec converts raw avro binary payloads, without container or schema registry framing, to the avro json encoding
+type avroCodec struct {
+	codec *goavro.Codec
+}
+
+func newAvroCodec(schema Schema) (*avroCodec, error) {
+	if schema.File == "" {
+		return nil, fmt.Errorf("avro format requires a schema file")
+	}
+	data, err := os.ReadFile(schema.File)
+	if err != nil {
+		return nil, fmt.Errorf("error reading avro schema file, %w", err)
+	}
+	codec, err := goavro.NewCodec(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %s, %w", schema.File, err)
+	}
+	return &avroCodec{codec: codec}, nil
+}
+
+func (a *avroCodec) ToJSON(data []byte) ([]byte, error) {
+	native, remaining, err := a.codec.NativeFromBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro payload, %w", err)
+	}
+	if len(remaining) > 0 {
+		return nil, fmt.Errorf("invalid avro payload, %d unexpected bytes after the record", len(remaining))
+	}
+	return a.codec.TextualFromNative(nil, native)
+}
+
+func (a *avroCodec) FromJSON(data []byte) ([]byte, error) {
+	native, _, err := a.codec.NativeFromTextual(data)
+	if err != nil {
+		return nil, fmt.Errorf("json payload does not match the avro schema, %w", err)
+	}
+	return a.codec.BinaryFromNative(nil, native)
+}
@@ -0,0 +1,35 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+type cborCodec struct {
+	enc cbor.EncMode
+}
+
+func newCBORCodec() (*cborCodec, error) {
+	enc, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return &cborCodec{enc: enc}, nil
+}
+
+func (c *cborCodec) ToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid cbor payload, %w", err)
+	}
+	return encodeJSON(value)
+}
+
+func (c *cborCodec) FromJSON(data []byte) ([]byte, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.enc.Marshal(value)
+}
@@ -0,0 +1,80 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatProtobuf = "protobuf"
+	FormatAvro     = "avro"
+	FormatMsgpack  = "msgpack"
+	FormatCBOR     = "cbor"
+)
+
+// Formats lists the supported payload formats
+var Formats = []string{FormatJSON, FormatProtobuf, FormatAvro, FormatMsgpack, FormatCBOR}
+
+// Codec converts payloads of a format to and from json, which all the conversions pass through
+type Codec interface {
+	// ToJSON converts a payload of the codec format to json
+	ToJSON(data []byte) ([]byte, error)
+	// FromJSON converts json to a payload of the codec format
+	FromJSON(data []byte) ([]byte, error)
+}
+
+// Schema references the schema of a payload format
+type Schema struct {
+	// File is a .proto file or a protobuf descriptor set for protobuf, an .avsc file for avro
+	File string
+	// MessageType is the full name of the protobuf message type
+	MessageType string
+	// ImportPaths are the directories searched for the imports of a .proto file
+	ImportPaths []string
+}
+
+// NewCodec returns the codec of a format, loading its schema when the format requires one
+func NewCodec(format string, schema Schema) (Codec, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return jsonCodec{}, nil
+	case FormatMsgpack:
+		return msgpackCodec{}, nil
+	case FormatCBOR:
+		return newCBORCodec()
+	case FormatProtobuf:
+		return newProtobufCodec(schema)
+	case FormatAvro:
+		return newAvroCodec(schema)
+	default:
+		return nil, fmt.Errorf("invalid format %s, supported formats are %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Converter converts payloads from one format to another
+type Converter struct {
+	from Codec
+	to   Codec
+}
+
+func NewConverter(from, to Codec) *Converter {
+	return &Converter{
+		from: from,
+		to:   to,
+	}
+}
+
+// Convert converts a payload of the source format to the target format
+func (c *Converter) Convert(data []byte) ([]byte, error) {
+	js, err := c.from.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.to.FromJSON(js)
+}
+
+// Reverse returns a converter from the target format back to the source format
+func (c *Converter) Reverse() *Converter {
+	return NewConverter(c.to, c.from)
+}
@@ -0,0 +1,206 @@
+package convert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const testProto = `syntax = "proto3";
+package test;
+
+message Customer {
+  string name = 1;
+  int32 tier = 2;
+  repeated string tags = 3;
+}
+`
+
+const testAvro = `{
+  "type": "record",
+  "name": "Customer",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "tier", "type": "int"},
+    {"name": "tags", "type": {"type": "array", "items": "string"}}
+  ]
+}`
+
+const testJSON = `{"name":"acme","tier":2,"tags":["a","b"]}`
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func testSchemas(t *testing.T) (protoFile, descriptorSet, avroFile string) {
+	dir := t.TempDir()
+	protoFile = writeFile(t, dir, "customer.proto", []byte(testProto))
+	avroFile = writeFile(t, dir, "customer.avsc", []byte(testAvro))
+	files, err := compileProto(protoFile, nil)
+	require.NoError(t, err)
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+	}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+	descriptorSet = writeFile(t, dir, "customer.pb", data)
+	return
+}
+
+func TestConverter_RoundTrip(t *testing.T) {
+	protoFile, descriptorSet, avroFile := testSchemas(t)
+	tests := []struct {
+		name   string
+		format string
+		schema Schema
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+		},
+		{
+			name:   "msgpack",
+			format: FormatMsgpack,
+		},
+		{
+			name:   "cbor",
+			format: FormatCBOR,
+		},
+		{
+			name:   "protobuf - proto file",
+			format: FormatProtobuf,
+			schema: Schema{File: protoFile},
+		},
+		{
+			name:   "protobuf - descriptor set",
+			format: FormatProtobuf,
+			schema: Schema{File: descriptorSet, MessageType: "test.Customer"},
+		},
+		{
+			name:   "avro",
+			format: FormatAvro,
+			schema: Schema{File: avroFile},
+		},
+	}
+	jsonCodec, err := NewCodec(FormatJSON, Schema{})
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewCodec(tt.format, tt.schema)
+			require.NoError(t, err)
+			encoded, err := NewConverter(jsonCodec, codec).Convert([]byte(testJSON))
+			require.NoError(t, err)
+			decoded, err := NewConverter(jsonCodec, codec).Reverse().Convert(encoded)
+			require.NoError(t, err)
+			require.JSONEq(t, testJSON, string(decoded))
+		})
+	}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	protoFile, _, avroFile := testSchemas(t)
+	protobuf, err := NewCodec(FormatProtobuf, Schema{File: protoFile, MessageType: "test.Customer"})
+	require.NoError(t, err)
+	avro, err := NewCodec(FormatAvro, Schema{File: avroFile})
+	require.NoError(t, err)
+	msgpack, err := NewCodec(FormatMsgpack, Schema{})
+	require.NoError(t, err)
+
+	data, err := protobuf.FromJSON([]byte(testJSON))
+	require.NoError(t, err)
+	converted, err := NewConverter(protobuf, avro).Convert(data)
+	require.NoError(t, err)
+	converted, err = NewConverter(avro, msgpack).Convert(converted)
+	require.NoError(t, err)
+	js, err := msgpack.ToJSON(converted)
+	require.NoError(t, err)
+	require.JSONEq(t, testJSON, string(js))
+
+	_, err = NewConverter(avro, msgpack).Convert([]byte("bad-data"))
+	require.Error(t, err)
+	_, err = NewConverter(msgpack, protobuf).Convert([]byte{0xc1})
+	require.Error(t, err)
+	_, err = protobuf.FromJSON([]byte(`{"unknown":1}`))
+	require.Error(t, err)
+	_, err = avro.FromJSON([]byte(`{"name":"acme"}`))
+	require.Error(t, err)
+}
+
+func TestNewCodec(t *testing.T) {
+	protoFile, descriptorSet, avroFile := testSchemas(t)
+	tests := []struct {
+		name    string
+		format  string
+		schema  Schema
+		wantErr bool
+	}{
+		{
+			name:    "valid - upper case format",
+			format:  "JSON",
+			wantErr: false,
+		},
+		{
+			name:    "invalid - unknown format",
+			format:  "xml",
+			wantErr: true,
+		},
+		{
+			name:    "invalid - protobuf no schema",
+			format:  FormatProtobuf,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - protobuf unknown message type",
+			format:  FormatProtobuf,
+			schema:  Schema{File: protoFile, MessageType: "test.Order"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - protobuf missing file",
+			format:  FormatProtobuf,
+			schema:  Schema{File: filepath.Join(t.TempDir(), "missing.proto")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - protobuf bad descriptor set",
+			format:  FormatProtobuf,
+			schema:  Schema{File: avroFile},
+			wantErr: true,
+		},
+		{
+			name:    "valid - protobuf descriptor set",
+			format:  FormatProtobuf,
+			schema:  Schema{File: descriptorSet},
+			wantErr: false,
+		},
+		{
+			name:    "invalid - avro no schema",
+			format:  FormatAvro,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - avro bad schema",
+			format:  FormatAvro,
+			schema:  Schema{File: protoFile},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCodec(tt.format, tt.schema)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
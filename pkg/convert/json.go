@@ -0,0 +1,109 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) ToJSON(data []byte) ([]byte, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid json payload")
+	}
+	return data, nil
+}
+
+func (jsonCodec) FromJSON(data []byte) ([]byte, error) {
+	return jsonCodec{}.ToJSON(data)
+}
+
+// decodeJSON decodes json to go values, keeping integral numbers as int64 so binary formats encode them as integers
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid json payload, %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid json payload, unexpected data after the json value")
+	}
+	return fromNumbers(value), nil
+}
+
+func fromNumbers(value interface{}) interface{} {
+	switch val := value.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = fromNumbers(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = fromNumbers(item)
+		}
+		return val
+	default:
+		return value
+	}
+}
+
+// encodeJSON encodes go values decoded from binary formats, which may have non string map keys, to json
+func encodeJSON(value interface{}) ([]byte, error) {
+	normalized, err := normalize(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalized)
+}
+
+func normalize(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			n, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprintf("%v", key)] = n
+		}
+		return m, nil
+	case map[string]interface{}:
+		for key, item := range val {
+			n, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = n
+		}
+		return val, nil
+	case []interface{}:
+		for i, item := range val {
+			n, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = n
+		}
+		return val, nil
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil, fmt.Errorf("value %v cannot be represented in json", val)
+		}
+		return val, nil
+	case float32:
+		return normalize(float64(val))
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,143 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type protobufCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+func newProtobufCodec(schema Schema) (*protobufCodec, error) {
+	if schema.File == "" {
+		return nil, fmt.Errorf("protobuf format requires a schema file")
+	}
+	files, err := LoadDescriptors(schema.File, schema.ImportPaths)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, err := findMessage(files, schema.MessageType)
+	if err != nil {
+		return nil, err
+	}
+	return &protobufCodec{descriptor: descriptor}, nil
+}
+
+// LoadDescriptors returns the file descriptors of a .proto file compiled with its imports, or of a protobuf descriptor set file
+func LoadDescriptors(file string, importPaths []string) ([]protoreflect.FileDescriptor, error) {
+	if strings.EqualFold(filepath.Ext(file), ".proto") {
+		return compileProto(file, importPaths)
+	}
+	return loadDescriptorSet(file)
+}
+
+func compileProto(file string, importPaths []string) ([]protoreflect.FileDescriptor, error) {
+	name := ""
+	for _, importPath := range importPaths {
+		rel, err := filepath.Rel(importPath, file)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			name = filepath.ToSlash(rel)
+			break
+		}
+	}
+	if name == "" {
+		name = filepath.Base(file)
+		importPaths = append([]string{filepath.Dir(file)}, importPaths...)
+	}
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+	}
+	compiled, err := compiler.Compile(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling proto file %s, %w", file, err)
+	}
+	var files []protoreflect.FileDescriptor
+	for _, f := range compiled {
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func loadDescriptorSet(file string) ([]protoreflect.FileDescriptor, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading protobuf descriptor set, %w", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set %s, %w", file, err)
+	}
+	registry, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set %s, %w", file, err)
+	}
+	var files []protoreflect.FileDescriptor
+	registry.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		files = append(files, fd)
+		return true
+	})
+	return files, nil
+}
+
+// findMessage returns the named message type, or the only top level message type defined when no name is set
+func findMessage(files []protoreflect.FileDescriptor, name string) (protoreflect.MessageDescriptor, error) {
+	if name != "" {
+		for _, file := range files {
+			if md := findNested(file.Messages(), protoreflect.FullName(name)); md != nil {
+				return md, nil
+			}
+		}
+		return nil, fmt.Errorf("protobuf message type %s not found", name)
+	}
+	var found []protoreflect.MessageDescriptor
+	for _, file := range files {
+		for i := 0; i < file.Messages().Len(); i++ {
+			found = append(found, file.Messages().Get(i))
+		}
+	}
+	if len(found) != 1 {
+		return nil, fmt.Errorf("protobuf message type must be set when the schema defines %d message types", len(found))
+	}
+	return found[0], nil
+}
+
+func findNested(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.FullName() == name {
+			return md
+		}
+		if nested := findNested(md.Messages(), name); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+func (p *protobufCodec) ToJSON(data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(p.descriptor)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid protobuf payload for %s, %w", p.descriptor.FullName(), err)
+	}
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+}
+
+func (p *protobufCodec) FromJSON(data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(p.descriptor)
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("json payload does not match protobuf message %s, %w", p.descriptor.FullName(), err)
+	}
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,164 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	StateConnecting = "connecting"
+	StateSubscribed = "subscribed"
+	StateFailed     = "failed"
+	StateStopped    = "stopped"
+
+	rateWindow = 60
+)
+
+// Status is a report of a source connection health
+type Status struct {
+	Name            string     `json:"name"`
+	State           string     `json:"state"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorTime   *time.Time `json:"last_error_time,omitempty"`
+	Reconnects      int        `json:"reconnects"`
+	LastMessageTime *time.Time `json:"last_message_time,omitempty"`
+	Messages        int64      `json:"messages"`
+	Rate            float64    `json:"rate"`
+}
+
+func (s *Status) Healthy() bool {
+	return s.State != StateFailed
+}
+
+// Tracker tracks the state of a source connection, it is safe for concurrent use
+type Tracker struct {
+	sync.Mutex
+	name            string
+	state           string
+	subscribed      bool
+	lastError       string
+	lastErrorTime   time.Time
+	reconnects      int
+	lastMessageTime time.Time
+	messages        int64
+	buckets         [rateWindow]int64
+	bucketTimes     [rateWindow]int64
+	now             func() time.Time
+}
+
+func NewTracker(name string) *Tracker {
+	return &Tracker{
+		name:  name,
+		state: StateConnecting,
+		now:   time.Now,
+	}
+}
+
+// Connecting sets the connection state to connecting
+func (t *Tracker) Connecting() {
+	t.Lock()
+	defer t.Unlock()
+	if t.state == StateStopped {
+		return
+	}
+	t.state = StateConnecting
+}
+
+// Subscribed sets the connection state to subscribed, any subscription after the first one is counted as a reconnect
+func (t *Tracker) Subscribed() {
+	t.Lock()
+	defer t.Unlock()
+	if t.state == StateSubscribed || t.state == StateStopped {
+		return
+	}
+	if t.subscribed {
+		t.reconnects++
+	}
+	t.subscribed = true
+	t.state = StateSubscribed
+}
+
+// Failed sets the connection state to failed with the error which caused it, errors of a stopped connection are ignored
+func (t *Tracker) Failed(err error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.state == StateStopped {
+		return
+	}
+	t.state = StateFailed
+	t.setError(err)
+}
+
+// Stopped sets the connection state to stopped, a stopped connection state is final
+func (t *Tracker) Stopped() {
+	t.Lock()
+	defer t.Unlock()
+	t.state = StateStopped
+}
+
+func (t *Tracker) setError(err error) {
+	if err == nil {
+		return
+	}
+	t.lastError = err.Error()
+	t.lastErrorTime = t.now()
+}
+
+// Message records a message received on the connection
+func (t *Tracker) Message() {
+	t.Lock()
+	defer t.Unlock()
+	now := t.now()
+	t.lastMessageTime = now
+	t.messages++
+	sec := now.Unix()
+	i := sec % rateWindow
+	if t.bucketTimes[i] != sec {
+		t.bucketTimes[i] = sec
+		t.buckets[i] = 0
+	}
+	t.buckets[i]++
+}
+
+// rate returns the messages per second average of the last rateWindow seconds
+func (t *Tracker) rate() float64 {
+	from := t.now().Unix() - rateWindow
+	var count int64
+	for i := range t.buckets {
+		if t.bucketTimes[i] > from {
+			count += t.buckets[i]
+		}
+	}
+	return float64(count) / rateWindow
+}
+
+func (t *Tracker) Status() *Status {
+	t.Lock()
+	defer t.Unlock()
+	s := &Status{
+		Name:       t.name,
+		State:      t.state,
+		LastError:  t.lastError,
+		Reconnects: t.reconnects,
+		Messages:   t.messages,
+		Rate:       t.rate(),
+	}
+	if !t.lastErrorTime.IsZero() {
+		lastErrorTime := t.lastErrorTime
+		s.LastErrorTime = &lastErrorTime
+	}
+	if !t.lastMessageTime.IsZero() {
+		lastMessageTime := t.lastMessageTime
+		s.LastMessageTime = &lastMessageTime
+	}
+	return s
+}
+
+// Statuses returns the statuses of a list of trackers
+func Statuses(trackers []*Tracker) []*Status {
+	var list []*Status
+	for _, tracker := range trackers {
+		list = append(list, tracker.Status())
+	}
+	return list
+}
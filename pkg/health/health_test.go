@@ -0,0 +1,106 @@
+package health
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_State(t *testing.T) {
+	tests := []struct {
+		name           string
+		actions        func(tr *Tracker)
+		wantState      string
+		wantReconnects int
+		wantError      string
+		wantHealthy    bool
+	}{
+		{
+			name:        "new connection",
+			actions:     func(tr *Tracker) {},
+			wantState:   StateConnecting,
+			wantHealthy: true,
+		},
+		{
+			name: "subscribed",
+			actions: func(tr *Tracker) {
+				tr.Subscribed()
+			},
+			wantState:   StateSubscribed,
+			wantHealthy: true,
+		},
+		{
+			name: "failed",
+			actions: func(tr *Tracker) {
+				tr.Subscribed()
+				tr.Failed(fmt.Errorf("connection lost"))
+			},
+			wantState:   StateFailed,
+			wantError:   "connection lost",
+			wantHealthy: false,
+		},
+		{
+			name: "resubscribed after failure",
+			actions: func(tr *Tracker) {
+				tr.Subscribed()
+				tr.Failed(fmt.Errorf("connection lost"))
+				tr.Connecting()
+				tr.Subscribed()
+				tr.Subscribed()
+			},
+			wantState:      StateSubscribed,
+			wantReconnects: 1,
+			wantError:      "connection lost",
+			wantHealthy:    true,
+		},
+		{
+			name: "errors after stop are ignored",
+			actions: func(tr *Tracker) {
+				tr.Subscribed()
+				tr.Stopped()
+				tr.Failed(fmt.Errorf("context canceled"))
+				tr.Subscribed()
+			},
+			wantState:   StateStopped,
+			wantHealthy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker("channel/1")
+			tt.actions(tr)
+			status := tr.Status()
+			require.Equal(t, "channel/1", status.Name)
+			require.Equal(t, tt.wantState, status.State)
+			require.Equal(t, tt.wantReconnects, status.Reconnects)
+			require.Equal(t, tt.wantError, status.LastError)
+			require.Equal(t, tt.wantError != "", status.LastErrorTime != nil)
+			require.Equal(t, tt.wantHealthy, status.Healthy())
+		})
+	}
+}
+
+func TestTracker_Rate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tr := NewTracker("channel/1")
+	tr.now = func() time.Time {
+		return now
+	}
+	require.Nil(t, tr.Status().LastMessageTime)
+	for i := 0; i < 60; i++ {
+		for j := 0; j < 2; j++ {
+			tr.Message()
+		}
+		now = now.Add(time.Second)
+	}
+	status := tr.Status()
+	require.EqualValues(t, 120, status.Messages)
+	require.Equal(t, 2.0-2.0/60, status.Rate)
+	require.NotNil(t, status.LastMessageTime)
+	now = now.Add(2 * time.Minute)
+	status = tr.Status()
+	require.EqualValues(t, 120, status.Messages)
+	require.Equal(t, 0.0, status.Rate)
+}
@@ -0,0 +1,147 @@
+package jsonl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord_RoundTrip(t *testing.T) {
+	timestamp := time.Unix(1600000000, 0).UTC()
+	tests := []struct {
+		name string
+		msg  interface{}
+	}{
+		{
+			name: "event",
+			msg:  &kubemq.Event{Id: "1", Channel: "events", ClientId: "client", Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name: "event store",
+			msg:  &kubemq.EventStoreReceive{Id: "2", Sequence: 10, Timestamp: timestamp, Channel: "events-store", Metadata: "metadata", Body: []byte{0, 1, 2}, Tags: map[string]string{}},
+		},
+		{
+			name: "command",
+			msg:  &kubemq.CommandReceive{Id: "3", Channel: "commands", Body: []byte("body")},
+		},
+		{
+			name: "query",
+			msg:  &kubemq.QueryReceive{Id: "4", Channel: "queries", Metadata: "metadata", Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name: "queue",
+			msg:  kubemq.NewQueueMessage().SetId("5").SetChannel("queue").SetMetadata("metadata").SetBody([]byte("body")).SetTags(map[string]string{"key": "value"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, gz := range []bool{false, true} {
+				dir := t.TempDir()
+				w, err := NewWriter(WriterOptions{Dir: dir, Prefix: "messages", Gzip: gz})
+				require.NoError(t, err)
+				record, err := NewRecord(tt.msg)
+				require.NoError(t, err)
+				require.NoError(t, w.Write(record))
+				require.NoError(t, w.Close())
+				files, err := Files(dir)
+				require.NoError(t, err)
+				require.Len(t, files, 1)
+				var messages []interface{}
+				lines, err := ReadFile(files[0], 0, func(record *Record) error {
+					msg, err := record.Message()
+					messages = append(messages, msg)
+					return err
+				})
+				require.NoError(t, err)
+				require.Equal(t, 1, lines)
+				require.Equal(t, []interface{}{tt.msg}, messages)
+			}
+		})
+	}
+}
+
+func TestNewRecord_UnknownType(t *testing.T) {
+	_, err := NewRecord("message")
+	require.Error(t, err)
+	_, err = (&Record{Type: "bad"}).Message()
+	require.Error(t, err)
+}
+
+func TestWriter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(WriterOptions{Dir: dir, Prefix: "messages", MaxSize: 1})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, w.Write(&Record{Type: TypeEvent, Channel: "events"}))
+	}
+	files, err := Files(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.NoError(t, w.Close())
+
+	dir = t.TempDir()
+	w, err = NewWriter(WriterOptions{Dir: dir, Prefix: "messages", MaxAge: 100 * time.Millisecond, Gzip: true})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(&Record{Type: TypeEvent, Channel: "events"}))
+	files, err = Files(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+	require.Eventually(t, func() bool {
+		files, _ = Files(dir)
+		return len(files) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	require.True(t, strings.HasSuffix(files[0], GzipExt))
+	require.NoError(t, w.Close())
+	require.Error(t, w.Write(&Record{Type: TypeEvent}))
+}
+
+func TestWriter_SharedDir(t *testing.T) {
+	dir := t.TempDir()
+	// the active file of another writer is not completed
+	active := filepath.Join(dir, "messages-20200101T000000.000000000-other"+Ext+activeExt)
+	require.NoError(t, os.WriteFile(active, []byte(`{"type":"event","channel":"a"}`+"\n"), 0644))
+	first, err := NewWriter(WriterOptions{Dir: dir, Prefix: "messages"})
+	require.NoError(t, err)
+	second, err := NewWriter(WriterOptions{Dir: dir, Prefix: "messages"})
+	require.NoError(t, err)
+	require.NoError(t, first.Write(&Record{Type: TypeEvent, Channel: "b"}))
+	require.NoError(t, second.Write(&Record{Type: TypeEvent, Channel: "c"}))
+	require.NoError(t, first.Close())
+	require.NoError(t, second.Close())
+	files, err := Files(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.FileExists(t, active)
+}
+
+func TestReadFile_Resume(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "messages"+Ext)
+	data := `{"type":"event","channel":"a"}` + "\n" +
+		`{"type":"event","channel":"b"}` + "\n" +
+		`{"type":"event","channel":"c"}` + "\n" +
+		`{"type":"event","chan`
+	require.NoError(t, os.WriteFile(name, []byte(data), 0644))
+	var channels []string
+	lines, err := ReadFile(name, 0, func(record *Record) error {
+		if record.Channel == "b" {
+			return os.ErrInvalid
+		}
+		channels = append(channels, record.Channel)
+		return nil
+	})
+	require.ErrorIs(t, err, os.ErrInvalid)
+	require.Equal(t, 1, lines)
+	lines, err = ReadFile(name, 1, func(record *Record) error {
+		channels = append(channels, record.Channel)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, lines)
+	require.Equal(t, []string{"a", "b", "c"}, channels)
+}
@@ -0,0 +1,79 @@
+package jsonl
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IsFile returns true for a complete json lines file name, plain or gzip compressed
+func IsFile(name string) bool {
+	return strings.HasSuffix(name, Ext) || strings.HasSuffix(name, GzipExt)
+}
+
+// Files returns the complete json lines files in a directory, sorted by name, which is the files creation order for Writer files
+func Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsFile(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadFile calls fn for each record of a json lines file, after skipping the first skip lines. It returns the number of lines
+// read, including the skipped ones and excluding a line which fn failed on, so a failed file can be resumed from where it stopped
+func ReadFile(name string, skip int, fn func(record *Record) error) (int, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, fmt.Errorf("error reading gzip file %s, %w", name, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	reader := bufio.NewReader(r)
+	lines := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && err == nil {
+			if lines >= skip {
+				record := &Record{}
+				if err := json.Unmarshal(line, record); err != nil {
+					return lines, fmt.Errorf("error parsing line %d of file %s, %w", lines+1, name, err)
+				}
+				if err := fn(record); err != nil {
+					return lines, err
+				}
+			}
+			lines++
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				// a last line without a new line, or a truncated gzip stream, is an incomplete write, and is not read
+				return lines, nil
+			}
+			return lines, fmt.Errorf("error reading file %s, %w", name, err)
+		}
+	}
+}
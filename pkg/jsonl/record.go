@@ -0,0 +1,78 @@
+package jsonl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	TypeEvent      = "event"
+	TypeEventStore = "event_store"
+	TypeCommand    = "command"
+	TypeQuery      = "query"
+	TypeQueue      = "queue"
+)
+
+// Record is a message stored as a json line, it keeps the message type, id, metadata, body and tags so it can be replayed as is
+type Record struct {
+	Type      string            `json:"type"`
+	Id        string            `json:"id"`
+	Channel   string            `json:"channel"`
+	ClientId  string            `json:"client_id,omitempty"`
+	Metadata  string            `json:"metadata"`
+	Body      []byte            `json:"body"`
+	Tags      map[string]string `json:"tags"`
+	Sequence  uint64            `json:"sequence,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// NewRecord returns the record of a kubemq message, the record timestamp is the message timestamp when it has one, otherwise the current time
+func NewRecord(msg interface{}) (*Record, error) {
+	switch val := msg.(type) {
+	case *kubemq.Event:
+		return &Record{Type: TypeEvent, Id: val.Id, Channel: val.Channel, ClientId: val.ClientId, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Timestamp: time.Now().UTC()}, nil
+	case *kubemq.EventStoreReceive:
+		timestamp := val.Timestamp.UTC()
+		if val.Timestamp.IsZero() {
+			timestamp = time.Now().UTC()
+		}
+		return &Record{Type: TypeEventStore, Id: val.Id, Channel: val.Channel, ClientId: val.ClientId, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Sequence: val.Sequence, Timestamp: timestamp}, nil
+	case *kubemq.CommandReceive:
+		return &Record{Type: TypeCommand, Id: val.Id, Channel: val.Channel, ClientId: val.ClientId, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Timestamp: time.Now().UTC()}, nil
+	case *kubemq.QueryReceive:
+		return &Record{Type: TypeQuery, Id: val.Id, Channel: val.Channel, ClientId: val.ClientId, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Timestamp: time.Now().UTC()}, nil
+	case *kubemq.QueueMessage:
+		return &Record{Type: TypeQueue, Id: val.MessageID, Channel: val.Channel, ClientId: val.ClientID, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Timestamp: time.Now().UTC()}, nil
+	case *queues_stream.QueueMessage:
+		return &Record{Type: TypeQueue, Id: val.MessageID, Channel: val.Channel, ClientId: val.ClientID, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags, Timestamp: time.Now().UTC()}, nil
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+}
+
+// Message returns the kubemq message of the record, in the form a source of the same type sends it to the targets
+func (r *Record) Message() (interface{}, error) {
+	switch r.Type {
+	case TypeEvent:
+		return &kubemq.Event{Id: r.Id, Channel: r.Channel, ClientId: r.ClientId, Metadata: r.Metadata, Body: r.Body, Tags: r.Tags}, nil
+	case TypeEventStore:
+		return &kubemq.EventStoreReceive{Id: r.Id, Sequence: r.Sequence, Timestamp: r.Timestamp, Channel: r.Channel, ClientId: r.ClientId, Metadata: r.Metadata, Body: r.Body, Tags: r.Tags}, nil
+	case TypeCommand:
+		return &kubemq.CommandReceive{Id: r.Id, Channel: r.Channel, ClientId: r.ClientId, Metadata: r.Metadata, Body: r.Body, Tags: r.Tags}, nil
+	case TypeQuery:
+		return &kubemq.QueryReceive{Id: r.Id, Channel: r.Channel, ClientId: r.ClientId, Metadata: r.Metadata, Body: r.Body, Tags: r.Tags}, nil
+	case TypeQueue:
+		return kubemq.NewQueueMessage().
+			SetId(r.Id).
+			SetChannel(r.Channel).
+			SetClientId(r.ClientId).
+			SetMetadata(r.Metadata).
+			SetBody(r.Body).
+			SetTags(r.Tags), nil
+	default:
+		return nil, fmt.Errorf("unknown record type %s", r.Type)
+	}
+}
@@ -0,0 +1,184 @@
+package jsonl
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+)
+
+const (
+	Ext       = ".jsonl"
+	GzipExt   = ".jsonl.gz"
+	activeExt = ".tmp"
+
+	fileTimeFormat = "20060102T150405.000000000"
+)
+
+// WriterOptions sets the files and rotation of a Writer
+type WriterOptions struct {
+	// Dir is the directory of the files
+	Dir string
+	// Prefix is the file names prefix
+	Prefix string
+	// MaxSize rotates a file when it reaches MaxSize bytes, 0 - no size rotation
+	MaxSize int64
+	// MaxAge rotates a file when it is open for MaxAge, 0 - no time rotation
+	MaxAge time.Duration
+	// Gzip compresses the files
+	Gzip bool
+}
+
+func (o WriterOptions) ext() string {
+	if o.Gzip {
+		return GzipExt
+	}
+	return Ext
+}
+
+type countWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// Writer appends records to rotating json lines files, a file is written with a .tmp suffix and renamed when rotated or closed,
+// so only complete files are visible to readers. The file names hold a writer id, so writers can share a directory and a prefix.
+type Writer struct {
+	sync.Mutex
+	id       string
+	opts     WriterOptions
+	file     *os.File
+	counter  *countWriter
+	gz       *gzip.Writer
+	openedAt time.Time
+	done     chan struct{}
+	closed   bool
+}
+
+func NewWriter(opts WriterOptions) (*Writer, error) {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory %s, %w", opts.Dir, err)
+	}
+	w := &Writer{
+		id:   uuid.New().String()[:8],
+		opts: opts,
+		done: make(chan struct{}),
+	}
+	if opts.MaxAge > 0 {
+		go w.runRotation()
+	}
+	return w, nil
+}
+
+func (w *Writer) runRotation() {
+	ticker := time.NewTicker(w.opts.MaxAge / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Lock()
+			if w.file != nil && time.Since(w.openedAt) >= w.opts.MaxAge {
+				_ = w.rotate()
+			}
+			w.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *Writer) open() error {
+	w.openedAt = time.Now()
+	name := filepath.Join(w.opts.Dir, fmt.Sprintf("%s-%s-%s%s%s", w.opts.Prefix, w.openedAt.UTC().Format(fileTimeFormat), w.id, w.opts.ext(), activeExt))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating file %s, %w", name, err)
+	}
+	w.file = file
+	w.counter = &countWriter{w: file}
+	if w.opts.Gzip {
+		w.gz = gzip.NewWriter(w.counter)
+	}
+	return nil
+}
+
+// rotate closes the current file and renames it to its final name
+func (w *Writer) rotate() error {
+	if w.file == nil {
+		return nil
+	}
+	var errs []error
+	if w.gz != nil {
+		errs = append(errs, w.gz.Close())
+	}
+	errs = append(errs, w.file.Close())
+	name := w.file.Name()
+	errs = append(errs, os.Rename(name, strings.TrimSuffix(name, activeExt)))
+	w.file = nil
+	w.gz = nil
+	w.counter = nil
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("error rotating file %s, %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Write appends a record as a json line, the record is flushed to the file before Write returns
+func (w *Writer) Write(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		if _, err := w.gz.Write(data); err != nil {
+			return err
+		}
+		if err := w.gz.Flush(); err != nil {
+			return err
+		}
+	} else if _, err := w.counter.Write(data); err != nil {
+		return err
+	}
+	if w.opts.MaxSize > 0 && w.counter.count >= w.opts.MaxSize {
+		return w.rotate()
+	}
+	return nil
+}
+
+// Close closes the current file, the writer can not be used after it is closed
+func (w *Writer) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	return w.rotate()
+}
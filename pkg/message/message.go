@@ -0,0 +1,245 @@
+package message
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+// Body returns the body of a kubemq message or a query response, false for other values
+func Body(value interface{}) ([]byte, bool) {
+	switch val := value.(type) {
+	case *kubemq.Event:
+		return val.Body, true
+	case *kubemq.EventStoreReceive:
+		return val.Body, true
+	case *kubemq.CommandReceive:
+		return val.Body, true
+	case *kubemq.QueryReceive:
+		return val.Body, true
+	case *kubemq.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil, false
+		}
+		return val.Body, true
+	case *queues_stream.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil, false
+		}
+		return val.Body, true
+	case *kubemq.QueryResponse:
+		if val == nil {
+			return nil, false
+		}
+		return val.Body, true
+	default:
+		return nil, false
+	}
+}
+
+// Tags returns the tags of a kubemq message or a response, nil for other values
+func Tags(value interface{}) map[string]string {
+	switch val := value.(type) {
+	case *kubemq.Event:
+		return val.Tags
+	case *kubemq.EventStoreReceive:
+		return val.Tags
+	case *kubemq.CommandReceive:
+		return val.Tags
+	case *kubemq.QueryReceive:
+		return val.Tags
+	case *kubemq.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil
+		}
+		return val.Tags
+	case *queues_stream.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil
+		}
+		return val.Tags
+	case *kubemq.QueryResponse:
+		if val == nil {
+			return nil
+		}
+		return val.Tags
+	case *kubemq.CommandResponse:
+		if val == nil {
+			return nil
+		}
+		return val.Tags
+	default:
+		return nil
+	}
+}
+
+// Channel returns the channel of a kubemq message, empty for other values
+func Channel(value interface{}) string {
+	switch val := value.(type) {
+	case *kubemq.Event:
+		return val.Channel
+	case *kubemq.EventStoreReceive:
+		return val.Channel
+	case *kubemq.CommandReceive:
+		return val.Channel
+	case *kubemq.QueryReceive:
+		return val.Channel
+	case *kubemq.QueueMessage:
+		if val.QueueMessage == nil {
+			return ""
+		}
+		return val.Channel
+	case *queues_stream.QueueMessage:
+		if val.QueueMessage == nil {
+			return ""
+		}
+		return val.Channel
+	default:
+		return ""
+	}
+}
+
+// Metadata returns the metadata of a kubemq message or a query response, empty for other values
+func Metadata(value interface{}) string {
+	switch val := value.(type) {
+	case *kubemq.Event:
+		return val.Metadata
+	case *kubemq.EventStoreReceive:
+		return val.Metadata
+	case *kubemq.CommandReceive:
+		return val.Metadata
+	case *kubemq.QueryReceive:
+		return val.Metadata
+	case *kubemq.QueueMessage:
+		if val.QueueMessage == nil {
+			return ""
+		}
+		return val.Metadata
+	case *queues_stream.QueueMessage:
+		if val.QueueMessage == nil {
+			return ""
+		}
+		return val.Metadata
+	case *kubemq.QueryResponse:
+		if val == nil {
+			return ""
+		}
+		return val.Metadata
+	default:
+		return ""
+	}
+}
+
+// CopyTags returns a copy of the tags which can be modified, never nil
+func CopyTags(tags map[string]string) map[string]string {
+	c := make(map[string]string, len(tags))
+	for key, value := range tags {
+		c[key] = value
+	}
+	return c
+}
+
+// Clone returns a copy of a kubemq message or a response with the body and tags replaced.
+// The original message is shared between the binding targets, so it is never modified.
+func Clone(value interface{}, body []byte, tags map[string]string) (interface{}, error) {
+	switch val := value.(type) {
+	case *kubemq.Event:
+		c := *val
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.EventStoreReceive:
+		c := *val
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.CommandReceive:
+		c := *val
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.QueryReceive:
+		c := *val
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil, fmt.Errorf("invalid empty queue message")
+		}
+		c := *val
+		pm := *val.QueueMessage
+		c.QueueMessage = &pm
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *queues_stream.QueueMessage:
+		if val.QueueMessage == nil {
+			return nil, fmt.Errorf("invalid empty queue message")
+		}
+		c := *val
+		pm := *val.QueueMessage
+		c.QueueMessage = &pm
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.QueryResponse:
+		if val == nil {
+			return nil, fmt.Errorf("invalid nil query response")
+		}
+		c := *val
+		c.Body, c.Tags = body, tags
+		return &c, nil
+	case *kubemq.CommandResponse:
+		if val == nil {
+			return nil, fmt.Errorf("invalid nil command response")
+		}
+		c := *val
+		c.Tags = tags
+		return &c, nil
+	default:
+		return nil, fmt.Errorf("invalid message type %T", value)
+	}
+}
+
+// Fields are the fields of a kubemq message which middlewares read and replace
+type Fields struct {
+	Channel  string
+	Metadata string
+	Body     []byte
+	Tags     map[string]string
+}
+
+// GetFields returns the fields of a kubemq message or a query response, false for other values
+func GetFields(value interface{}) (Fields, bool) {
+	body, ok := Body(value)
+	if !ok {
+		return Fields{}, false
+	}
+	return Fields{
+		Channel:  Channel(value),
+		Metadata: Metadata(value),
+		Body:     body,
+		Tags:     Tags(value),
+	}, true
+}
+
+// WithFields returns a copy of a kubemq message with the fields replaced, query responses have no channel to replace
+func WithFields(value interface{}, fields Fields) (interface{}, error) {
+	c, err := Clone(value, fields.Body, fields.Tags)
+	if err != nil {
+		return nil, err
+	}
+	switch val := c.(type) {
+	case *kubemq.Event:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *kubemq.EventStoreReceive:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *kubemq.CommandReceive:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *kubemq.QueryReceive:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *kubemq.QueueMessage:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *queues_stream.QueueMessage:
+		val.Channel, val.Metadata = fields.Channel, fields.Metadata
+	case *kubemq.QueryResponse:
+		val.Metadata = fields.Metadata
+	}
+	return c, nil
+}
@@ -0,0 +1,92 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{
+			name:  "event",
+			value: &kubemq.Event{Channel: "ch", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:  "event store",
+			value: &kubemq.EventStoreReceive{Channel: "ch", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:  "command",
+			value: &kubemq.CommandReceive{Channel: "ch", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:  "query",
+			value: &kubemq.QueryReceive{Channel: "ch", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:  "queue",
+			value: kubemq.NewQueueMessage().SetChannel("ch").SetBody([]byte("body")).SetTags(map[string]string{"key": "value"}),
+		},
+		{
+			name:  "queues stream",
+			value: queues_stream.NewQueueMessage().SetChannel("ch").SetBody([]byte("body")).SetTags(map[string]string{"key": "value"}),
+		},
+		{
+			name:  "query response",
+			value: &kubemq.QueryResponse{Executed: true, Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:    "invalid - empty queue message",
+			value:   &kubemq.QueueMessage{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - unknown type",
+			value:   "body",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := CopyTags(Tags(tt.value))
+			tags["new"] = "tag"
+			c, err := Clone(tt.value, []byte("new-body"), tags)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			body, ok := Body(c)
+			require.True(t, ok)
+			require.Equal(t, []byte("new-body"), body)
+			require.Equal(t, "tag", Tags(c)["new"])
+			body, ok = Body(tt.value)
+			require.True(t, ok)
+			require.Equal(t, []byte("body"), body)
+			require.Equal(t, map[string]string{"key": "value"}, Tags(tt.value))
+		})
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	original := kubemq.NewQueueMessage().SetChannel("ch").SetMetadata("md").SetBody([]byte("body"))
+	fields, ok := GetFields(original)
+	require.True(t, ok)
+	require.Equal(t, Fields{Channel: "ch", Metadata: "md", Body: []byte("body"), Tags: original.Tags}, fields)
+	fields.Channel, fields.Metadata = "new-ch", "new-md"
+	c, err := WithFields(original, fields)
+	require.NoError(t, err)
+	require.Equal(t, "new-ch", Channel(c))
+	require.Equal(t, "new-md", Metadata(c))
+	require.Equal(t, "ch", original.Channel)
+	require.Equal(t, "md", original.Metadata)
+	_, ok = GetFields("body")
+	require.False(t, ok)
+}
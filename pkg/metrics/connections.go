@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var connectionLabels = []string{"binding", "source_kind", "connection"}
+
+// ConnectionReport is the reconnects count of a binding source connection
+type ConnectionReport struct {
+	Binding    string
+	SourceKind string
+	Connection string
+	Reconnects int
+}
+
+// connectionsCollector exports the sources connections reconnects counts, which are read on each scrape
+type connectionsCollector struct {
+	desc *prometheus.Desc
+	list func() []*ConnectionReport
+}
+
+func newConnectionsCollector(list func() []*ConnectionReport) *connectionsCollector {
+	return &connectionsCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName("kubemq_targets", "connections", "reconnects"),
+			"counts sources connections resubscriptions per binding, source type and connection",
+			connectionLabels,
+			nil),
+		list: list,
+	}
+}
+
+func (c *connectionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *connectionsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, report := range c.list() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(report.Reconnects), report.Binding, report.SourceKind, report.Connection)
+	}
+}
+
+// RegisterConnections registers a collector of the sources connections reconnects counts
+func (e *Exporter) RegisterConnections(list func() []*ConnectionReport) error {
+	return prometheus.Register(newConnectionsCollector(list))
+}
@@ -15,6 +15,8 @@ type Exporter struct {
 	requestsVolumeCollector  *promCounterMetric
 	responsesVolumeCollector *promCounterMetric
 	errorsCollector          *promCounterMetric
+	cacheHitsCollector       *promCounterMetric
+	cacheMissesCollector     *promCounterMetric
 }
 
 func (e *Exporter) PrometheusHandler() http.Handler {
@@ -29,6 +31,8 @@ func NewExporter() (*Exporter, error) {
 		requestsVolumeCollector:  nil,
 		responsesVolumeCollector: nil,
 		errorsCollector:          nil,
+		cacheHitsCollector:       nil,
+		cacheMissesCollector:     nil,
 	}
 	if err := e.initPromMetrics(); err != nil {
 		return nil, err
@@ -67,6 +71,18 @@ func (e *Exporter) initPromMetrics() error {
 		"counts error requests per binding,source and target types",
 		labels...,
 	)
+	e.cacheHitsCollector = newPromCounterMetric(
+		"cache",
+		"hits",
+		"counts query responses served from cache per binding,source and target types",
+		labels...,
+	)
+	e.cacheMissesCollector = newPromCounterMetric(
+		"cache",
+		"misses",
+		"counts query responses not found in cache per binding,source and target types",
+		labels...,
+	)
 
 	err := prometheus.Register(e.requestsCollector.metric)
 	if err != nil {
@@ -88,6 +104,14 @@ func (e *Exporter) initPromMetrics() error {
 	if err != nil {
 		return err
 	}
+	err = prometheus.Register(e.cacheHitsCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.cacheMissesCollector.metric)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -99,5 +123,7 @@ func (e *Exporter) Report(m *Report) {
 	e.responsesCollector.add(m.ResponseCount, lbs)
 	e.responsesVolumeCollector.add(m.ResponseVolume, lbs)
 	e.errorsCollector.add(m.ErrorsCount, lbs)
+	e.cacheHitsCollector.add(m.CacheHits, lbs)
+	e.cacheMissesCollector.add(m.CacheMisses, lbs)
 	e.Store.Add(m)
 }
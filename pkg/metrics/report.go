@@ -14,6 +14,8 @@ type Report struct {
 	ResponseCount  float64 `json:"response_count"`
 	ResponseVolume float64 `json:"response_volume"`
 	ErrorsCount    float64 `json:"errors_count"`
+	CacheHits      float64 `json:"cache_hits"`
+	CacheMisses    float64 `json:"cache_misses"`
 }
 
 func (m *Report) labels() prometheus.Labels {
@@ -35,5 +37,7 @@ func (m *Report) Clone() *Report {
 		ResponseCount:  m.ResponseCount,
 		ResponseVolume: m.ResponseVolume,
 		ErrorsCount:    m.ErrorsCount,
+		CacheHits:      m.CacheHits,
+		CacheMisses:    m.CacheMisses,
 	}
 }
@@ -20,6 +20,8 @@ func (s *Store) Add(report *Report) {
 		loaded.ResponseCount += report.ResponseCount
 		loaded.RequestVolume += report.RequestVolume
 		loaded.RequestCount += report.RequestCount
+		loaded.CacheHits += report.CacheHits
+		loaded.CacheMisses += report.CacheMisses
 	} else {
 		s.store.Store(report.Key, report.Clone())
 	}
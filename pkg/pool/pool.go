@@ -0,0 +1,377 @@
+package pool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	KindClient       = "client"
+	KindQueuesStream = "queues_stream"
+
+	StateConnected    = "connected"
+	StateDisconnected = "disconnected"
+
+	pingTimeout = 2 * time.Second
+)
+
+var defaultPool = New()
+
+// Options are the connection settings of a pooled client, clients are shared between connections with the same address, transport, auth and tls settings.
+// ClientId is the client id of a new client, sources subscriptions set their own client id on a shared client.
+type Options struct {
+	Host      string
+	Port      int
+	Transport string
+	Uri       string
+	AuthToken string
+	CertFile  string
+	CertData  string
+	ClientId  string
+}
+
+func (o Options) address() string {
+	if o.Transport == TransportRest {
+		return o.Uri
+	}
+	return fmt.Sprintf("%s:%d", o.Host, o.Port)
+}
+
+func (o Options) transport() string {
+	if o.Transport == "" {
+		return TransportGRPC
+	}
+	return o.Transport
+}
+
+func (o Options) key() string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(strings.Join([]string{o.transport(), o.address(), o.AuthToken, o.CertFile, o.CertData}, "|")))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+type entry struct {
+	sync.Mutex
+	kind         string
+	key          string
+	opts         Options
+	refs         int
+	createdAt    time.Time
+	cancel       context.CancelFunc
+	client       *kubemq.Client
+	streamClient *queues_stream.QueuesStreamClient
+	state        string
+	lastMessage  string
+}
+
+func (e *entry) setState(msg string) {
+	e.Lock()
+	defer e.Unlock()
+	e.lastMessage = msg
+	switch {
+	case strings.Contains(msg, "disconnected"), strings.Contains(msg, "error"):
+		e.state = StateDisconnected
+	case strings.Contains(msg, "connected"):
+		e.state = StateConnected
+	}
+}
+
+// Stats is a report of a pooled client
+type Stats struct {
+	Key        string    `json:"key"`
+	Kind       string    `json:"kind"`
+	Address    string    `json:"address"`
+	Transport  string    `json:"transport"`
+	ClientId   string    `json:"client_id"`
+	Secured    bool      `json:"secured"`
+	References int       `json:"references"`
+	CreatedAt  time.Time `json:"created_at"`
+	State      string    `json:"state"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// Pool holds kubemq clients shared between sources and targets connections, with reference counting
+type Pool struct {
+	sync.Mutex
+	log           *logger.Logger
+	clients       map[string]*entry
+	streamClients map[string]*entry
+	newClient     func(ctx context.Context, op ...kubemq.Option) (*kubemq.Client, error)
+}
+
+func New() *Pool {
+	return &Pool{
+		log:           logger.NewLogger("client-pool"),
+		clients:       map[string]*entry{},
+		streamClients: map[string]*entry{},
+		newClient:     kubemq.NewClient,
+	}
+}
+
+// GetClient returns a shared kubemq client for the connection options, the client must be released with ReleaseClient
+func GetClient(ctx context.Context, opts Options) (*kubemq.Client, error) {
+	return defaultPool.GetClient(ctx, opts)
+}
+
+// ReleaseClient releases a client reference, the client is closed when no references are left
+func ReleaseClient(client *kubemq.Client) {
+	defaultPool.ReleaseClient(client)
+}
+
+// GetQueuesStreamClient returns a shared queues stream client for the connection options, the client must be released with ReleaseQueuesStreamClient
+func GetQueuesStreamClient(ctx context.Context, opts Options) (*queues_stream.QueuesStreamClient, error) {
+	return defaultPool.GetQueuesStreamClient(ctx, opts)
+}
+
+// ReleaseQueuesStreamClient releases a queues stream client reference, the client is closed when no references are left
+func ReleaseQueuesStreamClient(client *queues_stream.QueuesStreamClient) {
+	defaultPool.ReleaseQueuesStreamClient(client)
+}
+
+// List returns the stats of all the pooled clients
+func List(ctx context.Context) []*Stats {
+	return defaultPool.List(ctx)
+}
+
+// GetClient returns the pooled client of the options key, a new client is dialed without holding the pool lock,
+// so a slow or unreachable server does not block the other pool users
+func (p *Pool) GetClient(ctx context.Context, opts Options) (*kubemq.Client, error) {
+	key := opts.key()
+	p.Lock()
+	if e, ok := p.clients[key]; ok {
+		e.refs++
+		p.Unlock()
+		return e.client, nil
+	}
+	p.Unlock()
+	clientOpts := []kubemq.Option{
+		kubemq.WithClientId(opts.ClientId),
+		kubemq.WithCheckConnection(true),
+		kubemq.WithAuthToken(opts.AuthToken),
+		// subscriptions are resubscribed by the sources and streams are restarted by the targets, so the client does not reconnect them
+		kubemq.WithAutoReconnect(false),
+	}
+	if opts.transport() == TransportRest {
+		clientOpts = append(clientOpts, kubemq.WithUri(opts.Uri), kubemq.WithTransportType(kubemq.TransportTypeRest))
+	} else {
+		clientOpts = append(clientOpts, kubemq.WithAddress(opts.Host, opts.Port), kubemq.WithTransportType(kubemq.TransportTypeGRPC))
+	}
+	if opts.CertFile != "" {
+		clientOpts = append(clientOpts, kubemq.WithCredentials(opts.CertFile, ""))
+	} else if opts.CertData != "" {
+		clientOpts = append(clientOpts, kubemq.WithCertificate(opts.CertData, ""))
+	}
+	// pooled clients outlive the binding which opened them, so they are bound to their own context
+	clientCtx, cancel := context.WithCancel(context.Background())
+	client, err := newWithContext(ctx, func() (*kubemq.Client, error) {
+		return p.newClient(clientCtx, clientOpts...)
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	p.Lock()
+	defer p.Unlock()
+	// a concurrent call may have added a client of the same key while dialing, which is shared instead
+	if e, ok := p.clients[key]; ok {
+		e.refs++
+		_ = client.Close()
+		cancel()
+		return e.client, nil
+	}
+	p.clients[key] = &entry{
+		kind:      KindClient,
+		key:       key,
+		opts:      opts,
+		refs:      1,
+		createdAt: time.Now(),
+		cancel:    cancel,
+		client:    client,
+		state:     StateConnected,
+	}
+	p.log.Infof("new client to %s created, pool size: %d", opts.address(), p.size())
+	return client, nil
+}
+
+func (p *Pool) ReleaseClient(client *kubemq.Client) {
+	if client == nil {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	for key, e := range p.clients {
+		if e.client != client {
+			continue
+		}
+		e.refs--
+		if e.refs <= 0 {
+			_ = e.client.Close()
+			e.cancel()
+			delete(p.clients, key)
+			p.log.Infof("client to %s closed, pool size: %d", e.opts.address(), p.size())
+		}
+		return
+	}
+}
+
+func (p *Pool) GetQueuesStreamClient(ctx context.Context, opts Options) (*queues_stream.QueuesStreamClient, error) {
+	if opts.transport() != TransportGRPC {
+		return nil, fmt.Errorf("queues stream client is not supported by the %s transport", opts.transport())
+	}
+	key := opts.key()
+	p.Lock()
+	if e, ok := p.streamClients[key]; ok {
+		e.refs++
+		p.Unlock()
+		return e.streamClient, nil
+	}
+	p.Unlock()
+	e := &entry{
+		kind:      KindQueuesStream,
+		key:       key,
+		opts:      opts,
+		refs:      1,
+		createdAt: time.Now(),
+		state:     StateConnected,
+	}
+	clientOpts := []queues_stream.Option{
+		queues_stream.WithAddress(opts.Host, opts.Port),
+		queues_stream.WithClientId(opts.ClientId),
+		queues_stream.WithCheckConnection(true),
+		queues_stream.WithAutoReconnect(true),
+		queues_stream.WithAuthToken(opts.AuthToken),
+		queues_stream.WithConnectionNotificationFunc(
+			func(msg string) {
+				e.setState(msg)
+				p.log.Infof("connection: %s, %s", opts.address(), msg)
+			}),
+	}
+	if opts.CertFile != "" {
+		clientOpts = append(clientOpts, queues_stream.WithCredentials(opts.CertFile, ""))
+	} else if opts.CertData != "" {
+		clientOpts = append(clientOpts, queues_stream.WithCertificate(opts.CertData, ""))
+	}
+	clientCtx, cancel := context.WithCancel(context.Background())
+	client, err := newWithContext(ctx, func() (*queues_stream.QueuesStreamClient, error) {
+		return queues_stream.NewQueuesStreamClient(clientCtx, clientOpts...)
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	p.Lock()
+	defer p.Unlock()
+	if shared, ok := p.streamClients[key]; ok {
+		shared.refs++
+		_ = client.Close()
+		cancel()
+		return shared.streamClient, nil
+	}
+	e.cancel = cancel
+	e.streamClient = client
+	p.streamClients[key] = e
+	p.log.Infof("new queues stream client to %s created, pool size: %d", opts.address(), p.size())
+	return client, nil
+}
+
+func (p *Pool) ReleaseQueuesStreamClient(client *queues_stream.QueuesStreamClient) {
+	if client == nil {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	for key, e := range p.streamClients {
+		if e.streamClient != client {
+			continue
+		}
+		e.refs--
+		if e.refs <= 0 {
+			_ = e.streamClient.Close()
+			e.cancel()
+			delete(p.streamClients, key)
+			p.log.Infof("queues stream client to %s closed, pool size: %d", e.opts.address(), p.size())
+		}
+		return
+	}
+}
+
+func (p *Pool) size() int {
+	return len(p.clients) + len(p.streamClients)
+}
+
+// List returns the stats of all the pooled clients, the kubemq clients health is checked with a ping
+func (p *Pool) List(ctx context.Context) []*Stats {
+	p.Lock()
+	var entries []*entry
+	for _, e := range p.clients {
+		entries = append(entries, e)
+	}
+	for _, e := range p.streamClients {
+		entries = append(entries, e)
+	}
+	var list []*Stats
+	for _, e := range entries {
+		list = append(list, &Stats{
+			Key:        e.key,
+			Kind:       e.kind,
+			Address:    e.opts.address(),
+			Transport:  e.opts.transport(),
+			ClientId:   e.opts.ClientId,
+			Secured:    e.opts.CertFile != "" || e.opts.CertData != "",
+			References: e.refs,
+			CreatedAt:  e.createdAt,
+		})
+	}
+	p.Unlock()
+	for i, e := range entries {
+		if e.kind == KindClient {
+			pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			_, err := e.client.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				e.setState(fmt.Sprintf("ping error, %s", err.Error()))
+			} else {
+				e.setState("ping connected")
+			}
+		}
+		e.Lock()
+		list[i].State = e.state
+		list[i].Message = e.lastMessage
+		e.Unlock()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// newWithContext runs a client constructor and returns early with the caller context error when the caller context is done first,
+// a client created after that is closed by cancelling its own context
+func newWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		client T
+		err    error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		client, err := fn()
+		resultCh <- result{client: client, err: err}
+	}()
+	select {
+	case r := <-resultCh:
+		return r.client, r.err
+	case <-ctx.Done():
+		var empty T
+		return empty, ctx.Err()
+	}
+}
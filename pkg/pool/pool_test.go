@@ -0,0 +1,225 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Key(t *testing.T) {
+	base := Options{
+		Host:      "localhost",
+		Port:      50000,
+		AuthToken: "token",
+		ClientId:  "client-1",
+	}
+	tests := []struct {
+		name     string
+		opts     Options
+		wantSame bool
+	}{
+		{
+			name:     "same connection",
+			opts:     base,
+			wantSame: true,
+		},
+		{
+			name: "different client id",
+			opts: Options{
+				Host:      "localhost",
+				Port:      50000,
+				AuthToken: "token",
+				ClientId:  "client-2",
+			},
+			wantSame: true,
+		},
+		{
+			name: "different address",
+			opts: Options{
+				Host:      "localhost",
+				Port:      50001,
+				AuthToken: "token",
+			},
+			wantSame: false,
+		},
+		{
+			name: "different auth token",
+			opts: Options{
+				Host:      "localhost",
+				Port:      50000,
+				AuthToken: "other-token",
+			},
+			wantSame: false,
+		},
+		{
+			name: "different transport",
+			opts: Options{
+				Host:      "localhost",
+				Port:      50000,
+				Transport: TransportRest,
+				Uri:       "http://localhost:50000",
+				AuthToken: "token",
+			},
+			wantSame: false,
+		},
+		{
+			name: "different tls settings",
+			opts: Options{
+				Host:      "localhost",
+				Port:      50000,
+				AuthToken: "token",
+				CertFile:  "./cert.pem",
+			},
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantSame {
+				require.Equal(t, base.key(), tt.opts.key())
+			} else {
+				require.NotEqual(t, base.key(), tt.opts.key())
+			}
+			require.NotContains(t, tt.opts.key(), tt.opts.AuthToken)
+		})
+	}
+}
+
+func TestPool_GetClientError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := New()
+	opts := Options{
+		Host:     "127.0.0.1",
+		Port:     1,
+		ClientId: "pool-test",
+	}
+	client, err := p.GetClient(ctx, opts)
+	require.Error(t, err)
+	require.Nil(t, client)
+	require.Empty(t, p.List(ctx))
+	p.ReleaseClient(nil)
+	p.ReleaseQueuesStreamClient(nil)
+	require.Empty(t, p.List(ctx))
+}
+
+func TestPool_GetClientDialUnlocked(t *testing.T) {
+	p := New()
+	shared := Options{Host: "localhost", Port: 50000}
+	p.clients[shared.key()] = &entry{kind: KindClient, key: shared.key(), opts: shared, refs: 1, client: &kubemq.Client{}}
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	p.newClient = func(ctx context.Context, op ...kubemq.Option) (*kubemq.Client, error) {
+		close(dialing)
+		<-release
+		return nil, fmt.Errorf("unreachable")
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := p.GetClient(context.Background(), Options{Host: "unreachable", Port: 50000})
+		errCh <- err
+	}()
+	<-dialing
+	// a slow dial does not block getting a pooled client of another connection
+	done := make(chan struct{})
+	go func() {
+		client, err := p.GetClient(context.Background(), shared)
+		require.NoError(t, err)
+		require.NotNil(t, client)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("get client is blocked by a dial of another connection")
+	}
+	require.Equal(t, 2, p.clients[shared.key()].refs)
+	close(release)
+	require.Error(t, <-errCh)
+	require.Len(t, p.clients, 1)
+}
+
+func TestNewWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newWithContext(ctx, func() (int, error) {
+		time.Sleep(time.Second)
+		return 1, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	val, err := newWithContext(context.Background(), func() (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, val)
+}
+
+func TestParseTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.Metadata
+		wantTransport string
+		wantUri       string
+		wantErr       bool
+	}{
+		{
+			name:          "default grpc",
+			cfg:           config.Metadata{},
+			wantTransport: TransportGRPC,
+		},
+		{
+			name:          "rest on the connection address",
+			cfg:           config.Metadata{"transport": "rest"},
+			wantTransport: TransportRest,
+			wantUri:       "http://localhost:9090",
+		},
+		{
+			name:          "uri selects rest",
+			cfg:           config.Metadata{"uri": "https://kubemq.example.com/api/"},
+			wantTransport: TransportRest,
+			wantUri:       "https://kubemq.example.com/api",
+		},
+		{
+			name:    "uri with grpc",
+			cfg:     config.Metadata{"transport": "grpc", "uri": "http://localhost:9090"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid transport",
+			cfg:     config.Metadata{"transport": "http"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid uri",
+			cfg:     config.Metadata{"uri": "localhost:9090"},
+			wantErr: true,
+		},
+		{
+			name:    "rest with tls cert",
+			cfg:     config.Metadata{"transport": "rest", "tls_cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, uri, err := ParseTransport(tt.cfg, "localhost", 9090)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTransport, transport)
+			require.Equal(t, tt.wantUri, uri)
+		})
+	}
+}
+
+func TestPool_GetQueuesStreamClientRest(t *testing.T) {
+	_, err := New().GetQueuesStreamClient(context.Background(), Options{Transport: TransportRest, Uri: "http://localhost:9090"})
+	require.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package pool
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	TransportGRPC = "grpc"
+	TransportRest = "rest"
+)
+
+var transports = map[string]string{
+	"":            "",
+	TransportGRPC: TransportGRPC,
+	TransportRest: TransportRest,
+}
+
+// ParseTransport parses the transport and uri connection values. A uri selects the rest transport, and the rest transport
+// without a uri connects to the http interface on the connection address. The rest transport does not support the tls
+// certificate values, a secured server is reached with an https uri.
+func ParseTransport(cfg config.Metadata, host string, port int) (string, string, error) {
+	transport, err := cfg.ParseStringMap("transport", transports)
+	if err != nil {
+		return "", "", fmt.Errorf("error parsing transport value, %w", err)
+	}
+	uri := strings.TrimSuffix(cfg.ParseString("uri", ""), "/")
+	switch transport {
+	case "":
+		if uri == "" {
+			return TransportGRPC, "", nil
+		}
+	case TransportGRPC:
+		if uri != "" {
+			return "", "", fmt.Errorf("error parsing uri value, uri is supported only by the rest transport")
+		}
+		return TransportGRPC, "", nil
+	}
+	if uri == "" {
+		uri = fmt.Sprintf("http://%s:%d", host, port)
+	}
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("error parsing uri value, invalid http or https uri %s", uri)
+	}
+	if cfg.ParseString("tls_cert_file", "") != "" || cfg.ParseString("tls_cert_data", "") != "" {
+		return "", "", fmt.Errorf("tls_cert_file and tls_cert_data are not supported by the rest transport, use an https uri")
+	}
+	return TransportRest, uri, nil
+}
@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kubemq-io/kubemq-bridges/pkg/convert"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const reloadDebounce = 200 * time.Millisecond
+
+// Registry holds the schemas of a local directory and reloads them when the directory changes.
+//
+// JSON schemas (.json) and avro schemas (.avsc) are registered by their path relative to the directory, without the extension.
+// Protobuf files (.proto) and descriptor sets (.pb, .desc, .protoset) register each of their message types by its full name.
+type Registry struct {
+	sync.RWMutex
+	dir         string
+	importPaths []string
+	schemas     map[string]Validator
+	log         *logger.Logger
+	fsWatcher   *fsnotify.Watcher
+	timer       *time.Timer
+}
+
+func NewRegistry(dir string, importPaths []string, log *logger.Logger) (*Registry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema registry directory, %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("schema registry %s is not a directory", dir)
+	}
+	r := &Registry{
+		dir:         dir,
+		importPaths: append([]string{dir}, importPaths...),
+		log:         log,
+	}
+	schemas, dirs, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.schemas = schemas
+	r.fsWatcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	r.watch(dirs)
+	go r.run()
+	return r, nil
+}
+
+// Get returns the schema registered with the id
+func (r *Registry) Get(id string) (Validator, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	v, ok := r.schemas[id]
+	return v, ok
+}
+
+// IDs returns the sorted ids of the registered schemas
+func (r *Registry) IDs() []string {
+	r.RLock()
+	defer r.RUnlock()
+	var ids []string
+	for id := range r.schemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *Registry) Close() error {
+	r.Lock()
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.Unlock()
+	return r.fsWatcher.Close()
+}
+
+func (r *Registry) load() (map[string]Validator, []string, error) {
+	schemas := map[string]Validator{}
+	var dirs []string
+	register := func(id string, v Validator, file string) error {
+		if _, ok := schemas[id]; ok {
+			return fmt.Errorf("duplicate schema id %s in %s", id, file)
+		}
+		schemas[id] = v
+		return nil
+	}
+	err := filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// kubernetes config maps keep their files in hidden ..data directories, which are read through the symlinks
+		if strings.HasPrefix(d.Name(), ".") && path != r.dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		rel, err := filepath.Rel(r.dir, path)
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		id := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		switch ext {
+		case ".json":
+			v, err := newJSONValidator(path)
+			if err != nil {
+				return err
+			}
+			return register(id, v, path)
+		case ".avsc":
+			v, err := newAvroValidator(path)
+			if err != nil {
+				return err
+			}
+			return register(id, v, path)
+		case ".proto", ".pb", ".desc", ".protoset":
+			files, err := convert.LoadDescriptors(path, r.importPaths)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				if err := registerMessages(file.Messages(), func(md protoreflect.MessageDescriptor) error {
+					return register(string(md.FullName()), &protobufValidator{descriptor: md}, path)
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading schema registry %s, %w", r.dir, err)
+	}
+	return schemas, dirs, nil
+}
+
+func registerMessages(messages protoreflect.MessageDescriptors, register func(md protoreflect.MessageDescriptor) error) error {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		if err := register(md); err != nil {
+			return err
+		}
+		if err := registerMessages(md.Messages(), register); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registry) watch(dirs []string) {
+	for _, dir := range dirs {
+		if err := r.fsWatcher.Add(dir); err != nil {
+			r.log.Errorf("error watching schema registry directory %s: %s", dir, err.Error())
+		}
+	}
+}
+
+// reload replaces the schemas with the directory contents, a directory which fails to load keeps the current schemas
+func (r *Registry) reload() {
+	schemas, dirs, err := r.load()
+	if err != nil {
+		r.log.Errorf("schema registry reload failed, keeping the current schemas: %s", err.Error())
+		return
+	}
+	r.watch(dirs)
+	r.Lock()
+	r.schemas = schemas
+	r.Unlock()
+	r.log.Infof("schema registry %s reloaded with %d schemas", r.dir, len(schemas))
+}
+
+func (r *Registry) run() {
+	for {
+		select {
+		case event, ok := <-r.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			r.Lock()
+			if r.timer != nil {
+				r.timer.Stop()
+			}
+			r.timer = time.AfterFunc(reloadDebounce, r.reload)
+			r.Unlock()
+		case err, ok := <-r.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			r.log.Errorf("schema registry watcher error: %s", err.Error())
+		}
+	}
+}
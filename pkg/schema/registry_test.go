@@ -0,0 +1,213 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	testJSONSchema = `{
+  "type": "object",
+  "properties": {"id": {"type": "integer"}},
+  "required": ["id"]
+}`
+	testAvroSchema = `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "long"}]}`
+	testProto      = `syntax = "proto3";
+package orders;
+
+message Order {
+  int64 id = 1;
+  message Line {
+    string sku = 1;
+  }
+}
+`
+)
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+}
+
+func newTestRegistry(t *testing.T) (*Registry, string) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "orders", "order.v1.json"), testJSONSchema)
+	writeFile(t, filepath.Join(dir, "order.avsc"), testAvroSchema)
+	writeFile(t, filepath.Join(dir, "orders.proto"), testProto)
+	writeFile(t, filepath.Join(dir, "README.md"), "not a schema")
+	r, err := NewRegistry(dir, nil, logger.NewLogger("schema-registry"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	return r, dir
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	require.Equal(t, []string{"order", "orders.Order", "orders.Order.Line", "orders/order.v1"}, r.IDs())
+	codec, err := goavro.NewCodec(testAvroSchema)
+	require.NoError(t, err)
+	avroBody, err := codec.BinaryFromNative(nil, map[string]interface{}{"id": int64(1)})
+	require.NoError(t, err)
+	protoBody := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 1)
+	unknownField := protowire.AppendVarint(protowire.AppendTag(nil, 2, protowire.VarintType), 1)
+	tests := []struct {
+		name    string
+		id      string
+		kind    string
+		body    []byte
+		wantErr bool
+	}{
+		{
+			name: "json - valid",
+			id:   "orders/order.v1",
+			kind: KindJSON,
+			body: []byte(`{"id":1}`),
+		},
+		{
+			name:    "json - missing field",
+			id:      "orders/order.v1",
+			kind:    KindJSON,
+			body:    []byte(`{"name":"order"}`),
+			wantErr: true,
+		},
+		{
+			name:    "json - not json",
+			id:      "orders/order.v1",
+			kind:    KindJSON,
+			body:    []byte(`order`),
+			wantErr: true,
+		},
+		{
+			name: "avro - valid",
+			id:   "order",
+			kind: KindAvro,
+			body: avroBody,
+		},
+		{
+			name:    "avro - trailing bytes",
+			id:      "order",
+			kind:    KindAvro,
+			body:    append(avroBody, 0x01),
+			wantErr: true,
+		},
+		{
+			name: "protobuf - valid",
+			id:   "orders.Order",
+			kind: KindProtobuf,
+			body: protoBody,
+		},
+		{
+			name:    "protobuf - unknown field",
+			id:      "orders.Order",
+			kind:    KindProtobuf,
+			body:    unknownField,
+			wantErr: true,
+		},
+		{
+			name:    "protobuf - not protobuf",
+			id:      "orders.Order.Line",
+			kind:    KindProtobuf,
+			body:    []byte{0xff},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := r.Get(tt.id)
+			require.True(t, ok)
+			require.Equal(t, tt.kind, v.Kind())
+			err := v.Validate(tt.body)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	r, dir := newTestRegistry(t)
+	_, ok := r.Get("customer")
+	require.False(t, ok)
+	writeFile(t, filepath.Join(dir, "customer.json"), `{"type": "string"}`)
+	require.Eventually(t, func() bool {
+		_, ok := r.Get("customer")
+		return ok
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// an invalid schema keeps the loaded schemas
+	writeFile(t, filepath.Join(dir, "broken.avsc"), `{"type": "record"}`)
+	time.Sleep(4 * reloadDebounce)
+	_, ok = r.Get("broken")
+	require.False(t, ok)
+	_, ok = r.Get("customer")
+	require.True(t, ok)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.avsc")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "customer.json")))
+	require.Eventually(t, func() bool {
+		_, ok := r.Get("customer")
+		return !ok
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestNewRegistry(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.json")
+	writeFile(t, file, testJSONSchema)
+	duplicate := t.TempDir()
+	writeFile(t, filepath.Join(duplicate, "a.proto"), testProto)
+	writeFile(t, filepath.Join(duplicate, "b.proto"), testProto)
+	broken := t.TempDir()
+	writeFile(t, filepath.Join(broken, "broken.json"), `{"type": 1}`)
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			dir:     dir,
+			wantErr: false,
+		},
+		{
+			name:    "invalid - missing directory",
+			dir:     filepath.Join(dir, "missing"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid - not a directory",
+			dir:     file,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - duplicate schema ids",
+			dir:     duplicate,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad schema",
+			dir:     broken,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRegistry(tt.dir, nil, logger.NewLogger("schema-registry"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+		})
+	}
+}
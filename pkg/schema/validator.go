@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	KindJSON     = "json"
+	KindProtobuf = "protobuf"
+	KindAvro     = "avro"
+)
+
+// Validator validates message bodies against a schema
+type Validator interface {
+	Kind() string
+	Validate(data []byte) error
+}
+
+type jsonValidator struct {
+	schema *jsonschema.Schema
+}
+
+func newJSONValidator(file string) (*jsonValidator, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	schema, err := compiler.Compile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json schema %s, %w", file, err)
+	}
+	return &jsonValidator{schema: schema}, nil
+}
+
+func (v *jsonValidator) Kind() string {
+	return KindJSON
+}
+
+func (v *jsonValidator) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid json body, %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("invalid json body, unexpected data after the json value")
+	}
+	return v.schema.Validate(value)
+}
+
+type protobufValidator struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+func (v *protobufValidator) Kind() string {
+	return KindProtobuf
+}
+
+// Validate rejects bodies which do not decode as the message type, or which have fields unknown to it
+func (v *protobufValidator) Validate(data []byte) error {
+	msg := dynamicpb.NewMessage(v.descriptor)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("invalid protobuf body for %s, %w", v.descriptor.FullName(), err)
+	}
+	if len(msg.GetUnknown()) > 0 {
+		return fmt.Errorf("invalid protobuf body for %s, unknown fields found", v.descriptor.FullName())
+	}
+	return nil
+}
+
+type avroValidator struct {
+	codec *goavro.Codec
+}
+
+func newAvroValidator(file string) (*avroValidator, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	codec, err := goavro.NewCodec(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %s, %w", file, err)
+	}
+	return &avroValidator{codec: codec}, nil
+}
+
+func (v *avroValidator) Kind() string {
+	return KindAvro
+}
+
+func (v *avroValidator) Validate(data []byte) error {
+	_, remaining, err := v.codec.NativeFromBinary(data)
+	if err != nil {
+		return fmt.Errorf("invalid avro body, %w", err)
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("invalid avro body, %d unexpected bytes after the record", len(remaining))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package script
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+type javaScriptVM struct {
+	runtime *goja.Runtime
+	fn      goja.Callable
+}
+
+// loadJavaScript loads scripts into runtimes without host access, as goja provides only the standard ecmascript built-ins
+func loadJavaScript(name string, source []byte, opts Options) (func() (vm, error), error) {
+	program, err := goja.Compile(name, string(source), true)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling javascript script, %w", err)
+	}
+	return func() (vm, error) {
+		runtime := goja.New()
+		runtime.SetMaxCallStackSize(opts.MaxStack)
+		if _, err := runtime.RunProgram(program); err != nil {
+			return nil, fmt.Errorf("error running javascript script, %w", err)
+		}
+		fn, ok := goja.AssertFunction(runtime.Get(functionName))
+		if !ok {
+			return nil, fmt.Errorf("javascript script does not define a %s function", functionName)
+		}
+		return &javaScriptVM{runtime: runtime, fn: fn}, nil
+	}, nil
+}
+
+func (j *javaScriptVM) process(ctx context.Context, msg Message) ([]Message, error) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			j.runtime.Interrupt(context.Cause(ctx))
+		case <-stop:
+		}
+	}()
+	// an interrupt which fires as the call returns must not stop the next call of the pooled runtime
+	defer func() {
+		close(stop)
+		<-stopped
+		j.runtime.ClearInterrupt()
+	}()
+	result, err := j.fn(goja.Undefined(), j.toObject(msg))
+	if err != nil {
+		if _, interrupted := err.(*goja.InterruptedError); interrupted {
+			return nil, fmt.Errorf("javascript script %s, %w", functionName, context.Cause(ctx))
+		}
+		return nil, fmt.Errorf("javascript script %s failed, %w", functionName, err)
+	}
+	if goja.IsNull(result) || goja.IsUndefined(result) {
+		return nil, nil
+	}
+	switch val := result.Export().(type) {
+	case []interface{}:
+		var list []Message
+		for _, item := range val {
+			m, err := fromObject(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, m)
+		}
+		return list, nil
+	default:
+		m, err := fromObject(val)
+		if err != nil {
+			return nil, err
+		}
+		return []Message{m}, nil
+	}
+}
+
+func (j *javaScriptVM) close() {}
+
+func (j *javaScriptVM) toObject(msg Message) goja.Value {
+	tags := j.runtime.NewObject()
+	for key, value := range msg.Tags {
+		_ = tags.Set(key, value)
+	}
+	obj := j.runtime.NewObject()
+	_ = obj.Set("channel", msg.Channel)
+	_ = obj.Set("metadata", msg.Metadata)
+	_ = obj.Set("body", string(msg.Body))
+	_ = obj.Set("tags", tags)
+	return obj
+}
+
+func fromObject(value interface{}) (Message, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return Message{}, fmt.Errorf("javascript script %s returned %T, a message, a list of messages or null is expected", functionName, value)
+	}
+	m := Message{
+		Tags: map[string]string{},
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"channel", &m.Channel},
+		{"metadata", &m.Metadata},
+	} {
+		switch val := obj[field.name].(type) {
+		case nil:
+		case string:
+			*field.value = val
+		default:
+			return Message{}, fmt.Errorf("message %s must be a string", field.name)
+		}
+	}
+	switch val := obj["body"].(type) {
+	case nil:
+	case string:
+		m.Body = []byte(val)
+	default:
+		return Message{}, fmt.Errorf("message body must be a string")
+	}
+	switch val := obj["tags"].(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, value := range val {
+			switch v := value.(type) {
+			case map[string]interface{}, []interface{}:
+				return Message{}, fmt.Errorf("message tags must be an object of strings")
+			default:
+				m.Tags[key] = fmt.Sprintf("%v", v)
+			}
+		}
+	default:
+		return Message{}, fmt.Errorf("message tags must be an object")
+	}
+	return m, nil
+}
@@ -0,0 +1,175 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// luaUnsafe are the base library functions removed from the sandbox, as they access files or load code
+var luaUnsafe = []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "collectgarbage", "print"}
+
+type luaVM struct {
+	state *lua.LState
+	fn    *lua.LFunction
+}
+
+func loadLua(name string, source []byte, opts Options) (func() (vm, error), error) {
+	chunk, err := parse.Parse(bytes.NewReader(source), name)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing lua script, %w", err)
+	}
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling lua script, %w", err)
+	}
+	return func() (vm, error) {
+		state := lua.NewState(lua.Options{
+			SkipOpenLibs:    true,
+			CallStackSize:   opts.MaxStack,
+			RegistrySize:    opts.MaxStack * 16,
+			RegistryMaxSize: opts.MaxStack * 1024,
+		})
+		for _, lib := range []struct {
+			name string
+			open lua.LGFunction
+		}{
+			{lua.BaseLibName, lua.OpenBase},
+			{lua.TabLibName, lua.OpenTable},
+			{lua.StringLibName, lua.OpenString},
+			{lua.MathLibName, lua.OpenMath},
+		} {
+			if err := state.CallByParam(lua.P{Fn: state.NewFunction(lib.open), NRet: 0, Protect: true}, lua.LString(lib.name)); err != nil {
+				state.Close()
+				return nil, err
+			}
+		}
+		for _, name := range luaUnsafe {
+			state.SetGlobal(name, lua.LNil)
+		}
+		state.Push(state.NewFunctionFromProto(proto))
+		if err := state.PCall(0, lua.MultRet, nil); err != nil {
+			state.Close()
+			return nil, fmt.Errorf("error running lua script, %w", err)
+		}
+		fn, ok := state.GetGlobal(functionName).(*lua.LFunction)
+		if !ok {
+			state.Close()
+			return nil, fmt.Errorf("lua script does not define a %s function", functionName)
+		}
+		return &luaVM{state: state, fn: fn}, nil
+	}, nil
+}
+
+func (l *luaVM) process(ctx context.Context, msg Message) ([]Message, error) {
+	l.state.SetContext(ctx)
+	defer l.state.RemoveContext()
+	if err := l.state.CallByParam(lua.P{Fn: l.fn, NRet: 1, Protect: true}, l.toTable(msg)); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("lua script %s, %w", functionName, context.Cause(ctx))
+		}
+		return nil, fmt.Errorf("lua script %s failed, %w", functionName, err)
+	}
+	result := l.state.Get(-1)
+	l.state.Pop(1)
+	switch val := result.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case *lua.LTable:
+		// a table with array items is a list of messages, an empty table returns no messages
+		if _, isList := val.RawGetInt(1).(*lua.LTable); isList || isEmpty(val) {
+			var list []Message
+			for i := 1; i <= val.Len(); i++ {
+				item, ok := val.RawGetInt(i).(*lua.LTable)
+				if !ok {
+					return nil, fmt.Errorf("lua script %s returned a list item which is not a message", functionName)
+				}
+				m, err := fromTable(item)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, m)
+			}
+			return list, nil
+		}
+		m, err := fromTable(val)
+		if err != nil {
+			return nil, err
+		}
+		return []Message{m}, nil
+	default:
+		return nil, fmt.Errorf("lua script %s returned %s, a message, a list of messages or nil is expected", functionName, result.Type().String())
+	}
+}
+
+func (l *luaVM) close() {
+	l.state.Close()
+}
+
+func (l *luaVM) toTable(msg Message) *lua.LTable {
+	table := l.state.NewTable()
+	table.RawSetString("channel", lua.LString(msg.Channel))
+	table.RawSetString("metadata", lua.LString(msg.Metadata))
+	table.RawSetString("body", lua.LString(msg.Body))
+	tags := l.state.NewTable()
+	for key, value := range msg.Tags {
+		tags.RawSetString(key, lua.LString(value))
+	}
+	table.RawSetString("tags", tags)
+	return table
+}
+
+func isEmpty(table *lua.LTable) bool {
+	key, _ := table.Next(lua.LNil)
+	return key == lua.LNil
+}
+
+func fromTable(table *lua.LTable) (Message, error) {
+	m := Message{
+		Tags: map[string]string{},
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"channel", &m.Channel},
+		{"metadata", &m.Metadata},
+	} {
+		switch val := table.RawGetString(field.name).(type) {
+		case *lua.LNilType:
+		case lua.LString:
+			*field.value = string(val)
+		default:
+			return Message{}, fmt.Errorf("message %s must be a string", field.name)
+		}
+	}
+	switch val := table.RawGetString("body").(type) {
+	case *lua.LNilType:
+	case lua.LString:
+		m.Body = []byte(val)
+	default:
+		return Message{}, fmt.Errorf("message body must be a string")
+	}
+	switch val := table.RawGetString("tags").(type) {
+	case *lua.LNilType:
+	case *lua.LTable:
+		var err error
+		val.ForEach(func(key, value lua.LValue) {
+			k, kOk := key.(lua.LString)
+			if !kOk || value.Type() == lua.LTTable || value.Type() == lua.LTFunction {
+				err = fmt.Errorf("message tags must be a table of strings")
+				return
+			}
+			m.Tags[string(k)] = value.String()
+		})
+		if err != nil {
+			return Message{}, err
+		}
+	default:
+		return Message{}, fmt.Errorf("message tags must be a table")
+	}
+	return m, nil
+}
@@ -0,0 +1,279 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+const (
+	LanguageLua        = "lua"
+	LanguageJavaScript = "javascript"
+
+	reloadDebounce    = 200 * time.Millisecond
+	functionName      = "process"
+	heapCheckInterval = 5 * time.Millisecond
+	heapMetric        = "/memory/classes/heap/objects:bytes"
+)
+
+// Message is the message passed to and returned by the script function
+type Message struct {
+	Channel  string
+	Metadata string
+	Body     []byte
+	Tags     map[string]string
+}
+
+// Options are the limits of the script executions
+type Options struct {
+	// Timeout is the max run time of a single function call
+	Timeout time.Duration
+	// MaxStack is the max call stack depth
+	MaxStack int
+	// MaxMessages is the max number of messages a call may return
+	MaxMessages int
+	// MaxOutputSize is the max total size in bytes of the bodies a call may return
+	MaxOutputSize int
+	// MaxHeapGrowth is the max growth in bytes of the process heap while a call runs, 0 - no limit.
+	// The interpreters do not account memory per call, so the heap is sampled and the call is stopped
+	// once it grew by more than the limit, allocations of other goroutines count as well.
+	MaxHeapGrowth int
+}
+
+// vm is a single script interpreter, which is not safe for concurrent use
+type vm interface {
+	process(ctx context.Context, msg Message) ([]Message, error)
+	close()
+}
+
+// loader compiles a script source and returns a factory of interpreters which have the script loaded
+type loader func(name string, source []byte, opts Options) (func() (vm, error), error)
+
+// Language returns the language of a script file by its extension
+func Language(file string) (string, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".lua":
+		return LanguageLua, nil
+	case ".js":
+		return LanguageJavaScript, nil
+	default:
+		return "", fmt.Errorf("unknown script language of file %s", file)
+	}
+}
+
+type version struct {
+	factory func() (vm, error)
+	pool    chan vm
+}
+
+// Script runs the process function of a script file with a pool of interpreters, and reloads the file when it changes
+type Script struct {
+	sync.RWMutex
+	file      string
+	load      loader
+	opts      Options
+	current   *version
+	log       *logger.Logger
+	fsWatcher *fsnotify.Watcher
+	timer     *time.Timer
+}
+
+func New(file, language string, opts Options, log *logger.Logger) (*Script, error) {
+	s := &Script{
+		file: file,
+		opts: opts,
+		log:  log,
+	}
+	switch language {
+	case LanguageLua:
+		s.load = loadLua
+	case LanguageJavaScript:
+		s.load = loadJavaScript
+	default:
+		return nil, fmt.Errorf("invalid script language %s", language)
+	}
+	current, err := s.compile()
+	if err != nil {
+		return nil, err
+	}
+	s.current = current
+	s.fsWatcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// the directory is watched, as editors and config maps replace files rather than writing them
+	if err := s.fsWatcher.Add(filepath.Dir(file)); err != nil {
+		_ = s.fsWatcher.Close()
+		return nil, fmt.Errorf("error watching script file, %w", err)
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *Script) compile() (*version, error) {
+	source, err := os.ReadFile(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading script file, %w", err)
+	}
+	factory, err := s.load(filepath.Base(s.file), source, s.opts)
+	if err != nil {
+		return nil, err
+	}
+	// the first interpreter checks the script defines the process function
+	first, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	v := &version{
+		factory: factory,
+		pool:    make(chan vm, 16),
+	}
+	v.pool <- first
+	return v, nil
+}
+
+// Process calls the script function with a message, and returns the messages it returns, none when the message is dropped
+func (s *Script) Process(ctx context.Context, msg Message) ([]Message, error) {
+	s.RLock()
+	v := s.current
+	s.RUnlock()
+	var machine vm
+	select {
+	case machine = <-v.pool:
+	default:
+		var err error
+		machine, err = v.factory()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+	if s.opts.MaxHeapGrowth > 0 {
+		var cancelCause context.CancelCauseFunc
+		ctx, cancelCause = context.WithCancelCause(ctx)
+		defer cancelCause(nil)
+		go s.watchHeap(ctx, cancelCause)
+	}
+	result, err := machine.process(ctx, msg)
+	if err != nil {
+		// an interrupted interpreter may be left in any state, it is not reused
+		machine.close()
+		return nil, err
+	}
+	if err := s.checkLimits(result); err != nil {
+		machine.close()
+		return nil, err
+	}
+	select {
+	case v.pool <- machine:
+	default:
+		machine.close()
+	}
+	return result, nil
+}
+
+// watchHeap stops the call of the context when the process heap grows by more than the max heap growth
+func (s *Script) watchHeap(ctx context.Context, cancel context.CancelCauseFunc) {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	base := sample[0].Value.Uint64()
+	ticker := time.NewTicker(heapCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.Read(sample)
+			if current := sample[0].Value.Uint64(); current > base && current-base > uint64(s.opts.MaxHeapGrowth) {
+				cancel(fmt.Errorf("script exceeded the max heap growth of %d bytes", s.opts.MaxHeapGrowth))
+				return
+			}
+		}
+	}
+}
+
+func (s *Script) checkLimits(result []Message) error {
+	if len(result) > s.opts.MaxMessages {
+		return fmt.Errorf("script returned %d messages, max is %d", len(result), s.opts.MaxMessages)
+	}
+	size := 0
+	for _, msg := range result {
+		size += len(msg.Body)
+	}
+	if size > s.opts.MaxOutputSize {
+		return fmt.Errorf("script returned %d bytes, max is %d", size, s.opts.MaxOutputSize)
+	}
+	return nil
+}
+
+func (s *Script) Close() error {
+	s.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.Unlock()
+	return s.fsWatcher.Close()
+}
+
+// reload replaces the script with the file contents, a script which fails to load is logged and the current script is kept
+func (s *Script) reload() {
+	v, err := s.compile()
+	if err != nil {
+		s.log.Errorf("script %s reload failed, keeping the current script: %s", s.file, err.Error())
+		return
+	}
+	s.Lock()
+	old := s.current
+	s.current = v
+	s.Unlock()
+	// interpreters of the old script which are in use are returned to its pool, which is released with it
+	for {
+		select {
+		case machine := <-old.pool:
+			machine.close()
+		default:
+			s.log.Infof("script %s reloaded", s.file)
+			return
+		}
+	}
+}
+
+func (s *Script) run() {
+	name := filepath.Clean(s.file)
+	for {
+		select {
+		case event, ok := <-s.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			// kubernetes config maps are updated by swapping the ..data symlink
+			if filepath.Clean(event.Name) != name && !strings.HasPrefix(filepath.Base(event.Name), "..") {
+				continue
+			}
+			s.Lock()
+			if s.timer != nil {
+				s.timer.Stop()
+			}
+			s.timer = time.AfterFunc(reloadDebounce, s.reload)
+			s.Unlock()
+		case err, ok := <-s.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			s.log.Errorf("script watcher error: %s", err.Error())
+		}
+	}
+}
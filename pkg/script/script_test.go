@@ -0,0 +1,322 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLua = `
+function process(msg)
+  if msg.tags["drop"] == "true" then
+    return nil
+  end
+  if msg.tags["split"] == "true" then
+    local list = {}
+    for part in string.gmatch(msg.body, "[^,]+") do
+      table.insert(list, {channel = msg.channel, body = part, tags = {part = part}})
+    end
+    return list
+  end
+  msg.body = string.upper(msg.body)
+  msg.channel = msg.channel .. ".out"
+  msg.tags["script"] = "lua"
+  return msg
+end
+`
+	testJavaScript = `
+function process(msg) {
+  if (msg.tags["drop"] === "true") {
+    return null;
+  }
+  if (msg.tags["split"] === "true") {
+    return msg.body.split(",").map(function (part) {
+      return {channel: msg.channel, body: part, tags: {part: part}};
+    });
+  }
+  msg.body = msg.body.toUpperCase();
+  msg.channel = msg.channel + ".out";
+  msg.tags["script"] = "javascript";
+  return msg;
+}
+`
+)
+
+var testOptions = Options{
+	Timeout:       time.Second,
+	MaxStack:      256,
+	MaxMessages:   2,
+	MaxOutputSize: 1024,
+}
+
+func writeScript(t *testing.T, path, source string) {
+	require.NoError(t, os.WriteFile(path, []byte(source), 0600))
+}
+
+func newTestScript(t *testing.T, name, source string, opts Options) (*Script, string) {
+	file := filepath.Join(t.TempDir(), name)
+	writeScript(t, file, source)
+	language, err := Language(file)
+	require.NoError(t, err)
+	s, err := New(file, language, opts, logger.NewLogger("script"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, file
+}
+
+func TestScript_Process(t *testing.T) {
+	scripts := []struct {
+		name     string
+		file     string
+		source   string
+		language string
+	}{
+		{name: "lua", file: "transform.lua", source: testLua, language: LanguageLua},
+		{name: "javascript", file: "transform.js", source: testJavaScript, language: LanguageJavaScript},
+	}
+	tests := []struct {
+		name    string
+		msg     Message
+		want    []Message
+		wantErr bool
+	}{
+		{
+			name: "transform",
+			msg:  Message{Channel: "orders", Metadata: "md", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+			want: []Message{{Channel: "orders.out", Metadata: "md", Body: []byte("BODY"), Tags: map[string]string{"key": "value", "script": ""}}},
+		},
+		{
+			name: "drop",
+			msg:  Message{Channel: "orders", Body: []byte("body"), Tags: map[string]string{"drop": "true"}},
+		},
+		{
+			name: "split",
+			msg:  Message{Channel: "orders", Body: []byte("a,b"), Tags: map[string]string{"split": "true"}},
+			want: []Message{
+				{Channel: "orders", Body: []byte("a"), Tags: map[string]string{"part": "a"}},
+				{Channel: "orders", Body: []byte("b"), Tags: map[string]string{"part": "b"}},
+			},
+		},
+		{
+			name:    "invalid - too many messages",
+			msg:     Message{Channel: "orders", Body: []byte("a,b,c"), Tags: map[string]string{"split": "true"}},
+			wantErr: true,
+		},
+	}
+	for _, script := range scripts {
+		s, _ := newTestScript(t, script.file, script.source, testOptions)
+		for _, tt := range tests {
+			t.Run(script.name+" - "+tt.name, func(t *testing.T) {
+				got, err := s.Process(context.Background(), tt.msg)
+				if tt.wantErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				for _, want := range tt.want {
+					if _, ok := want.Tags["script"]; ok {
+						want.Tags["script"] = script.language
+					}
+				}
+				require.Equal(t, tt.want, got)
+			})
+		}
+	}
+}
+
+func TestScript_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		source  string
+		timeout bool
+	}{
+		{
+			name:    "lua - infinite loop",
+			file:    "loop.lua",
+			source:  `function process(msg) while true do end end`,
+			timeout: true,
+		},
+		{
+			name:    "javascript - infinite loop",
+			file:    "loop.js",
+			source:  `function process(msg) { while (true) {} }`,
+			timeout: true,
+		},
+		{
+			name:   "lua - recursion",
+			file:   "recursion.lua",
+			source: `function f(n) return f(n + 1) + 1 end function process(msg) return f(1) end`,
+		},
+		{
+			name:   "javascript - recursion",
+			file:   "recursion.js",
+			source: `function f(n) { return f(n + 1) + 1; } function process(msg) { return f(1); }`,
+		},
+		{
+			name:   "lua - output size",
+			file:   "size.lua",
+			source: `function process(msg) msg.body = string.rep("x", 2048) return msg end`,
+		},
+		{
+			name:   "javascript - output size",
+			file:   "size.js",
+			source: `function process(msg) { msg.body = "x".repeat(2048); return msg; }`,
+		},
+		{
+			name:   "lua - no file access",
+			file:   "io.lua",
+			source: `function process(msg) return io.open("/etc/passwd") end`,
+		},
+		{
+			name:   "lua - no code loading",
+			file:   "dofile.lua",
+			source: `function process(msg) return dofile("/etc/passwd") end`,
+		},
+		{
+			name:   "lua - no os access",
+			file:   "os.lua",
+			source: `function process(msg) os.exit(1) end`,
+		},
+		{
+			name:   "javascript - no host access",
+			file:   "require.js",
+			source: `function process(msg) { return require("fs").readFileSync("/etc/passwd"); }`,
+		},
+		{
+			name:   "lua - invalid result",
+			file:   "result.lua",
+			source: `function process(msg) return 1 end`,
+		},
+		{
+			name:   "javascript - invalid result",
+			file:   "result.js",
+			source: `function process(msg) { return {body: 1}; }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions
+			opts.Timeout = 200 * time.Millisecond
+			s, _ := newTestScript(t, tt.file, tt.source, opts)
+			_, err := s.Process(context.Background(), Message{Channel: "orders", Body: []byte("body")})
+			require.Error(t, err)
+			require.Equal(t, tt.timeout, errors.Is(err, context.DeadlineExceeded))
+			// a failed call does not break the following calls
+			_, err = s.Process(context.Background(), Message{Channel: "orders", Body: []byte("body")})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestScript_MaxHeapGrowth(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		source string
+	}{
+		{
+			name:   "lua",
+			file:   "heap.lua",
+			source: `function process(msg) local t = {} local i = 0 while true do i = i + 1 t[i] = string.rep("x", 1024) .. i end end`,
+		},
+		{
+			name:   "javascript",
+			file:   "heap.js",
+			source: `function process(msg) { var a = []; while (true) { a.push("x".repeat(1024) + a.length); } }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions
+			opts.Timeout = 10 * time.Second
+			opts.MaxHeapGrowth = 16 * 1024 * 1024
+			s, _ := newTestScript(t, tt.file, tt.source, opts)
+			_, err := s.Process(context.Background(), Message{Channel: "orders", Body: []byte("body")})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "max heap growth")
+			require.False(t, errors.Is(err, context.DeadlineExceeded))
+		})
+	}
+}
+
+func TestScript_Reload(t *testing.T) {
+	s, file := newTestScript(t, "reload.lua", `function process(msg) msg.body = "v1" return msg end`, testOptions)
+	body := func() string {
+		got, err := s.Process(context.Background(), Message{Channel: "orders"})
+		require.NoError(t, err)
+		return string(got[0].Body)
+	}
+	require.Equal(t, "v1", body())
+	writeScript(t, file, `function process(msg) msg.body = "v2" return msg end`)
+	require.Eventually(t, func() bool { return body() == "v2" }, 5*time.Second, 50*time.Millisecond)
+
+	// a script which fails to load keeps the current script
+	writeScript(t, file, `function process(msg`)
+	time.Sleep(4 * reloadDebounce)
+	require.Equal(t, "v2", body())
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.lua")
+	writeScript(t, valid, testLua)
+	syntax := filepath.Join(dir, "syntax.js")
+	writeScript(t, syntax, `function process(msg {`)
+	missing := filepath.Join(dir, "missing.lua")
+	writeScript(t, missing, `function other(msg) return msg end`)
+	tests := []struct {
+		name     string
+		file     string
+		language string
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			file:     valid,
+			language: LanguageLua,
+		},
+		{
+			name:     "invalid - syntax error",
+			file:     syntax,
+			language: LanguageJavaScript,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid - no process function",
+			file:     missing,
+			language: LanguageLua,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid - missing file",
+			file:     filepath.Join(dir, "none.lua"),
+			language: LanguageLua,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid - language",
+			file:     valid,
+			language: "python",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.file, tt.language, testOptions, logger.NewLogger("script"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, s.Close())
+		})
+	}
+}
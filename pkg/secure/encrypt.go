@@ -0,0 +1,133 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	AlgorithmAESGCM  = "aes-gcm"
+	AlgorithmNaClBox = "nacl-box"
+)
+
+// EncryptionKeySizes returns the valid key sizes of an encryption algorithm
+func EncryptionKeySizes(algorithm string) ([]int, error) {
+	switch algorithm {
+	case AlgorithmAESGCM:
+		return []int{16, 24, 32}, nil
+	case AlgorithmNaClBox:
+		return []int{32}, nil
+	default:
+		return nil, fmt.Errorf("invalid encryption algorithm %s", algorithm)
+	}
+}
+
+// Encrypter encrypts payloads with the current key, an aes key for aes-gcm or the recipient public key for nacl-box
+type Encrypter struct {
+	algorithm string
+	key       Key
+}
+
+func NewEncrypter(algorithm string, keys []Key) (*Encrypter, error) {
+	if _, err := EncryptionKeySizes(algorithm); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no encryption keys")
+	}
+	return &Encrypter{
+		algorithm: algorithm,
+		key:       keys[0],
+	}, nil
+}
+
+// Encrypt returns the encrypted payload and the id of the key it was encrypted with
+func (e *Encrypter) Encrypt(data []byte) (string, []byte, error) {
+	switch e.algorithm {
+	case AlgorithmAESGCM:
+		gcm, err := newGCM(e.key.Data)
+		if err != nil {
+			return "", nil, err
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", nil, err
+		}
+		return e.key.Id, gcm.Seal(nonce, nonce, data, []byte(e.key.Id)), nil
+	default:
+		var publicKey [32]byte
+		copy(publicKey[:], e.key.Data)
+		sealed, err := box.SealAnonymous(nil, data, &publicKey, rand.Reader)
+		if err != nil {
+			return "", nil, err
+		}
+		return e.key.Id, sealed, nil
+	}
+}
+
+// Decrypter decrypts payloads with any of its keys, aes keys for aes-gcm or the recipient private keys for nacl-box
+type Decrypter struct {
+	algorithm string
+	keys      *keyRing
+}
+
+func NewDecrypter(algorithm string, keys []Key) (*Decrypter, error) {
+	if _, err := EncryptionKeySizes(algorithm); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no decryption keys")
+	}
+	return &Decrypter{
+		algorithm: algorithm,
+		keys:      newKeyRing(keys),
+	}, nil
+}
+
+func (d *Decrypter) Decrypt(keyId string, data []byte) ([]byte, error) {
+	key, err := d.keys.get(keyId)
+	if err != nil {
+		return nil, err
+	}
+	switch d.algorithm {
+	case AlgorithmAESGCM:
+		gcm, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < gcm.NonceSize() {
+			return nil, fmt.Errorf("invalid encrypted payload")
+		}
+		plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(keyId))
+		if err != nil {
+			return nil, fmt.Errorf("invalid encrypted payload, %w", err)
+		}
+		return plain, nil
+	default:
+		var privateKey, publicKey [32]byte
+		copy(privateKey[:], key)
+		public, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+		if err != nil {
+			return nil, err
+		}
+		copy(publicKey[:], public)
+		plain, ok := box.OpenAnonymous(nil, data, &publicKey, &privateKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid encrypted payload")
+		}
+		return plain, nil
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
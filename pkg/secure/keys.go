@@ -0,0 +1,95 @@
+package secure
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Key is a key loaded from a file, identified by the file name without its extension
+type Key struct {
+	Id   string
+	Data []byte
+}
+
+// LoadKeys loads keys from files, in their order. Key files hold the key as base64 or hex text, or as raw bytes.
+// When sizes are set, a key must be one of the sizes.
+func LoadKeys(files []string, sizes ...int) ([]Key, error) {
+	var keys []Key
+	ids := map[string]bool{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading key file, %w", err)
+		}
+		key, err := parseKey(data, sizes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s, %w", file, err)
+		}
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if ids[id] {
+			return nil, fmt.Errorf("duplicate key id %s", id)
+		}
+		ids[id] = true
+		keys = append(keys, Key{Id: id, Data: key})
+	}
+	return keys, nil
+}
+
+func parseKey(data []byte, sizes []int) ([]byte, error) {
+	validSize := func(key []byte) bool {
+		if len(key) == 0 {
+			return false
+		}
+		if len(sizes) == 0 {
+			return true
+		}
+		for _, size := range sizes {
+			if len(key) == size {
+				return true
+			}
+		}
+		return false
+	}
+	text := string(bytes.TrimSpace(data))
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && validSize(key) {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(text); err == nil && validSize(key) {
+		return key, nil
+	}
+	if validSize(data) {
+		return data, nil
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+	return nil, fmt.Errorf("key size must be one of %v bytes", sizes)
+}
+
+// keyRing holds the keys by id
+type keyRing struct {
+	keys map[string][]byte
+}
+
+func newKeyRing(keys []Key) *keyRing {
+	r := &keyRing{
+		keys: map[string][]byte{},
+	}
+	for _, key := range keys {
+		r.keys[key.Id] = key.Data
+	}
+	return r
+}
+
+func (r *keyRing) get(id string) ([]byte, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %s", id)
+	}
+	return key, nil
+}
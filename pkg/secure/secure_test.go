@@ -0,0 +1,181 @@
+package secure
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+)
+
+func randomKey(t *testing.T, size int) []byte {
+	key := make([]byte, size)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func writeKey(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir := t.TempDir()
+	key := randomKey(t, 32)
+	tests := []struct {
+		name    string
+		files   []string
+		sizes   []int
+		want    []Key
+		wantErr bool
+	}{
+		{
+			name: "base64, hex and raw keys",
+			files: []string{
+				writeKey(t, dir, "k1.key", []byte(base64.StdEncoding.EncodeToString(key)+"\n")),
+				writeKey(t, dir, "k2.hex", []byte(hex.EncodeToString(key))),
+				writeKey(t, dir, "k3", key),
+			},
+			sizes: []int{32},
+			want:  []Key{{Id: "k1", Data: key}, {Id: "k2", Data: key}, {Id: "k3", Data: key}},
+		},
+		{
+			name:    "invalid - size",
+			files:   []string{writeKey(t, dir, "short.key", []byte("short"))},
+			sizes:   []int{16, 32},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - empty",
+			files:   []string{writeKey(t, dir, "empty.key", nil)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - duplicate id",
+			files:   []string{writeKey(t, dir, "dup.a", key), writeKey(t, dir, "dup.b", key)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - missing file",
+			files:   []string{filepath.Join(dir, "missing.key")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := LoadKeys(tt.files, tt.sizes...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, keys)
+		})
+	}
+}
+
+func TestEncrypter_Decrypt(t *testing.T) {
+	aesOld, aesNew := randomKey(t, 16), randomKey(t, 32)
+	private := randomKey(t, 32)
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	require.NoError(t, err)
+	tests := []struct {
+		name        string
+		algorithm   string
+		encryptKeys []Key
+		decryptKeys []Key
+	}{
+		{
+			name:        "aes-gcm with rotated keys",
+			algorithm:   AlgorithmAESGCM,
+			encryptKeys: []Key{{Id: "new", Data: aesNew}, {Id: "old", Data: aesOld}},
+			decryptKeys: []Key{{Id: "old", Data: aesOld}, {Id: "new", Data: aesNew}},
+		},
+		{
+			name:        "nacl-box",
+			algorithm:   AlgorithmNaClBox,
+			encryptKeys: []Key{{Id: "recipient", Data: public}},
+			decryptKeys: []Key{{Id: "recipient", Data: private}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypter, err := NewEncrypter(tt.algorithm, tt.encryptKeys)
+			require.NoError(t, err)
+			decrypter, err := NewDecrypter(tt.algorithm, tt.decryptKeys)
+			require.NoError(t, err)
+			keyId, encrypted, err := encrypter.Encrypt([]byte("payload"))
+			require.NoError(t, err)
+			require.Equal(t, tt.encryptKeys[0].Id, keyId)
+			require.NotContains(t, string(encrypted), "payload")
+			decrypted, err := decrypter.Decrypt(keyId, encrypted)
+			require.NoError(t, err)
+			require.Equal(t, []byte("payload"), decrypted)
+
+			encrypted[len(encrypted)-1] ^= 0xff
+			_, err = decrypter.Decrypt(keyId, encrypted)
+			require.Error(t, err)
+			_, err = decrypter.Decrypt("unknown", encrypted)
+			require.Error(t, err)
+		})
+	}
+	_, err = NewEncrypter("des", []Key{{Id: "key", Data: aesNew}})
+	require.Error(t, err)
+	_, err = NewDecrypter(AlgorithmAESGCM, nil)
+	require.Error(t, err)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := randomKey(t, 20)
+	tests := []struct {
+		name       string
+		algorithm  string
+		signKeys   []Key
+		verifyKeys []Key
+	}{
+		{
+			name:       "hmac-sha256",
+			algorithm:  AlgorithmHMACSHA256,
+			signKeys:   []Key{{Id: "secret", Data: secret}},
+			verifyKeys: []Key{{Id: "secret", Data: secret}},
+		},
+		{
+			name:       "ed25519 private key",
+			algorithm:  AlgorithmEd25519,
+			signKeys:   []Key{{Id: "signer", Data: private}},
+			verifyKeys: []Key{{Id: "other", Data: randomKey(t, 32)}, {Id: "signer", Data: public}},
+		},
+		{
+			name:       "ed25519 seed",
+			algorithm:  AlgorithmEd25519,
+			signKeys:   []Key{{Id: "signer", Data: private.Seed()}},
+			verifyKeys: []Key{{Id: "signer", Data: public}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSigner(tt.algorithm, tt.signKeys)
+			require.NoError(t, err)
+			verifier, err := NewVerifier(tt.algorithm, tt.verifyKeys)
+			require.NoError(t, err)
+			keyId, signature := signer.Sign([]byte("payload"))
+			require.Equal(t, tt.signKeys[0].Id, keyId)
+			require.NoError(t, verifier.Verify(keyId, []byte("payload"), signature))
+			require.Error(t, verifier.Verify(keyId, []byte("tampered"), signature))
+			require.Error(t, verifier.Verify("unknown", []byte("payload"), signature))
+		})
+	}
+	_, err = NewSigner("rsa", []Key{{Id: "key", Data: secret}})
+	require.Error(t, err)
+	_, err = NewVerifier(AlgorithmEd25519, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package secure
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+const (
+	AlgorithmHMACSHA256 = "hmac-sha256"
+	AlgorithmEd25519    = "ed25519"
+)
+
+// SigningKeySizes returns the valid signing key sizes of a signing algorithm, any size for hmac
+func SigningKeySizes(algorithm string) ([]int, error) {
+	switch algorithm {
+	case AlgorithmHMACSHA256:
+		return nil, nil
+	case AlgorithmEd25519:
+		return []int{ed25519.SeedSize, ed25519.PrivateKeySize}, nil
+	default:
+		return nil, fmt.Errorf("invalid signing algorithm %s", algorithm)
+	}
+}
+
+// VerifyingKeySizes returns the valid verifying key sizes of a signing algorithm, any size for hmac
+func VerifyingKeySizes(algorithm string) ([]int, error) {
+	switch algorithm {
+	case AlgorithmHMACSHA256:
+		return nil, nil
+	case AlgorithmEd25519:
+		return []int{ed25519.PublicKeySize}, nil
+	default:
+		return nil, fmt.Errorf("invalid signing algorithm %s", algorithm)
+	}
+}
+
+// Signer signs payloads with the current key, an hmac secret or an ed25519 private key
+type Signer struct {
+	algorithm string
+	key       Key
+}
+
+func NewSigner(algorithm string, keys []Key) (*Signer, error) {
+	if _, err := SigningKeySizes(algorithm); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+	key := keys[0]
+	if algorithm == AlgorithmEd25519 && len(key.Data) == ed25519.SeedSize {
+		key.Data = ed25519.NewKeyFromSeed(key.Data)
+	}
+	return &Signer{
+		algorithm: algorithm,
+		key:       key,
+	}, nil
+}
+
+// Sign returns the signature of the payload and the id of the key it was signed with
+func (s *Signer) Sign(data []byte) (string, []byte) {
+	if s.algorithm == AlgorithmEd25519 {
+		return s.key.Id, ed25519.Sign(s.key.Data, data)
+	}
+	return s.key.Id, hmacSHA256(s.key.Data, data)
+}
+
+// Verifier verifies payloads signatures with any of its keys, hmac secrets or ed25519 public keys
+type Verifier struct {
+	algorithm string
+	keys      *keyRing
+}
+
+func NewVerifier(algorithm string, keys []Key) (*Verifier, error) {
+	if _, err := VerifyingKeySizes(algorithm); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no verifying keys")
+	}
+	return &Verifier{
+		algorithm: algorithm,
+		keys:      newKeyRing(keys),
+	}, nil
+}
+
+func (v *Verifier) Verify(keyId string, data, signature []byte) error {
+	key, err := v.keys.get(keyId)
+	if err != nil {
+		return err
+	}
+	var valid bool
+	if v.algorithm == AlgorithmEd25519 {
+		valid = ed25519.Verify(key, data, signature)
+	} else {
+		valid = hmac.Equal(hmacSHA256(key, data), signature)
+	}
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
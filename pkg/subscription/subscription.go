@@ -0,0 +1,166 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+const (
+	defaultDelay    = time.Second
+	defaultMaxDelay = time.Minute
+)
+
+var errChannelClosed = fmt.Errorf("subscription channel closed")
+
+// Options sets the resubscription backoff of a subscription
+type Options struct {
+	// Delay is the delay before the first resubscription attempt, doubled on each consecutive failed attempt
+	Delay time.Duration
+	// MaxDelay is the maximum delay between resubscription attempts
+	MaxDelay time.Duration
+	// MaxAttempts is how many consecutive failed attempts before giving up, 0 - unlimited
+	MaxAttempts int
+	// NoResubscribe leaves a failed subscription failed
+	NoResubscribe bool
+}
+
+// backoff returns the delay before a resubscription attempt, starting from attempt 0
+func (o Options) backoff(attempt int) time.Duration {
+	delay := o.Delay
+	if delay <= 0 {
+		delay = defaultDelay
+	}
+	maxDelay := o.MaxDelay
+	if maxDelay < delay {
+		maxDelay = delay
+	}
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+func (o Options) maxDelay() time.Duration {
+	if o.MaxDelay <= 0 {
+		return defaultMaxDelay
+	}
+	return o.MaxDelay
+}
+
+// SubscribeFunc subscribes and returns the channel of received messages, subscription errors are sent to errCh
+type SubscribeFunc[T any] func(ctx context.Context, errCh chan error) (<-chan T, error)
+
+// Subscription is a supervised subscription, which is resubscribed with exponential backoff when it fails
+type Subscription[T any] struct {
+	opts      Options
+	tracker   *health.Tracker
+	log       *logger.Logger
+	subscribe SubscribeFunc[T]
+	handler   func(T)
+}
+
+func New[T any](opts Options, tracker *health.Tracker, log *logger.Logger, subscribe SubscribeFunc[T], handler func(T)) *Subscription[T] {
+	return &Subscription[T]{
+		opts:      opts,
+		tracker:   tracker,
+		log:       log,
+		subscribe: subscribe,
+		handler:   handler,
+	}
+}
+
+// Start subscribes and handles the received messages until ctx is done, an error is returned only when the first subscription fails
+func (s *Subscription[T]) Start(ctx context.Context) error {
+	s.tracker.Connecting()
+	msgCh, errCh, err := s.subscribeOnce(ctx)
+	if err != nil {
+		s.tracker.Failed(err)
+		return err
+	}
+	s.tracker.Subscribed()
+	go s.run(ctx, msgCh, errCh)
+	return nil
+}
+
+func (s *Subscription[T]) subscribeOnce(ctx context.Context) (<-chan T, chan error, error) {
+	errCh := make(chan error, 1)
+	msgCh, err := s.subscribe(ctx, errCh)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msgCh, errCh, nil
+}
+
+func (s *Subscription[T]) run(ctx context.Context, msgCh <-chan T, errCh chan error) {
+	attempt := 0
+	for {
+		started := time.Now()
+		err := s.consume(ctx, msgCh, errCh)
+		if err == nil {
+			return
+		}
+		s.tracker.Failed(err)
+		if s.opts.NoResubscribe {
+			s.log.Errorf("subscription failed, %s, resubscription is disabled", err.Error())
+			return
+		}
+		// a subscription which was alive long enough starts the backoff over
+		if time.Since(started) > s.opts.maxDelay() {
+			attempt = 0
+		}
+		for {
+			if s.opts.MaxAttempts > 0 && attempt >= s.opts.MaxAttempts {
+				s.log.Errorf("subscription failed, %s, max resubscribe attempts reached", err.Error())
+				return
+			}
+			delay := s.opts.backoff(attempt)
+			attempt++
+			s.log.Errorf("subscription failed, %s, resubscribing in %s", err.Error(), delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			s.tracker.Connecting()
+			msgCh, errCh, err = s.subscribeOnce(ctx)
+			if err != nil {
+				s.tracker.Failed(err)
+				continue
+			}
+			s.tracker.Subscribed()
+			s.log.Infof("resubscribed successfully, attempt: %d", attempt)
+			break
+		}
+	}
+}
+
+// consume handles the received messages until the subscription fails or ctx is done, which returns nil
+func (s *Subscription[T]) consume(ctx context.Context, msgCh <-chan T, errCh chan error) error {
+	for {
+		select {
+		case msg, ok := <-msgCh:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errChannelClosed
+			}
+			s.tracker.Message()
+			s.handler(msg)
+		case err := <-errCh:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first attempt",
+			opts:    Options{Delay: time.Second, MaxDelay: time.Minute},
+			attempt: 0,
+			want:    time.Second,
+		},
+		{
+			name:    "doubled",
+			opts:    Options{Delay: time.Second, MaxDelay: time.Minute},
+			attempt: 3,
+			want:    8 * time.Second,
+		},
+		{
+			name:    "capped",
+			opts:    Options{Delay: time.Second, MaxDelay: time.Minute},
+			attempt: 100,
+			want:    time.Minute,
+		},
+		{
+			name:    "default delay",
+			opts:    Options{},
+			attempt: 0,
+			want:    defaultDelay,
+		},
+		{
+			name:    "max delay lower than delay",
+			opts:    Options{Delay: 10 * time.Second, MaxDelay: time.Second},
+			attempt: 2,
+			want:    10 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.opts.backoff(tt.attempt))
+		})
+	}
+}
+
+// fakeServer hands out subscriptions whose channels are controlled by the test
+type fakeServer struct {
+	sync.Mutex
+	subscribes int
+	failures   int
+	msgCh      chan int
+	errCh      chan error
+}
+
+func (f *fakeServer) subscribe(ctx context.Context, errCh chan error) (<-chan int, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.subscribes++
+	if f.failures > 0 {
+		f.failures--
+		return nil, fmt.Errorf("server unavailable")
+	}
+	f.msgCh = make(chan int)
+	f.errCh = errCh
+	return f.msgCh, nil
+}
+
+func (f *fakeServer) current() (chan int, chan error, int) {
+	f.Lock()
+	defer f.Unlock()
+	return f.msgCh, f.errCh, f.subscribes
+}
+
+func TestSubscription_Resubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := &fakeServer{}
+	tracker := health.NewTracker("test/1")
+	received := make(chan int, 10)
+	sub := New(Options{Delay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond}, tracker, logger.NewLogger("test"),
+		server.subscribe,
+		func(msg int) {
+			received <- msg
+		})
+	require.NoError(t, sub.Start(ctx))
+	require.Equal(t, health.StateSubscribed, tracker.Status().State)
+	msgCh, errCh, _ := server.current()
+	msgCh <- 1
+	require.Equal(t, 1, <-received)
+
+	server.Lock()
+	server.failures = 2
+	server.Unlock()
+	errCh <- fmt.Errorf("stream broken")
+	require.Eventually(t, func() bool {
+		_, _, subscribes := server.current()
+		return subscribes == 4
+	}, 2*time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return tracker.Status().State == health.StateSubscribed
+	}, time.Second, 5*time.Millisecond)
+	msgCh, _, _ = server.current()
+	msgCh <- 2
+	require.Equal(t, 2, <-received)
+	status := tracker.Status()
+	require.Equal(t, 1, status.Reconnects)
+	require.EqualValues(t, 2, status.Messages)
+	require.Equal(t, "server unavailable", status.LastError)
+}
+
+func TestSubscription_StartError(t *testing.T) {
+	server := &fakeServer{failures: 1}
+	tracker := health.NewTracker("test/1")
+	sub := New(Options{}, tracker, logger.NewLogger("test"), server.subscribe, func(msg int) {})
+	require.Error(t, sub.Start(context.Background()))
+	require.Equal(t, health.StateFailed, tracker.Status().State)
+}
+
+func TestSubscription_MaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := &fakeServer{}
+	tracker := health.NewTracker("test/1")
+	sub := New(Options{Delay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 2}, tracker, logger.NewLogger("test"), server.subscribe, func(msg int) {})
+	require.NoError(t, sub.Start(ctx))
+	_, errCh, _ := server.current()
+	server.Lock()
+	server.failures = 10
+	server.Unlock()
+	errCh <- fmt.Errorf("stream broken")
+	require.Eventually(t, func() bool {
+		_, _, subscribes := server.current()
+		return subscribes == 3
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, _, subscribes := server.current()
+	require.Equal(t, 3, subscribes)
+	require.Equal(t, health.StateFailed, tracker.Status().State)
+}
+
+func TestSubscription_NoResubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := &fakeServer{}
+	tracker := health.NewTracker("test/1")
+	sub := New(Options{Delay: time.Millisecond, NoResubscribe: true}, tracker, logger.NewLogger("test"), server.subscribe, func(msg int) {})
+	require.NoError(t, sub.Start(ctx))
+	_, errCh, _ := server.current()
+	errCh <- fmt.Errorf("stream broken")
+	require.Eventually(t, func() bool {
+		return tracker.Status().State == health.StateFailed
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, _, subscribes := server.current()
+	require.Equal(t, 1, subscribes)
+}
+
+func TestSubscription_Stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server := &fakeServer{}
+	tracker := health.NewTracker("test/1")
+	sub := New(Options{Delay: time.Millisecond}, tracker, logger.NewLogger("test"), server.subscribe, func(msg int) {})
+	require.NoError(t, sub.Start(ctx))
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	_, _, subscribes := server.current()
+	require.Equal(t, 1, subscribes)
+}
@@ -0,0 +1,73 @@
+package tap
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+// Message is a readable form of a kubemq message or response, with the rules applied
+type Message struct {
+	Type      string            `json:"type"`
+	Id        string            `json:"id,omitempty"`
+	Channel   string            `json:"channel,omitempty"`
+	Metadata  string            `json:"metadata,omitempty"`
+	Body      string            `json:"body,omitempty"`
+	BodySize  int               `json:"body_size"`
+	Truncated bool              `json:"truncated,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Executed  *bool             `json:"executed,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+func newMessage(rules Rules, msgType, id, channel, metadata string, body []byte, tags map[string]string) *Message {
+	m := &Message{
+		Type:     msgType,
+		Id:       id,
+		Channel:  channel,
+		Metadata: rules.text(metadata),
+		BodySize: len(body),
+		Tags:     rules.tags(tags),
+	}
+	m.Body, m.Truncated = rules.body(body)
+	return m
+}
+
+// NewMessage returns the readable form of a message or a response, nil for a nil value
+func NewMessage(rules Rules, value interface{}) *Message {
+	switch val := value.(type) {
+	case nil:
+		return nil
+	case *kubemq.Event:
+		return newMessage(rules, "event", val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.EventStoreReceive:
+		return newMessage(rules, "event_store", val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.CommandReceive:
+		return newMessage(rules, "command", val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.QueryReceive:
+		return newMessage(rules, "query", val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.QueueMessage:
+		return newMessage(rules, "queue", val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *queues_stream.QueueMessage:
+		return newMessage(rules, "queue", val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.QueryResponse:
+		if val == nil {
+			return nil
+		}
+		m := newMessage(rules, "query_response", val.QueryId, "", val.Metadata, val.Body, val.Tags)
+		m.Executed = &val.Executed
+		m.Error = val.Error
+		return m
+	case *kubemq.CommandResponse:
+		if val == nil {
+			return nil
+		}
+		m := newMessage(rules, "command_response", val.CommandId, "", "", nil, val.Tags)
+		m.Executed = &val.Executed
+		m.Error = val.Error
+		return m
+	default:
+		return newMessage(rules, fmt.Sprintf("%T", value), "", "", "", []byte(fmt.Sprintf("%v", value)), nil)
+	}
+}
@@ -0,0 +1,87 @@
+package tap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	Redacted = "[REDACTED]"
+
+	defaultMaxBodySize = 1024
+	defaultRedactTags  = "authorization,password,token,secret"
+)
+
+// Rules sets how messages are shown, with a body truncation size and redaction of tags values and body contents
+type Rules struct {
+	// MaxBodySize truncates bodies longer than MaxBodySize bytes, 0 - no truncation
+	MaxBodySize int
+	// RedactTags are the tags keys which values are redacted, case insensitive
+	RedactTags map[string]bool
+	// RedactPattern redacts the matches of the pattern in the body and metadata
+	RedactPattern *regexp.Regexp
+}
+
+// DefaultRules truncates bodies to 1KB and redacts the common credentials tags
+func DefaultRules() Rules {
+	rules, _ := ParseRules(config.Metadata{}, "")
+	return rules
+}
+
+// ParseRules parses the rules from the <prefix>max_body_size, <prefix>redact_tags and <prefix>redact_pattern keys
+func ParseRules(meta config.Metadata, prefix string) (Rules, error) {
+	r := Rules{
+		RedactTags: map[string]bool{},
+	}
+	var err error
+	r.MaxBodySize, err = meta.ParseIntWithRange(prefix+"max_body_size", defaultMaxBodySize, 0, 1024*1024*1024)
+	if err != nil {
+		return Rules{}, fmt.Errorf("error parsing %smax_body_size value, %w", prefix, err)
+	}
+	for _, tag := range strings.Split(meta.ParseString(prefix+"redact_tags", defaultRedactTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			r.RedactTags[strings.ToLower(tag)] = true
+		}
+	}
+	if pattern := meta.ParseString(prefix+"redact_pattern", ""); pattern != "" {
+		r.RedactPattern, err = regexp.Compile(pattern)
+		if err != nil {
+			return Rules{}, fmt.Errorf("error parsing %sredact_pattern value, %w", prefix, err)
+		}
+	}
+	return r, nil
+}
+
+func (r Rules) tags(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	list := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if r.RedactTags[strings.ToLower(key)] {
+			value = Redacted
+		}
+		list[key] = value
+	}
+	return list
+}
+
+func (r Rules) text(value string) string {
+	if r.RedactPattern == nil || value == "" {
+		return value
+	}
+	return r.RedactPattern.ReplaceAllString(value, Redacted)
+}
+
+// body returns the redacted body, truncated to the max body size, and whether it was truncated
+func (r Rules) body(body []byte) (string, bool) {
+	truncated := false
+	if r.MaxBodySize > 0 && len(body) > r.MaxBodySize {
+		body = body[:r.MaxBodySize]
+		truncated = true
+	}
+	return r.text(string(body)), truncated
+}
@@ -0,0 +1,183 @@
+package tap
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// StageSource is a message as received from the source, before the binding middlewares
+	StageSource = "source"
+	// StageTarget is a message as sent to the target, after the binding middlewares
+	StageTarget = "target"
+	// StageAll selects both stages
+	StageAll = "all"
+
+	// MaxBuffer is the max number of samples kept for a subscriber
+	MaxBuffer = 10000
+	// MaxEvery is the max sampling interval of a subscriber
+	MaxEvery = 1000000
+)
+
+var defaultTap = New()
+
+// Sample is a message which passed through a binding, with its response
+type Sample struct {
+	Binding  string    `json:"binding"`
+	Stage    string    `json:"stage"`
+	Target   int       `json:"target"`
+	Time     time.Time `json:"time"`
+	Duration string    `json:"duration"`
+	Request  *Message  `json:"request"`
+	Response *Message  `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Subscriber receives every Nth sample of a binding stage, samples which do not fit in its buffer are dropped
+type Subscriber struct {
+	binding string
+	stage   string
+	every   int64
+	count   int64
+	dropped int64
+	ch      chan *Sample
+}
+
+func (s *Subscriber) Samples() <-chan *Sample {
+	return s.ch
+}
+
+// Dropped returns how many samples were dropped because the subscriber was too slow
+func (s *Subscriber) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// take returns true when the sample should be sent to the subscriber
+func (s *Subscriber) take(stage string) bool {
+	if s.stage != StageAll && s.stage != stage {
+		return false
+	}
+	return (atomic.AddInt64(&s.count, 1)-1)%s.every == 0
+}
+
+// Tap holds the bindings samples subscribers, publishing is a single atomic check when a binding has no subscribers
+type Tap struct {
+	sync.RWMutex
+	subscribers map[string]map[*Subscriber]struct{}
+	active      sync.Map
+}
+
+func New() *Tap {
+	return &Tap{
+		subscribers: map[string]map[*Subscriber]struct{}{},
+	}
+}
+
+// Subscribe subscribes to every Nth sample of a binding stage
+func Subscribe(binding, stage string, every, buffer int) *Subscriber {
+	return defaultTap.Subscribe(binding, stage, every, buffer)
+}
+
+// Unsubscribe removes a subscriber and closes its samples channel
+func Unsubscribe(s *Subscriber) {
+	defaultTap.Unsubscribe(s)
+}
+
+// Active returns true when a binding has subscribers
+func Active(binding string) bool {
+	return defaultTap.Active(binding)
+}
+
+// Publish sends a sample of a binding stage to the binding subscribers
+func Publish(binding, stage string, sample func() *Sample) {
+	defaultTap.Publish(binding, stage, sample)
+}
+
+func (t *Tap) Subscribe(binding, stage string, every, buffer int) *Subscriber {
+	if every < 1 {
+		every = 1
+	}
+	if every > MaxEvery {
+		every = MaxEvery
+	}
+	if buffer < 1 {
+		buffer = 1
+	}
+	if buffer > MaxBuffer {
+		buffer = MaxBuffer
+	}
+	s := &Subscriber{
+		binding: binding,
+		stage:   stage,
+		every:   int64(every),
+		ch:      make(chan *Sample, buffer),
+	}
+	t.Lock()
+	defer t.Unlock()
+	if t.subscribers[binding] == nil {
+		t.subscribers[binding] = map[*Subscriber]struct{}{}
+	}
+	t.subscribers[binding][s] = struct{}{}
+	t.active.Store(binding, true)
+	return s
+}
+
+func (t *Tap) Unsubscribe(s *Subscriber) {
+	t.Lock()
+	defer t.Unlock()
+	list, ok := t.subscribers[s.binding]
+	if !ok {
+		return
+	}
+	if _, ok := list[s]; !ok {
+		return
+	}
+	delete(list, s)
+	close(s.ch)
+	if len(list) == 0 {
+		delete(t.subscribers, s.binding)
+		t.active.Delete(s.binding)
+	}
+}
+
+func (t *Tap) Active(binding string) bool {
+	_, ok := t.active.Load(binding)
+	return ok
+}
+
+// Publish builds the sample only when one of the binding subscribers takes it
+func (t *Tap) Publish(binding, stage string, sample func() *Sample) {
+	if !t.Active(binding) {
+		return
+	}
+	t.RLock()
+	defer t.RUnlock()
+	var built *Sample
+	for s := range t.subscribers[binding] {
+		if !s.take(stage) {
+			continue
+		}
+		if built == nil {
+			built = sample()
+		}
+		select {
+		case s.ch <- built:
+		default:
+			atomic.AddInt64(&s.dropped, 1)
+		}
+	}
+}
+
+// ParseStage validates a stage value, an empty value selects both stages
+func ParseStage(stage string) (string, error) {
+	switch stage {
+	case "", StageAll:
+		return StageAll, nil
+	case StageSource, StageTarget:
+		return stage, nil
+	default:
+		return "", fmt.Errorf("invalid stage %s, valid values are source, target or all", stage)
+	}
+}
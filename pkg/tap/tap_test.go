@@ -0,0 +1,172 @@
+package tap
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    config.Metadata
+		want    Rules
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			meta: config.Metadata{},
+			want: Rules{
+				MaxBodySize: 1024,
+				RedactTags:  map[string]bool{"authorization": true, "password": true, "token": true, "secret": true},
+			},
+		},
+		{
+			name: "custom",
+			meta: config.Metadata{"tap_max_body_size": "0", "tap_redact_tags": " X-Api-Key ,", "tap_redact_pattern": "[0-9]+"},
+			want: Rules{
+				MaxBodySize:   0,
+				RedactTags:    map[string]bool{"x-api-key": true},
+				RedactPattern: regexp.MustCompile("[0-9]+"),
+			},
+		},
+		{
+			name:    "invalid max body size",
+			meta:    config.Metadata{"tap_max_body_size": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			meta:    config.Metadata{"tap_redact_pattern": "("},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRules(tt.meta, "tap_")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	rules := Rules{
+		MaxBodySize:   8,
+		RedactTags:    map[string]bool{"authorization": true},
+		RedactPattern: regexp.MustCompile("[0-9]{4}"),
+	}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  *Message
+	}{
+		{
+			name:  "nil",
+			value: nil,
+			want:  nil,
+		},
+		{
+			name: "event",
+			value: &kubemq.Event{
+				Id:       "1",
+				Channel:  "ch",
+				Metadata: "card 1234",
+				Body:     []byte("pin 5678 and more"),
+				Tags:     map[string]string{"Authorization": "Bearer x", "key": "value"},
+			},
+			want: &Message{
+				Type:      "event",
+				Id:        "1",
+				Channel:   "ch",
+				Metadata:  "card [REDACTED]",
+				Body:      "pin [REDACTED]",
+				BodySize:  17,
+				Truncated: true,
+				Tags:      map[string]string{"Authorization": Redacted, "key": "value"},
+			},
+		},
+		{
+			name:  "query response",
+			value: &kubemq.QueryResponse{QueryId: "2", Executed: false, Error: "failed", Body: []byte("body")},
+			want: &Message{
+				Type:     "query_response",
+				Id:       "2",
+				Body:     "body",
+				BodySize: 4,
+				Executed: new(bool),
+				Error:    "failed",
+			},
+		},
+		{
+			name:  "unknown",
+			value: "request",
+			want:  &Message{Type: "string", Body: "request", BodySize: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewMessage(rules, tt.value))
+		})
+	}
+}
+
+func TestTap_Subscribe(t *testing.T) {
+	tp := New()
+	require.False(t, tp.Active("b"))
+	built := 0
+	publish := func(stage string) {
+		tp.Publish("b", stage, func() *Sample {
+			built++
+			return &Sample{Binding: "b", Stage: stage}
+		})
+	}
+	publish(StageSource)
+	require.Equal(t, 0, built)
+
+	source := tp.Subscribe("b", StageSource, 2, 1)
+	all := tp.Subscribe("b", StageAll, 1, 10)
+	require.True(t, tp.Active("b"))
+	for i := 0; i < 4; i++ {
+		publish(StageSource)
+	}
+	publish(StageTarget)
+	require.Equal(t, 5, built)
+	require.Len(t, all.Samples(), 5)
+	// every second source sample is taken, the second one is dropped since the buffer is full
+	require.Len(t, source.Samples(), 1)
+	require.EqualValues(t, 1, source.Dropped())
+
+	tp.Unsubscribe(source)
+	tp.Unsubscribe(source)
+	_, ok := <-source.Samples()
+	require.True(t, ok)
+	_, ok = <-source.Samples()
+	require.False(t, ok)
+	require.True(t, tp.Active("b"))
+	tp.Unsubscribe(all)
+	require.False(t, tp.Active("b"))
+
+	// the buffer is capped, as it is set by the api clients
+	capped := tp.Subscribe("b", StageAll, MaxEvery+1, MaxBuffer+1)
+	require.Equal(t, MaxBuffer, cap(capped.Samples()))
+	require.EqualValues(t, MaxEvery, capped.every)
+	tp.Unsubscribe(capped)
+}
+
+func TestParseStage(t *testing.T) {
+	for value, want := range map[string]string{"": StageAll, "all": StageAll, "source": StageSource, "target": StageTarget} {
+		got, err := ParseStage(value)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := ParseStage("bad")
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// The module ABI:
+//   - memory: the exported module memory
+//   - alloc(size i32) i32: returns the address of size bytes the host writes the input to
+//   - process(address i32, size i32) i64: processes the input and returns its output address in the high 32 bits and size in the low 32 bits
+//   - dealloc(address i32, size i32): optional, called to release the input and the output after each call
+//
+// The input is a json encoded Message, the output is a json encoded Message, an array of messages, null to drop the message,
+// or an object with an error field to fail it.
+const (
+	exportMemory  = "memory"
+	exportAlloc   = "alloc"
+	exportProcess = "process"
+	exportDealloc = "dealloc"
+	// initFunction is the initialization function of modules built as reactors, modules are not run as commands
+	initFunction = "_initialize"
+)
+
+// Message is the json encoded message passed to and returned by the module process function, the body is base64 encoded
+type Message struct {
+	Channel  string            `json:"channel"`
+	Metadata string            `json:"metadata"`
+	Body     []byte            `json:"body"`
+	Tags     map[string]string `json:"tags"`
+}
+
+type output struct {
+	Message
+	Error string `json:"error"`
+}
+
+// Options are the limits of the module executions
+type Options struct {
+	// Timeout is the max run time of a single call
+	Timeout time.Duration
+	// PoolSize is the max number of module instances, which is the max number of concurrent calls
+	PoolSize int
+	// MaxMemoryPages is the max memory size of an instance in 64KB pages
+	MaxMemoryPages uint32
+}
+
+type instance struct {
+	module  api.Module
+	memory  api.Memory
+	alloc   api.Function
+	process api.Function
+	dealloc api.Function
+}
+
+// Plugin runs the process function of a webassembly module with a pool of module instances
+type Plugin struct {
+	timeout  time.Duration
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	idle     chan *instance
+	slots    chan struct{}
+}
+
+func New(ctx context.Context, file string, opts Options) (*Plugin, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading wasm module file, %w", err)
+	}
+	// calls are terminated when their context is done, so a module cannot block a call forever
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(opts.MaxMemoryPages))
+	p := &Plugin{
+		timeout: opts.Timeout,
+		runtime: runtime,
+		idle:    make(chan *instance, opts.PoolSize),
+		slots:   make(chan struct{}, opts.PoolSize),
+	}
+	// wasi is provided without file system, environment or clock access, for modules which import it
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("error loading wasi, %w", err)
+	}
+	p.compiled, err = runtime.CompileModule(ctx, data)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("error compiling wasm module, %w", err)
+	}
+	if err := checkExports(p.compiled); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	first, err := p.instantiate(ctx)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	p.idle <- first
+	p.slots <- struct{}{}
+	return p, nil
+}
+
+func checkExports(compiled wazero.CompiledModule) error {
+	if _, ok := compiled.ExportedMemories()[exportMemory]; !ok {
+		return fmt.Errorf("wasm module does not export a %s", exportMemory)
+	}
+	functions := compiled.ExportedFunctions()
+	for _, export := range []struct {
+		name     string
+		params   []api.ValueType
+		results  []api.ValueType
+		required bool
+	}{
+		{exportAlloc, []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}, true},
+		{exportProcess, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64}, true},
+		{exportDealloc, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, nil, false},
+	} {
+		def, ok := functions[export.name]
+		if !ok {
+			if export.required {
+				return fmt.Errorf("wasm module does not export a %s function", export.name)
+			}
+			continue
+		}
+		if !equalTypes(def.ParamTypes(), export.params) || !equalTypes(def.ResultTypes(), export.results) {
+			return fmt.Errorf("wasm module %s function has an invalid signature", export.name)
+		}
+	}
+	return nil
+}
+
+func equalTypes(a, b []api.ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Plugin) instantiate(ctx context.Context) (*instance, error) {
+	// instances are anonymous, so the module can be instantiated more than once
+	module, err := p.runtime.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions(initFunction))
+	if err != nil {
+		return nil, fmt.Errorf("error instantiating wasm module, %w", err)
+	}
+	return &instance{
+		module:  module,
+		memory:  module.ExportedMemory(exportMemory),
+		alloc:   module.ExportedFunction(exportAlloc),
+		process: module.ExportedFunction(exportProcess),
+		dealloc: module.ExportedFunction(exportDealloc),
+	}, nil
+}
+
+// acquire returns an idle instance, or a new one while the pool is not full, waiting for an instance otherwise
+func (p *Plugin) acquire(ctx context.Context) (*instance, error) {
+	select {
+	case inst := <-p.idle:
+		return inst, nil
+	default:
+	}
+	select {
+	case inst := <-p.idle:
+		return inst, nil
+	case p.slots <- struct{}{}:
+		inst, err := p.instantiate(ctx)
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return inst, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release returns a healthy instance to the pool, and closes a failed one, which state is unknown
+func (p *Plugin) release(ctx context.Context, inst *instance, failed bool) {
+	if !failed {
+		p.idle <- inst
+		return
+	}
+	_ = inst.module.Close(ctx)
+	<-p.slots
+}
+
+// Process calls the module process function with a message, and returns the messages it returns, none when the message is dropped
+func (p *Plugin) Process(ctx context.Context, msg Message) ([]Message, error) {
+	input, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	result, err := inst.call(callCtx, input)
+	cancel()
+	p.release(ctx, inst, err != nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutput(result)
+}
+
+func (i *instance) call(ctx context.Context, input []byte) ([]byte, error) {
+	results, err := i.alloc.Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("wasm module %s failed, %w", exportAlloc, err)
+	}
+	inAddress := uint32(results[0])
+	if !i.memory.Write(inAddress, input) {
+		return nil, fmt.Errorf("wasm module %s returned an address out of memory range", exportAlloc)
+	}
+	results, err = i.process.Call(ctx, uint64(inAddress), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("wasm module %s failed, %w", exportProcess, err)
+	}
+	outAddress, outSize := uint32(results[0]>>32), uint32(results[0])
+	view, ok := i.memory.Read(outAddress, outSize)
+	if !ok {
+		return nil, fmt.Errorf("wasm module %s returned an output out of memory range", exportProcess)
+	}
+	result := make([]byte, len(view))
+	copy(result, view)
+	if i.dealloc != nil {
+		if _, err := i.dealloc.Call(ctx, uint64(inAddress), uint64(len(input))); err != nil {
+			return nil, fmt.Errorf("wasm module %s failed, %w", exportDealloc, err)
+		}
+		if _, err := i.dealloc.Call(ctx, uint64(outAddress), uint64(outSize)); err != nil {
+			return nil, fmt.Errorf("wasm module %s failed, %w", exportDealloc, err)
+		}
+	}
+	return result, nil
+}
+
+func parseOutput(data []byte) ([]Message, error) {
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid wasm module output, %w", err)
+	}
+	switch {
+	case string(raw) == "null":
+		return nil, nil
+	case len(raw) > 0 && raw[0] == '[':
+		var list []Message
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, fmt.Errorf("invalid wasm module output, %w", err)
+		}
+		return list, nil
+	default:
+		var out output
+		if err := json.Unmarshal(raw, &out); err != nil {
+			return nil, fmt.Errorf("invalid wasm module output, %w", err)
+		}
+		if out.Error != "" {
+			return nil, fmt.Errorf("wasm module %s failed, %s", exportProcess, out.Error)
+		}
+		return []Message{out.Message}, nil
+	}
+}
+
+// Close closes the module instances and releases the compiled module
+func (p *Plugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
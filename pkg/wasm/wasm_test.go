@@ -0,0 +1,218 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// section returns a module section of an id with its vector items
+func section(id byte, items ...[]byte) []byte {
+	var content []byte
+	content = append(content, byte(len(items)))
+	for _, item := range items {
+		content = append(content, item...)
+	}
+	return append([]byte{id, byte(len(content))}, content...)
+}
+
+func sized(data []byte) []byte {
+	return append([]byte{byte(len(data))}, data...)
+}
+
+func name(s string) []byte {
+	return sized([]byte(s))
+}
+
+// testModule returns a module which exports a memory, a bump allocator, and a process function of body, with data at address 0
+func testModule(body []byte, data string) []byte {
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(0x01,
+		[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f},
+		[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e},
+	)...)
+	module = append(module, section(0x03, []byte{0x00}, []byte{0x01})...)
+	module = append(module, section(0x05, []byte{0x00, 0x01})...)
+	// the heap global starts after the data
+	module = append(module, section(0x06, []byte{0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b})...)
+	module = append(module, section(0x07,
+		append(name("memory"), 0x02, 0x00),
+		append(name("alloc"), 0x00, 0x00),
+		append(name("process"), 0x00, 0x01),
+	)...)
+	alloc := []byte{0x00, 0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b}
+	module = append(module, section(0x0a, sized(alloc), sized(append([]byte{0x00}, body...)))...)
+	if data != "" {
+		module = append(module, section(0x0b, append([]byte{0x00, 0x41, 0x00, 0x0b}, name(data)...))...)
+	}
+	return module
+}
+
+var (
+	// echoBody returns the input as the output
+	echoBody = []byte{0x20, 0x00, 0xad, 0x42, 0x20, 0x86, 0x20, 0x01, 0xad, 0x84, 0x0b}
+	// loopBody never returns
+	loopBody = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b}
+	// trapBody fails the call
+	trapBody = []byte{0x00, 0x0b}
+)
+
+// dataBody returns the data at address 0 as the output
+func dataBody(data string) []byte {
+	return []byte{0x42, byte(len(data)), 0x0b}
+}
+
+func writeModule(t *testing.T, module []byte) string {
+	file := filepath.Join(t.TempDir(), "module.wasm")
+	require.NoError(t, os.WriteFile(file, module, 0600))
+	return file
+}
+
+var testOptions = Options{
+	Timeout:        time.Second,
+	PoolSize:       2,
+	MaxMemoryPages: 16,
+}
+
+func TestPlugin_Process(t *testing.T) {
+	const (
+		dropOutput  = `null`
+		splitOutput = `[{"channel":"a","body":"MQ=="},{"channel":"b"}]`
+		errorOutput = `{"error":"bad order"}`
+	)
+	msg := Message{Channel: "orders", Metadata: "md", Body: []byte{0x00, 0xff}, Tags: map[string]string{"key": "value"}}
+	tests := []struct {
+		name    string
+		module  []byte
+		want    []Message
+		wantErr bool
+	}{
+		{
+			name:   "echo",
+			module: testModule(echoBody, ""),
+			want:   []Message{msg},
+		},
+		{
+			name:   "drop",
+			module: testModule(dataBody(dropOutput), dropOutput),
+		},
+		{
+			name:   "split",
+			module: testModule(dataBody(splitOutput), splitOutput),
+			want:   []Message{{Channel: "a", Body: []byte("1")}, {Channel: "b"}},
+		},
+		{
+			name:    "invalid - module error",
+			module:  testModule(dataBody(errorOutput), errorOutput),
+			wantErr: true,
+		},
+		{
+			name:    "invalid - trap",
+			module:  testModule(trapBody, ""),
+			wantErr: true,
+		},
+		{
+			name:    "invalid - output",
+			module:  testModule(dataBody("{"), "{"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			p, err := New(ctx, writeModule(t, tt.module), testOptions)
+			require.NoError(t, err)
+			defer func() { _ = p.Close(ctx) }()
+			for i := 0; i < 3; i++ {
+				got, err := p.Process(ctx, msg)
+				if tt.wantErr {
+					require.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPlugin_Timeout(t *testing.T) {
+	ctx := context.Background()
+	opts := testOptions
+	opts.Timeout = 100 * time.Millisecond
+	p, err := New(ctx, writeModule(t, testModule(loopBody, "")), opts)
+	require.NoError(t, err)
+	defer func() { _ = p.Close(ctx) }()
+	// more calls than instances wait for the timed out instances to be replaced
+	errs := make(chan error, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.Process(ctx, Message{Channel: "orders"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+	require.Empty(t, p.slots)
+}
+
+func TestNew(t *testing.T) {
+	noProcess := testModule(echoBody, "")
+	// renaming the process export leaves the module without it
+	for i := range noProcess {
+		if string(noProcess[i:i+7]) == "process" {
+			copy(noProcess[i:], "procesz")
+			break
+		}
+	}
+	tests := []struct {
+		name    string
+		module  []byte
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			module: testModule(echoBody, ""),
+			opts:   testOptions,
+		},
+		{
+			name:    "invalid - not a module",
+			module:  []byte("module"),
+			opts:    testOptions,
+			wantErr: true,
+		},
+		{
+			name:    "invalid - no process function",
+			module:  noProcess,
+			opts:    testOptions,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			p, err := New(ctx, writeModule(t, tt.module), tt.opts)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, p.Close(ctx))
+		})
+	}
+	_, err := New(context.Background(), filepath.Join(t.TempDir(), "missing.wasm"), testOptions)
+	require.Error(t, err)
+}
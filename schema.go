@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/sources"
+	"github.com/kubemq-io/kubemq-bridges/targets"
+)
+
+func printSchema() error {
+	data, err := config.SchemaJSON(sources.Connectors(), targets.Connectors(), append(middleware.Properties(), sources.Properties()...))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.command",
+		Aliases:     []string{"kubemq.command"},
+		Description: "kubemq commands subscriber, forwards each command to targets and replies with the target response",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "kubemq server address (gRPC interface)",
+				Default:     defaultAddress,
+			},
+			{
+				Name:        "transport",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server transport, rest connects to the address http interface or to the uri",
+				Default:     "grpc",
+				Options:     []string{"grpc", "rest"},
+			},
+			{
+				Name:        "uri",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server rest interface uri, sets the rest transport",
+			},
+			{
+				Name:        "client_id",
+				Kind:        config.PropertyKindString,
+				Description: "set client id",
+			},
+			{
+				Name:        "auth_token",
+				Kind:        config.PropertyKindString,
+				Description: "set authentication token",
+			},
+			{
+				Name:        "tls_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "tls_cert_data",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate pem data",
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set channel to subscribe",
+				Required:    true,
+			},
+			{
+				Name:        "group",
+				Kind:        config.PropertyKindString,
+				Description: "set subscriber group",
+			},
+			{
+				Name:        "sources",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many command sources to subscribe",
+				Default:     fmt.Sprintf("%d", defaultSources),
+				Min:         1,
+				Max:         1024,
+			},
+			{
+				Name:        "auto_reconnect",
+				Kind:        config.PropertyKindBool,
+				Description: "set auto reconnect on lost connection",
+				Default:     fmt.Sprintf("%t", defaultAutoReconnect),
+			},
+			{
+				Name:        "reconnect_interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set reconnection seconds",
+				Default:     "1",
+				Min:         1,
+				Max:         1000000,
+			},
+			{
+				Name:        "max_reconnect_interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set maximum seconds between resubscription attempts, the reconnection seconds are doubled on each failed attempt",
+				Default:     fmt.Sprintf("%d", defaultMaxReconnectInterval),
+				Min:         1,
+				Max:         1000000,
+			},
+			{
+				Name:        "max_reconnects",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many times to reconnect, 0 - unlimited",
+				Default:     "0",
+			},
+		},
+	}
+}
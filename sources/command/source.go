@@ -6,14 +6,20 @@ import (
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/subscription"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"github.com/kubemq-io/kubemq-go"
 )
 
 type Source struct {
+	cancel     context.CancelFunc
 	opts       options
 	clients    []*kubemq.Client
+	clientIds  []string
+	trackers   []*health.Tracker
 	log        *logger.Logger
 	targets    []middleware.Middleware
 	properties config.Metadata
@@ -39,88 +45,91 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		if s.opts.sources > 1 {
 			clientId = fmt.Sprintf("kubemq-bridges_%s_%s-%d", bindingName, clientId, i)
 		}
-		client, err := kubemq.NewClient(ctx,
-			kubemq.WithAddress(s.opts.host, s.opts.port),
-			kubemq.WithClientId(clientId),
-			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-			kubemq.WithCheckConnection(true),
-			kubemq.WithAuthToken(s.opts.authToken),
-			kubemq.WithMaxReconnects(s.opts.maxReconnects),
-			kubemq.WithAutoReconnect(s.opts.autoReconnect),
-			kubemq.WithReconnectInterval(s.opts.reconnectIntervalSeconds))
+		client, err := pool.GetClient(ctx, pool.Options{
+			Host:      s.opts.host,
+			Port:      s.opts.port,
+			Transport: s.opts.transport,
+			Uri:       s.opts.uri,
+			AuthToken: s.opts.authToken,
+			CertFile:  s.opts.certFile,
+			CertData:  s.opts.certData,
+			ClientId:  clientId,
+		})
 		if err != nil {
 			return err
 		}
 		s.clients = append(s.clients, client)
+		s.clientIds = append(s.clientIds, clientId)
+		s.trackers = append(s.trackers, health.NewTracker(fmt.Sprintf("%s/%d", s.opts.channel, i+1)))
 	}
 	return nil
 }
 
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx, s.cancel = context.WithCancel(ctx)
 	s.targets = target
 	if s.opts.sources > 1 && s.opts.group == "" {
 		s.opts.group = uuid.New().String()
 	}
-	for _, client := range s.clients {
+	for i, client := range s.clients {
 		for _, target := range target {
-			err := s.runSubscriber(ctx, s.opts.channel, s.opts.group, target, client)
+			err := s.runSubscriber(ctx, s.opts.channel, s.opts.group, target, client, s.clientIds[i], s.trackers[i])
 			if err != nil {
 				return err
 			}
 		}
 	}
-
 	return nil
 }
 
-func (s *Source) runSubscriber(ctx context.Context, channel, group string, target middleware.Middleware, client *kubemq.Client) error {
-	errCh := make(chan error, 1)
-	commandsCh, err := client.SubscribeToCommands(ctx, channel, group, errCh)
-	if err != nil {
-		return fmt.Errorf("error on subscribing to command channel, %w", err)
-	}
-	go func(ctx context.Context, commandCh <-chan *kubemq.CommandReceive, errCh chan error, target middleware.Middleware, client *kubemq.Client) {
-		s.run(ctx, commandsCh, errCh, target, client)
-	}(ctx, commandsCh, errCh, target, client)
-	return nil
+func (s *Source) runSubscriber(ctx context.Context, channel, group string, target middleware.Middleware, client *kubemq.Client, clientId string, tracker *health.Tracker) error {
+	sub := subscription.New(s.opts.subscriptionOptions(), tracker, s.log,
+		func(ctx context.Context, errCh chan error) (<-chan *kubemq.CommandReceive, error) {
+			// pooled clients are shared, so each subscription sets its own client id
+			commandCh, err := client.SubscribeToCommandsWithRequest(ctx, &kubemq.CommandsSubscription{
+				Channel:  channel,
+				Group:    group,
+				ClientId: clientId,
+			}, errCh)
+			if err != nil {
+				return nil, fmt.Errorf("error on subscribing to command channel, %w", err)
+			}
+			return commandCh, nil
+		},
+		func(command *kubemq.CommandReceive) {
+			s.handle(ctx, command, target, client, clientId)
+		})
+	return sub.Start(ctx)
 }
 
-func (s *Source) run(ctx context.Context, commandCh <-chan *kubemq.CommandReceive, errCh chan error, target middleware.Middleware, client *kubemq.Client) {
-	for {
-		select {
-		case command := <-commandCh:
-			go func(q *kubemq.CommandReceive) {
-				var cmdResponse *kubemq.Response
-				cmdResponse, err := s.processCommand(ctx, command, target, client)
-				if err != nil {
-					cmdResponse = client.NewResponse().
-						SetRequestId(command.Id).
-						SetResponseTo(command.ResponseTo).
-						SetError(err)
-				} else {
-					cmdResponse.
-						SetRequestId(command.Id).
-						SetResponseTo(command.ResponseTo)
-				}
-				err = cmdResponse.Send(ctx)
-				if err != nil {
-					s.log.Errorf("error sending command response %s", err.Error())
-				}
-			}(command)
-
-		case err := <-errCh:
-			s.log.Errorf("error received from kuebmq server, %s", err.Error())
-			return
-		case <-ctx.Done():
-			return
-
+func (s *Source) handle(ctx context.Context, command *kubemq.CommandReceive, target middleware.Middleware, client *kubemq.Client, clientId string) {
+	go func(q *kubemq.CommandReceive) {
+		var cmdResponse *kubemq.Response
+		cmdResponse, err := s.processCommand(ctx, command, target, client)
+		if err != nil {
+			cmdResponse = client.NewResponse().
+				SetRequestId(command.Id).
+				SetResponseTo(command.ResponseTo).
+				SetError(err)
+		} else {
+			cmdResponse.
+				SetRequestId(command.Id).
+				SetResponseTo(command.ResponseTo)
 		}
-	}
+		err = cmdResponse.SetClientId(clientId).Send(ctx)
+		if err != nil {
+			s.log.Errorf("error sending command response %s", err.Error())
+		}
+	}(command)
 }
 
 func (s *Source) processCommand(ctx context.Context, command *kubemq.CommandReceive, target middleware.Middleware, client *kubemq.Client) (*kubemq.Response, error) {
 	result, err := target.Do(ctx, command)
 	if err != nil {
+		// a failed query response, such as a http error status response, is returned to the sender with its body and tags
+		if val, ok := result.(*kubemq.QueryResponse); ok && val != nil {
+			return s.parseQueryResponse(val, client), nil
+		}
 		return nil, err
 	}
 	switch val := result.(type) {
@@ -154,8 +163,20 @@ func (s *Source) parseQueryResponse(query *kubemq.QueryResponse, client *kubemq.
 }
 
 func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
 	for _, client := range s.clients {
-		_ = client.Close()
+		pool.ReleaseClient(client)
+	}
+	s.clients = nil
+	s.clientIds = nil
+	for _, tracker := range s.trackers {
+		tracker.Stopped()
 	}
 	return nil
 }
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses(s.trackers)
+}
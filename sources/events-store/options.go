@@ -3,25 +3,33 @@ package events_store
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/subscription"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"time"
 )
 
 const (
-	defaultAddress       = "0.0.0.0:50000"
-	defaultAutoReconnect = true
-	defaultSources       = 1
+	defaultAddress              = "0.0.0.0:50000"
+	defaultAutoReconnect        = true
+	defaultSources              = 1
+	defaultMaxReconnectInterval = 60
 )
 
 type options struct {
 	host                     string
 	port                     int
+	transport                string
+	uri                      string
 	clientId                 string
 	authToken                string
+	certFile                 string
+	certData                 string
 	channel                  string
 	group                    string
 	autoReconnect            bool
 	reconnectIntervalSeconds time.Duration
+	maxReconnectInterval     time.Duration
 	maxReconnects            int
 	sources                  int
 }
@@ -33,8 +41,14 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
+	o.transport, o.uri, err = pool.ParseTransport(cfg, o.host, o.port)
+	if err != nil {
+		return options{}, err
+	}
 
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.certFile = cfg.ParseString("tls_cert_file", "")
+	o.certData = cfg.ParseString("tls_cert_data", "")
 	o.clientId = cfg.ParseString("client_id", uuid.New().String())
 	o.channel, err = cfg.MustParseString("channel")
 	if err != nil {
@@ -51,6 +65,20 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return o, fmt.Errorf("error parsing reconnect interval seconds value, %w", err)
 	}
 	o.reconnectIntervalSeconds = time.Duration(interval) * time.Second
+	maxInterval, err := cfg.ParseIntWithRange("max_reconnect_interval_seconds", defaultMaxReconnectInterval, 1, 1000000)
+	if err != nil {
+		return o, fmt.Errorf("error parsing max reconnect interval seconds value, %w", err)
+	}
+	o.maxReconnectInterval = time.Duration(maxInterval) * time.Second
 	o.maxReconnects = cfg.ParseInt("max_reconnects", 0)
 	return o, nil
 }
+
+func (o options) subscriptionOptions() subscription.Options {
+	return subscription.Options{
+		Delay:         o.reconnectIntervalSeconds,
+		MaxDelay:      o.maxReconnectInterval,
+		MaxAttempts:   o.maxReconnects,
+		NoResubscribe: !o.autoReconnect,
+	}
+}
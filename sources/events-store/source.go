@@ -3,11 +3,15 @@ package events_store
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+	"github.com/kubemq-io/kubemq-bridges/pkg/subscription"
 
 	"github.com/kubemq-io/kubemq-go"
 
@@ -15,8 +19,11 @@ import (
 )
 
 type Source struct {
+	cancel            context.CancelFunc
 	opts              options
 	clients           []*kubemq.Client
+	clientIds         []string
+	trackers          []*health.Tracker
 	log               *logger.Logger
 	targets           []middleware.Middleware
 	properties        config.Metadata
@@ -44,24 +51,28 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		if s.opts.sources > 1 {
 			clientId = fmt.Sprintf("kubemq-bridges_%s_%s-%d", bindingName, clientId, i)
 		}
-		client, err := kubemq.NewClient(ctx,
-			kubemq.WithAddress(s.opts.host, s.opts.port),
-			kubemq.WithClientId(clientId),
-			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-			kubemq.WithCheckConnection(true),
-			kubemq.WithAuthToken(s.opts.authToken),
-			kubemq.WithMaxReconnects(s.opts.maxReconnects),
-			kubemq.WithAutoReconnect(s.opts.autoReconnect),
-			kubemq.WithReconnectInterval(s.opts.reconnectIntervalSeconds))
+		client, err := pool.GetClient(ctx, pool.Options{
+			Host:      s.opts.host,
+			Port:      s.opts.port,
+			Transport: s.opts.transport,
+			Uri:       s.opts.uri,
+			AuthToken: s.opts.authToken,
+			CertFile:  s.opts.certFile,
+			CertData:  s.opts.certData,
+			ClientId:  clientId,
+		})
 		if err != nil {
 			return err
 		}
 		s.clients = append(s.clients, client)
+		s.clientIds = append(s.clientIds, clientId)
+		s.trackers = append(s.trackers, health.NewTracker(fmt.Sprintf("%s/%d", s.opts.channel, i+1)))
 	}
 	return nil
 }
 
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx, s.cancel = context.WithCancel(ctx)
 	s.roundRobin = roundrobin.NewRoundRobin(len(target))
 	if s.properties != nil {
 		mode, ok := s.properties["load-balancing"]
@@ -75,55 +86,75 @@ func (s *Source) Start(ctx context.Context, target []middleware.Middleware) erro
 		s.opts.group = uuid.New().String()
 	}
 
-	for _, client := range s.clients {
-		errCh := make(chan error, 1)
-		eventsCh, err := client.SubscribeToEventsStore(ctx, s.opts.channel, s.opts.group, errCh, kubemq.StartFromNewEvents())
-		if err != nil {
-			return fmt.Errorf("error on subscribing to events store channel, %w", err)
+	for i, client := range s.clients {
+		client, clientId := client, s.clientIds[i]
+		// a resubscription resumes from the event after the last received one
+		var lastSequence uint64
+		sub := subscription.New(s.opts.subscriptionOptions(), s.trackers[i], s.log,
+			func(ctx context.Context, errCh chan error) (<-chan *kubemq.EventStoreReceive, error) {
+				startFrom := kubemq.StartFromNewEvents()
+				if sequence := atomic.LoadUint64(&lastSequence); sequence > 0 {
+					startFrom = kubemq.StartFromSequence(int(sequence + 1))
+				}
+				// pooled clients are shared, so each subscription sets its own client id
+				eventsCh, err := client.SubscribeToEventsStoreWithRequest(ctx, &kubemq.EventsStoreSubscription{
+					Channel:          s.opts.channel,
+					Group:            s.opts.group,
+					ClientId:         clientId,
+					SubscriptionType: startFrom,
+				}, errCh)
+				if err != nil {
+					return nil, fmt.Errorf("error on subscribing to events store channel, %w", err)
+				}
+				return eventsCh, nil
+			},
+			func(event *kubemq.EventStoreReceive) {
+				atomic.StoreUint64(&lastSequence, event.Sequence)
+				s.handle(ctx, event)
+			})
+		if err := sub.Start(ctx); err != nil {
+			return err
 		}
-		go func(ctx context.Context, eventsCh <-chan *kubemq.EventStoreReceive, errCh chan error) {
-			s.run(ctx, eventsCh, errCh)
-		}(ctx, eventsCh, errCh)
 	}
 
 	return nil
 }
 
-func (s *Source) run(ctx context.Context, eventsCh <-chan *kubemq.EventStoreReceive, errCh chan error) {
-	for {
-		select {
-		case event := <-eventsCh:
-
-			if s.loadBalancingMode {
-				go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
-					_, err := target.Do(ctx, event)
-					if err != nil {
-						s.log.Errorf("error received from target, %w", err)
-					}
-				}(event, s.targets[s.roundRobin.Next()])
-			} else {
-				for _, target := range s.targets {
-					go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
-						_, err := target.Do(ctx, event)
-						if err != nil {
-							s.log.Errorf("error received from target, %w", err)
-						}
-					}(event, target)
-				}
+func (s *Source) handle(ctx context.Context, event *kubemq.EventStoreReceive) {
+	if s.loadBalancingMode {
+		go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
+			_, err := target.Do(ctx, event)
+			if err != nil {
+				s.log.Errorf("error received from target, %s", err.Error())
 			}
-		case err := <-errCh:
-			s.log.Errorf("error received from kuebmq server, %s", err.Error())
-			return
-		case <-ctx.Done():
-			return
-
+		}(event, s.targets[s.roundRobin.Next()])
+	} else {
+		for _, target := range s.targets {
+			go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
+				_, err := target.Do(ctx, event)
+				if err != nil {
+					s.log.Errorf("error received from target, %s", err.Error())
+				}
+			}(event, target)
 		}
 	}
 }
 
 func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
 	for _, client := range s.clients {
-		_ = client.Close()
+		pool.ReleaseClient(client)
+	}
+	s.clients = nil
+	s.clientIds = nil
+	for _, tracker := range s.trackers {
+		tracker.Stopped()
 	}
 	return nil
 }
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses(s.trackers)
+}
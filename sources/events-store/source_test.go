@@ -7,7 +7,10 @@ import (
 	"github.com/kubemq-io/kubemq-bridges/middleware"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"github.com/kubemq-io/kubemq-go"
+	pb "github.com/kubemq-io/protobuf/go"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"net"
 	"testing"
 	"time"
 )
@@ -214,3 +217,71 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+// dropServer is a kubemq server which drops the first events store subscription after sending events
+type dropServer struct {
+	pb.KubemqServer
+	subscribes chan *pb.Subscribe
+	events     int
+}
+
+func (d *dropServer) Ping(ctx context.Context, empty *pb.Empty) (*pb.PingResult, error) {
+	return &pb.PingResult{Host: "test"}, nil
+}
+
+func (d *dropServer) SubscribeToEvents(subscribe *pb.Subscribe, stream pb.Kubemq_SubscribeToEventsServer) error {
+	d.subscribes <- subscribe
+	if len(d.subscribes) > 1 {
+		<-stream.Context().Done()
+		return nil
+	}
+	for i := 1; i <= d.events; i++ {
+		if err := stream.Send(&pb.EventReceive{EventID: fmt.Sprintf("%d", i), Channel: subscribe.Channel, Sequence: uint64(i)}); err != nil {
+			return err
+		}
+	}
+	// gives the events time to be received before the stream is dropped
+	time.Sleep(100 * time.Millisecond)
+	return fmt.Errorf("stream dropped")
+}
+
+func TestSource_Resume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	drop := &dropServer{subscribes: make(chan *pb.Subscribe, 10), events: 3}
+	pb.RegisterKubemqServer(server, drop)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+	s := New()
+	err = s.Init(ctx, config.Metadata{
+		"address":                    listener.Addr().String(),
+		"client_id":                  "resume",
+		"channel":                    "events-store",
+		"reconnect_interval_seconds": "1",
+	}, config.Metadata{}, "binding", nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Stop()
+	}()
+	require.NoError(t, s.Start(ctx, []middleware.Middleware{&mockTarget{}}))
+	first := <-drop.subscribes
+	require.Equal(t, pb.Subscribe_StartNewOnly, first.EventsStoreTypeData)
+	require.Equal(t, "kubemq-bridges_binding_resume", first.ClientID)
+	select {
+	case second := <-drop.subscribes:
+		require.Equal(t, pb.Subscribe_StartAtSequence, second.EventsStoreTypeData)
+		require.EqualValues(t, 4, second.EventsStoreTypeValue)
+		require.Equal(t, first.ClientID, second.ClientID)
+	case <-ctx.Done():
+		t.Fatal("events store subscription was not resumed")
+	}
+	require.Eventually(t, func() bool {
+		return s.Health()[0].Reconnects == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Empty(t, drop.subscribes)
+}
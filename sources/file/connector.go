@@ -0,0 +1,43 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.file",
+		Description: "file reader, replays json lines files written by target.file to the targets",
+		Properties: []*config.Property{
+			{
+				Name:        "path",
+				Kind:        config.PropertyKindString,
+				Description: "set a file or a directory path to read",
+				Required:    true,
+			},
+			{
+				Name:        "watch",
+				Kind:        config.PropertyKindBool,
+				Description: "set watching the directory for new files",
+				Default:     "false",
+			},
+			{
+				Name:        "poll_interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set how often to check for new files and retry failed files",
+				Default:     fmt.Sprintf("%d", defaultPollIntervalSeconds),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+			{
+				Name:        "after_read",
+				Kind:        config.PropertyKindString,
+				Description: "set what to do with a file after it was read",
+				Default:     afterReadRename,
+				Options:     []string{afterReadKeep, afterReadRename, afterReadDelete},
+			},
+		},
+	}
+}
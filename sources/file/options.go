@@ -0,0 +1,50 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultPollIntervalSeconds = 5
+
+	afterReadKeep   = "keep"
+	afterReadRename = "rename"
+	afterReadDelete = "delete"
+
+	doneExt = ".done"
+)
+
+var afterReadActions = map[string]string{
+	"":              afterReadRename,
+	afterReadKeep:   afterReadKeep,
+	afterReadRename: afterReadRename,
+	afterReadDelete: afterReadDelete,
+}
+
+type options struct {
+	path                string
+	watch               bool
+	pollIntervalSeconds int
+	afterRead           string
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.path, err = cfg.MustParseString("path")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing path value, %w", err)
+	}
+	o.watch = cfg.ParseBool("watch", false)
+	o.pollIntervalSeconds, err = cfg.ParseIntWithRange("poll_interval_seconds", defaultPollIntervalSeconds, 1, 24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing poll interval seconds value, %w", err)
+	}
+	o.afterRead, err = cfg.ParseStringMap("after_read", afterReadActions)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing after read value, %w", err)
+	}
+	return o, nil
+}
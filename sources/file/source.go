@@ -0,0 +1,175 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/jsonl"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/sources/internal/fanout"
+)
+
+type Source struct {
+	opts       options
+	cancel     context.CancelFunc
+	tracker    *health.Tracker
+	log        *logger.Logger
+	targets    *fanout.Targets
+	properties config.Metadata
+	// offsets holds the lines read of the files which were not completed
+	offsets map[string]int
+	// completed holds the files which were read, when they are kept in place
+	completed map[string]bool
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("file")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	// the lines are replayed one at a time, so a waiting aggregation would send each line in its own batch
+	if middleware.AggregateWaits(properties) {
+		return fmt.Errorf("aggregation with aggregate wait is not supported by the file source, set aggregate wait to false")
+	}
+	s.properties = properties
+	s.tracker = health.NewTracker(s.opts.path)
+	s.offsets = map[string]int{}
+	s.completed = map[string]bool{}
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	s.targets = fanout.New(target, s.properties)
+	s.tracker.Connecting()
+	if _, err := os.Stat(s.opts.path); err != nil {
+		s.tracker.Failed(err)
+		return fmt.Errorf("error reading path %s, %w", s.opts.path, err)
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+	return nil
+}
+
+// run replays the files until all of them are read, and keeps polling for new files when watching
+func (s *Source) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.opts.pollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		pending, err := s.scan(ctx)
+		if err != nil {
+			s.tracker.Failed(err)
+			s.log.Error(err.Error())
+		} else {
+			s.tracker.Subscribed()
+			if !s.opts.watch && pending == 0 {
+				s.log.Infof("all files in %s were read", s.opts.path)
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Source) files() ([]string, error) {
+	if s.completed[s.opts.path] {
+		return nil, nil
+	}
+	info, err := os.Stat(s.opts.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{s.opts.path}, nil
+	}
+	files, err := jsonl.Files(s.opts.path)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, file := range files {
+		if !s.completed[file] {
+			list = append(list, file)
+		}
+	}
+	return list, nil
+}
+
+// scan reads the files in order, it stops on the first failed file so the messages are replayed in order, and returns how many files are left
+func (s *Source) scan(ctx context.Context) (int, error) {
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+	for i, file := range files {
+		if ctx.Err() != nil {
+			return len(files) - i, nil
+		}
+		if err := s.readFile(ctx, file); err != nil {
+			return len(files) - i, err
+		}
+	}
+	return 0, nil
+}
+
+func (s *Source) readFile(ctx context.Context, file string) error {
+	lines, err := jsonl.ReadFile(file, s.offsets[file], func(record *jsonl.Record) error {
+		msg, err := record.Message()
+		if err != nil {
+			return err
+		}
+		s.tracker.Message()
+		_, err = s.targets.Do(ctx, msg)
+		return err
+	})
+	if err != nil {
+		s.offsets[file] = lines
+		return fmt.Errorf("error replaying file %s, %w", file, err)
+	}
+	delete(s.offsets, file)
+	switch s.opts.afterRead {
+	case afterReadRename:
+		err = os.Rename(file, file+doneExt)
+	case afterReadDelete:
+		err = os.Remove(file)
+	}
+	if err != nil {
+		return fmt.Errorf("error completing file %s, %w", file, err)
+	}
+	if s.opts.afterRead == afterReadKeep || file == s.opts.path {
+		s.completed[file] = true
+	}
+	s.log.Infof("file %s was replayed, %d messages", file, lines)
+	return nil
+}
+
+func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.tracker != nil {
+		s.tracker.Stopped()
+	}
+	return nil
+}
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses([]*health.Tracker{s.tracker})
+}
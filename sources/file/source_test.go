@@ -0,0 +1,179 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/jsonl"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTarget struct {
+	sync.Mutex
+	failures int
+	received []interface{}
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	m.Lock()
+	defer m.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		return nil, fmt.Errorf("target error")
+	}
+	m.received = append(m.received, request)
+	return nil, nil
+}
+
+func (m *mockTarget) messages() []interface{} {
+	m.Lock()
+	defer m.Unlock()
+	return append([]interface{}{}, m.received...)
+}
+
+func writeFiles(t *testing.T, dir string, gzip bool, msgs ...interface{}) {
+	w, err := jsonl.NewWriter(jsonl.WriterOptions{Dir: dir, Prefix: "messages", MaxSize: 1, Gzip: gzip})
+	require.NoError(t, err)
+	for _, msg := range msgs {
+		record, err := jsonl.NewRecord(msg)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(record))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestSource_Replay(t *testing.T) {
+	msgs := []interface{}{
+		&kubemq.Event{Id: "1", Channel: "events", Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		&kubemq.QueryReceive{Id: "2", Channel: "queries", Body: []byte("query")},
+		kubemq.NewQueueMessage().SetId("3").SetChannel("queue").SetBody([]byte("queue")).SetTags(map[string]string{"key": "value"}),
+	}
+	tests := []struct {
+		name      string
+		gzip      bool
+		afterRead string
+		failures  int
+		wantFiles int
+	}{
+		{
+			name:      "rename",
+			afterRead: "rename",
+			wantFiles: 0,
+		},
+		{
+			name:      "delete with gzip",
+			gzip:      true,
+			afterRead: "delete",
+			wantFiles: 0,
+		},
+		{
+			name:      "keep with target failures",
+			afterRead: "keep",
+			failures:  2,
+			wantFiles: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.gzip, msgs...)
+			target := &mockTarget{failures: tt.failures}
+			s := New()
+			require.NoError(t, s.Init(context.Background(), config.Metadata{"path": dir, "after_read": tt.afterRead, "poll_interval_seconds": "1"}, config.Metadata{}, "test", nil))
+			require.NoError(t, s.Start(context.Background(), []middleware.Middleware{target}))
+			require.Eventually(t, func() bool {
+				return len(target.messages()) == len(msgs)
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(t, msgs, target.messages())
+			require.NoError(t, s.Stop())
+			files, err := jsonl.Files(dir)
+			require.NoError(t, err)
+			require.Len(t, files, tt.wantFiles)
+			if tt.afterRead == "rename" {
+				done, err := filepath.Glob(filepath.Join(dir, "*"+doneExt))
+				require.NoError(t, err)
+				require.Len(t, done, len(msgs))
+			}
+		})
+	}
+}
+
+func TestSource_Watch(t *testing.T) {
+	dir := t.TempDir()
+	target := &mockTarget{}
+	s := New()
+	require.NoError(t, s.Init(context.Background(), config.Metadata{"path": dir, "watch": "true", "poll_interval_seconds": "1"}, config.Metadata{}, "test", nil))
+	require.NoError(t, s.Start(context.Background(), []middleware.Middleware{target}))
+	defer func() {
+		_ = s.Stop()
+	}()
+	require.Eventually(t, func() bool {
+		return s.Health()[0].State == "subscribed"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("data"), 0644))
+	writeFiles(t, dir, false, &kubemq.Event{Id: "1", Channel: "events"})
+	require.Eventually(t, func() bool {
+		return len(target.messages()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 1, s.Health()[0].Messages)
+}
+
+func TestSource_StartMissingPath(t *testing.T) {
+	s := New()
+	require.NoError(t, s.Init(context.Background(), config.Metadata{"path": filepath.Join(t.TempDir(), "missing")}, config.Metadata{}, "test", nil))
+	require.Error(t, s.Start(context.Background(), []middleware.Middleware{&mockTarget{}}))
+	require.Equal(t, "failed", s.Health()[0].State)
+}
+
+func TestSource_InitAggregation(t *testing.T) {
+	dir := t.TempDir()
+	require.Error(t, New().Init(context.Background(), config.Metadata{"path": dir}, config.Metadata{"aggregate_format": "lines"}, "test", nil))
+	require.NoError(t, New().Init(context.Background(), config.Metadata{"path": dir}, config.Metadata{"aggregate_format": "lines", "aggregate_wait": "false"}, "test", nil))
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			cfg:     config.Metadata{"path": "./data"},
+			wantErr: false,
+		},
+		{
+			name:    "missing path",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid poll interval",
+			cfg:     config.Metadata{"path": "./data", "poll_interval_seconds": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid after read",
+			cfg:     config.Metadata{"path": "./data", "after_read": "move"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
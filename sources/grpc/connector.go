@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.grpc",
+		Description: "grpc bridge service, receives messages over unary and streaming calls and returns the targets response",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "set listening address",
+				Default:     defaultAddress,
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set message channel, overrides the channel of the received messages",
+			},
+			{
+				Name:        "token",
+				Kind:        config.PropertyKindString,
+				Description: "set bearer authentication token",
+			},
+			{
+				Name:        "cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "key_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls key file path",
+			},
+			{
+				Name:        "max_message_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set maximum received message size in bytes",
+				Default:     fmt.Sprintf("%d", defaultMaxMessageSize),
+				Min:         1,
+				Max:         1024 * 1024 * 1024,
+			},
+			{
+				Name:        "timeout_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set how long to wait for the targets response",
+				Default:     fmt.Sprintf("%d", defaultTimeoutSeconds),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultAddress        = "0.0.0.0:50052"
+	defaultMaxMessageSize = 4 * 1024 * 1024
+	defaultTimeoutSeconds = 30
+)
+
+type options struct {
+	host           string
+	port           int
+	channel        string
+	token          string
+	certFile       string
+	keyFile        string
+	maxMessageSize int
+	timeoutSeconds int
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.host, o.port, err = cfg.MustParseAddress("address", defaultAddress)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing address value, %w", err)
+	}
+	o.channel = cfg.ParseString("channel", "")
+	o.token = cfg.ParseString("token", "")
+	o.certFile = cfg.ParseString("cert_file", "")
+	o.keyFile = cfg.ParseString("key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls values, both cert_file and key_file must be set")
+	}
+	o.maxMessageSize, err = cfg.ParseIntWithRange("max_message_size", defaultMaxMessageSize, 1, 1024*1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max message size value, %w", err)
+	}
+	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, 24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	return o, nil
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/bridgespb"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/sources/internal/fanout"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	shutdownTimeout = 5 * time.Second
+)
+
+type Source struct {
+	bridgespb.UnimplementedBridgeServer
+	opts       options
+	log        *logger.Logger
+	targets    *fanout.Targets
+	properties config.Metadata
+	server     *grpc.Server
+	tracker    *health.Tracker
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("grpc")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.tracker = health.NewTracker(fmt.Sprintf("%s:%d", s.opts.host, s.opts.port))
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	s.targets = fanout.New(target, s.properties)
+	serverOpts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(s.opts.maxMessageSize),
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	}
+	if s.opts.certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.opts.certFile, s.opts.keyFile)
+		if err != nil {
+			return fmt.Errorf("error loading tls certificate, %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+	s.server = grpc.NewServer(serverOpts...)
+	bridgespb.RegisterBridgeServer(s.server, s)
+	address := fmt.Sprintf("%s:%d", s.opts.host, s.opts.port)
+	s.tracker.Connecting()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		s.tracker.Failed(err)
+		return fmt.Errorf("error on listening to %s, %w", address, err)
+	}
+	s.tracker.Subscribed()
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.tracker.Failed(err)
+			s.log.Errorf("grpc server error, %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (s *Source) authorized(ctx context.Context) error {
+	if s.opts.token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if subtle.ConstantTimeCompare([]byte(value), []byte("Bearer "+s.opts.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid authorization token")
+}
+
+func (s *Source) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorized(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Source) streamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorized(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func (s *Source) Send(ctx context.Context, msg *bridgespb.Message) (*bridgespb.Response, error) {
+	return s.send(ctx, msg)
+}
+
+func (s *Source) SendStream(stream bridgespb.Bridge_SendStreamServer) error {
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := s.send(stream.Context(), msg)
+		if err != nil {
+			resp = &bridgespb.Response{Id: msg.GetId(), Error: status.Convert(err).Message()}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// send sends a message to the targets and returns their response, a failed targets response is returned with executed false
+func (s *Source) send(ctx context.Context, msg *bridgespb.Message) (*bridgespb.Response, error) {
+	if s.opts.channel != "" {
+		msg.Channel = s.opts.channel
+	}
+	if msg.GetChannel() == "" {
+		return nil, status.Error(codes.InvalidArgument, "message channel is required")
+	}
+	if msg.GetId() == "" {
+		msg.Id = uuid.New().String()
+	}
+	s.tracker.Message()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.opts.timeoutSeconds)*time.Second)
+	defer cancel()
+	result, err := s.targets.Do(ctx, msg.ToKubemq())
+	if err != nil {
+		s.log.Errorf("error received from target, %s", err.Error())
+	}
+	return bridgespb.NewResponse(msg.GetId(), result, err), nil
+}
+
+func (s *Source) Stop() error {
+	if s.tracker != nil {
+		s.tracker.Stopped()
+	}
+	if s.server == nil {
+		return nil
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		s.server.Stop()
+	}
+	return nil
+}
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses([]*health.Tracker{s.tracker})
+}
@@ -0,0 +1,218 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/bridgespb"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type mockTarget struct {
+	setResponse interface{}
+	setError    error
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	if m.setError != nil {
+		return m.setResponse, m.setError
+	}
+	if m.setResponse != nil {
+		return m.setResponse, nil
+	}
+	// echo the request body
+	query, ok := request.(*kubemq.QueryReceive)
+	if !ok {
+		return nil, nil
+	}
+	return &kubemq.QueryResponse{Executed: true, Body: query.Body, Tags: map[string]string{"channel": query.Channel}}, nil
+}
+
+func startSource(t *testing.T, connection config.Metadata, targets ...middleware.Middleware) (*Source, bridgespb.BridgeClient) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	connection["address"] = address
+	s := New()
+	require.NoError(t, s.Init(context.Background(), connection, config.Metadata{}, "test", nil))
+	require.NoError(t, s.Start(context.Background(), targets))
+	t.Cleanup(func() {
+		_ = s.Stop()
+	})
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return s, bridgespb.NewBridgeClient(conn)
+}
+
+func TestSource_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection config.Metadata
+		targets    []middleware.Middleware
+		token      string
+		msg        *bridgespb.Message
+		want       *bridgespb.Response
+		wantCode   codes.Code
+	}{
+		{
+			name:       "query response",
+			connection: config.Metadata{},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			msg:        &bridgespb.Message{Id: "1", Channel: "orders", Body: []byte("body")},
+			want:       &bridgespb.Response{Id: "1", Executed: true, Body: []byte("body"), Tags: map[string]string{"channel": "orders"}},
+		},
+		{
+			name:       "channel override",
+			connection: config.Metadata{"channel": "override"},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			msg:        &bridgespb.Message{Id: "1", Body: []byte("body")},
+			want:       &bridgespb.Response{Id: "1", Executed: true, Body: []byte("body"), Tags: map[string]string{"channel": "override"}},
+		},
+		{
+			name:       "event",
+			connection: config.Metadata{},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			msg:        &bridgespb.Message{Id: "1", Type: bridgespb.Type_TYPE_EVENT, Channel: "events"},
+			want:       &bridgespb.Response{Id: "1", Executed: true},
+		},
+		{
+			name:       "targets error",
+			connection: config.Metadata{},
+			targets:    []middleware.Middleware{&mockTarget{setError: fmt.Errorf("target error")}, &mockTarget{setError: fmt.Errorf("other error")}},
+			msg:        &bridgespb.Message{Id: "1", Channel: "orders"},
+			want:       &bridgespb.Response{Id: "1", Error: "target error"},
+		},
+		{
+			name:       "missing channel",
+			connection: config.Metadata{},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			msg:        &bridgespb.Message{Id: "1"},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "authorized",
+			connection: config.Metadata{"token": "token"},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			token:      "token",
+			msg:        &bridgespb.Message{Id: "1", Type: bridgespb.Type_TYPE_EVENT, Channel: "events"},
+			want:       &bridgespb.Response{Id: "1", Executed: true},
+		},
+		{
+			name:       "unauthorized",
+			connection: config.Metadata{"token": "token"},
+			targets:    []middleware.Middleware{&mockTarget{}},
+			token:      "bad-token",
+			msg:        &bridgespb.Message{Id: "1", Channel: "events"},
+			wantCode:   codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, client := startSource(t, tt.connection, tt.targets...)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tt.token)
+			}
+			resp, err := client.Send(ctx, tt.msg)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.Id, resp.Id)
+			require.Equal(t, tt.want.Executed, resp.Executed)
+			require.Equal(t, tt.want.Error, resp.Error)
+			require.Equal(t, tt.want.Body, resp.Body)
+			require.Equal(t, tt.want.Tags, resp.Tags)
+			require.EqualValues(t, 1, s.Health()[0].Messages)
+		})
+	}
+}
+
+func TestSource_SendStream(t *testing.T) {
+	s, client := startSource(t, config.Metadata{}, &mockTarget{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.SendStream(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&bridgespb.Message{Channel: "orders", Body: []byte(fmt.Sprintf("%d", i))}))
+	}
+	require.NoError(t, stream.Send(&bridgespb.Message{Id: "no-channel"}))
+	require.NoError(t, stream.CloseSend())
+	for i := 0; i < 3; i++ {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.True(t, resp.Executed)
+		require.NotEmpty(t, resp.Id)
+		require.Equal(t, fmt.Sprintf("%d", i), string(resp.Body))
+	}
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "no-channel", resp.Id)
+	require.False(t, resp.Executed)
+	require.Equal(t, "message channel is required", resp.Error)
+	require.EqualValues(t, 3, s.Health()[0].Messages)
+	require.NoError(t, s.Stop())
+	require.Equal(t, "stopped", s.Health()[0].State)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			cfg:     config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid address",
+			cfg:     config.Metadata{"address": "localhost"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max message size",
+			cfg:     config.Metadata{"max_message_size": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			cfg:     config.Metadata{"timeout_seconds": "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.http",
+		Description: "http webhook listener, maps each request to a message and returns the targets response",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "set listening address",
+				Default:     defaultAddress,
+			},
+			{
+				Name:        "path",
+				Kind:        config.PropertyKindString,
+				Description: "set request path, a path ending with / matches all the paths under it",
+				Default:     defaultPath,
+			},
+			{
+				Name:        "method",
+				Kind:        config.PropertyKindString,
+				Description: "set accepted request method",
+				Default:     defaultMethod,
+				Options:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "ANY"},
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set message channel, default is the request path with / replaced by .",
+			},
+			{
+				Name:        "auth_type",
+				Kind:        config.PropertyKindString,
+				Description: "set request authentication type",
+				Default:     authNone,
+				Options:     []string{authNone, authBasic, authBearer},
+			},
+			{
+				Name:        "username",
+				Kind:        config.PropertyKindString,
+				Description: "set basic authentication username",
+			},
+			{
+				Name:        "password",
+				Kind:        config.PropertyKindString,
+				Description: "set basic authentication password",
+			},
+			{
+				Name:        "token",
+				Kind:        config.PropertyKindString,
+				Description: "set bearer authentication token",
+			},
+			{
+				Name:        "cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "key_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls key file path",
+			},
+			{
+				Name:        "max_body_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set maximum request body size in bytes",
+				Default:     fmt.Sprintf("%d", defaultMaxBodySize),
+				Min:         1,
+				Max:         1024 * 1024 * 1024,
+			},
+			{
+				Name:        "timeout_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set how long to wait for the targets response",
+				Default:     fmt.Sprintf("%d", defaultTimeoutSeconds),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultAddress        = "0.0.0.0:8081"
+	defaultPath           = "/"
+	defaultMethod         = http.MethodPost
+	defaultMaxBodySize    = 4 * 1024 * 1024
+	defaultTimeoutSeconds = 30
+
+	authNone   = "none"
+	authBasic  = "basic"
+	authBearer = "bearer"
+)
+
+var methods = map[string]string{
+	"":                 defaultMethod,
+	http.MethodGet:     http.MethodGet,
+	http.MethodPost:    http.MethodPost,
+	http.MethodPut:     http.MethodPut,
+	http.MethodPatch:   http.MethodPatch,
+	http.MethodDelete:  http.MethodDelete,
+	http.MethodOptions: http.MethodOptions,
+	"ANY":              "",
+}
+
+var authTypes = map[string]string{
+	"":         authNone,
+	authNone:   authNone,
+	authBasic:  authBasic,
+	authBearer: authBearer,
+}
+
+type options struct {
+	host           string
+	port           int
+	path           string
+	method         string
+	channel        string
+	authType       string
+	username       string
+	password       string
+	token          string
+	certFile       string
+	keyFile        string
+	maxBodySize    int
+	timeoutSeconds int
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.host, o.port, err = cfg.MustParseAddress("address", defaultAddress)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing address value, %w", err)
+	}
+	o.path = cfg.ParseString("path", defaultPath)
+	if !strings.HasPrefix(o.path, "/") {
+		o.path = "/" + o.path
+	}
+	o.method, err = config.Metadata{"method": strings.ToUpper(cfg.ParseString("method", ""))}.ParseStringMap("method", methods)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing method value, %w", err)
+	}
+	o.channel = cfg.ParseString("channel", "")
+	o.authType, err = cfg.ParseStringMap("auth_type", authTypes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing auth type value, %w", err)
+	}
+	switch o.authType {
+	case authBasic:
+		o.username, err = cfg.MustParseString("username")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing username value, %w", err)
+		}
+		o.password, err = cfg.MustParseString("password")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing password value, %w", err)
+		}
+	case authBearer:
+		o.token, err = cfg.MustParseString("token")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing token value, %w", err)
+		}
+	}
+	o.certFile = cfg.ParseString("cert_file", "")
+	o.keyFile = cfg.ParseString("key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls values, both cert_file and key_file must be set")
+	}
+	o.maxBodySize, err = cfg.ParseIntWithRange("max_body_size", defaultMaxBodySize, 1, 1024*1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max body size value, %w", err)
+	}
+	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, 24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	return o, nil
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/sources/internal/fanout"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+const (
+	TagMethod = "http_method"
+	TagPath   = "http_path"
+	TagQuery  = "http_query"
+	TagStatus = "http_status"
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// excludedHeaders are response tags which are not written as response headers, the response body is written as a whole
+var excludedHeaders = map[string]bool{
+	"content-length":    true,
+	"transfer-encoding": true,
+	"connection":        true,
+}
+
+type Source struct {
+	opts       options
+	log        *logger.Logger
+	targets    *fanout.Targets
+	properties config.Metadata
+	server     *http.Server
+	tracker    *health.Tracker
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("http")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.tracker = health.NewTracker(fmt.Sprintf("%s:%d%s", s.opts.host, s.opts.port, s.opts.path))
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	s.targets = fanout.New(target, s.properties)
+	mux := http.NewServeMux()
+	mux.Handle(s.opts.path, s.handler())
+	s.server = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", s.opts.host, s.opts.port),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	s.tracker.Connecting()
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		s.tracker.Failed(err)
+		return fmt.Errorf("error on listening to %s, %w", s.server.Addr, err)
+	}
+	s.tracker.Subscribed()
+	go func() {
+		var err error
+		if s.opts.certFile != "" {
+			err = s.server.ServeTLS(listener, s.opts.certFile, s.opts.keyFile)
+		} else {
+			err = s.server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.tracker.Failed(err)
+			s.log.Errorf("http server error, %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (s *Source) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.method != "" && r.Method != s.opts.method {
+			w.Header().Set("Allow", s.opts.method)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if !s.authorized(r) {
+			if s.opts.authType == authBasic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="kubemq-bridges"`)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(s.opts.maxBodySize)))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error reading request body, %s", err.Error()), http.StatusRequestEntityTooLarge)
+			return
+		}
+		s.tracker.Message()
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(s.opts.timeoutSeconds)*time.Second)
+		defer cancel()
+		result, err := s.targets.Do(ctx, s.parseRequest(r, body))
+		if err != nil {
+			s.log.Errorf("error received from target, %s", err.Error())
+			// a failed query response with a status, such as a target.http error response, is written as is
+			if val, ok := result.(*kubemq.QueryResponse); ok && val != nil && val.Tags[TagStatus] != "" {
+				s.writeResponse(w, val)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.writeResponse(w, result)
+	})
+}
+
+func (s *Source) authorized(r *http.Request) bool {
+	switch s.opts.authType {
+	case authBasic:
+		username, password, ok := r.BasicAuth()
+		return ok &&
+			subtle.ConstantTimeCompare([]byte(username), []byte(s.opts.username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(password), []byte(s.opts.password)) == 1
+	case authBearer:
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.token)) == 1
+	default:
+		return true
+	}
+}
+
+// parseRequest maps a http request to a query message, which all the targets can handle
+func (s *Source) parseRequest(r *http.Request, body []byte) *kubemq.QueryReceive {
+	tags := map[string]string{}
+	for key, values := range r.Header {
+		if key == "Authorization" {
+			continue
+		}
+		tags[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	tags[TagMethod] = r.Method
+	tags[TagPath] = r.URL.Path
+	if r.URL.RawQuery != "" {
+		tags[TagQuery] = r.URL.RawQuery
+	}
+	channel := s.opts.channel
+	if channel == "" {
+		channel = strings.ReplaceAll(strings.Trim(r.URL.Path, "/"), "/", ".")
+	}
+	return &kubemq.QueryReceive{
+		Id:      uuid.New().String(),
+		Channel: channel,
+		Body:    body,
+		Tags:    tags,
+	}
+}
+
+func (s *Source) writeResponse(w http.ResponseWriter, result interface{}) {
+	switch val := result.(type) {
+	case *kubemq.QueryResponse:
+		status := http.StatusOK
+		for key, value := range val.Tags {
+			if key == TagStatus {
+				if code, err := strconv.Atoi(value); err == nil && code >= 100 && code < 600 {
+					status = code
+				}
+				continue
+			}
+			if excludedHeaders[strings.ToLower(key)] {
+				continue
+			}
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(val.Body)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Source) Stop() error {
+	if s.tracker != nil {
+		s.tracker.Stopped()
+	}
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses([]*health.Tracker{s.tracker})
+}
@@ -0,0 +1,270 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/sources/internal/fanout"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTarget struct {
+	setResponse interface{}
+	setError    error
+	received    *kubemq.QueryReceive
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	m.received = request.(*kubemq.QueryReceive)
+	return m.setResponse, m.setError
+}
+
+func setupSource(t *testing.T, connection config.Metadata, targets ...middleware.Middleware) *Source {
+	s := New()
+	require.NoError(t, s.Init(context.Background(), connection, config.Metadata{}, "test", nil))
+	s.targets = fanout.New(targets, config.Metadata{})
+	return s
+}
+
+func TestSource_Handler(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection config.Metadata
+		target     *mockTarget
+		method     string
+		path       string
+		headers    map[string]string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+		wantTags   map[string]string
+		wantCh     string
+	}{
+		{
+			name:       "query response",
+			connection: config.Metadata{"path": "/hooks/", "channel": "orders"},
+			target: &mockTarget{
+				setResponse: &kubemq.QueryResponse{
+					Executed: true,
+					Body:     []byte("response"),
+					Tags:     map[string]string{"Content-Type": "text/plain"},
+				},
+			},
+			method:     http.MethodPost,
+			path:       "/hooks/orders?id=1",
+			headers:    map[string]string{"X-Request-Id": "1"},
+			body:       "request",
+			wantStatus: http.StatusOK,
+			wantBody:   "response",
+			wantHeader: map[string]string{"Content-Type": "text/plain"},
+			wantTags: map[string]string{
+				"x-request-id": "1",
+				TagMethod:      http.MethodPost,
+				TagPath:        "/hooks/orders",
+				TagQuery:       "id=1",
+			},
+			wantCh: "orders",
+		},
+		{
+			name:       "channel from path",
+			connection: config.Metadata{"path": "/hooks/"},
+			target:     &mockTarget{},
+			method:     http.MethodPost,
+			path:       "/hooks/orders",
+			body:       "request",
+			wantStatus: http.StatusOK,
+			wantCh:     "hooks.orders",
+		},
+		{
+			name:       "method not allowed",
+			connection: config.Metadata{},
+			target:     &mockTarget{},
+			method:     http.MethodGet,
+			path:       "/",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "any method",
+			connection: config.Metadata{"method": "any"},
+			target:     &mockTarget{},
+			method:     http.MethodGet,
+			path:       "/",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bearer unauthorized",
+			connection: config.Metadata{"auth_type": "bearer", "token": "token"},
+			target:     &mockTarget{},
+			method:     http.MethodPost,
+			path:       "/",
+			headers:    map[string]string{"Authorization": "Bearer bad-token"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "bearer authorized",
+			connection: config.Metadata{"auth_type": "bearer", "token": "token"},
+			target:     &mockTarget{},
+			method:     http.MethodPost,
+			path:       "/",
+			headers:    map[string]string{"Authorization": "Bearer token"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "body too large",
+			connection: config.Metadata{"max_body_size": "4"},
+			target:     &mockTarget{},
+			method:     http.MethodPost,
+			path:       "/",
+			body:       "too large body",
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "target error",
+			connection: config.Metadata{},
+			target:     &mockTarget{setError: fmt.Errorf("target error")},
+			method:     http.MethodPost,
+			path:       "/",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "target error\n",
+		},
+		{
+			name:       "failed response with status",
+			connection: config.Metadata{},
+			target: &mockTarget{
+				setResponse: &kubemq.QueryResponse{
+					Body: []byte("not found"),
+					Tags: map[string]string{TagStatus: "404", "content-length": "9"},
+				},
+				setError: fmt.Errorf("http request failed with status 404 Not Found"),
+			},
+			method:     http.MethodPost,
+			path:       "/",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := setupSource(t, tt.connection, tt.target)
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			s.handler().ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, rec.Body.String())
+			}
+			for key, value := range tt.wantHeader {
+				require.Equal(t, value, rec.Header().Get(key))
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			require.NotNil(t, tt.target.received)
+			require.Equal(t, tt.body, string(tt.target.received.Body))
+			require.NotContains(t, tt.target.received.Tags, "authorization")
+			for key, value := range tt.wantTags {
+				require.Equal(t, value, tt.target.received.Tags[key])
+			}
+			if tt.wantCh != "" {
+				require.Equal(t, tt.wantCh, tt.target.received.Channel)
+			}
+		})
+	}
+}
+
+func TestSource_StartStop(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	target := &mockTarget{}
+	s := New()
+	require.NoError(t, s.Init(context.Background(), config.Metadata{"address": address, "path": "/hook"}, config.Metadata{}, "test", nil))
+	require.NoError(t, s.Start(context.Background(), []middleware.Middleware{target}))
+	require.Equal(t, "subscribed", s.Health()[0].State)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://%s/hook", address), "text/plain", bytes.NewBufferString("data"))
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 1, s.Health()[0].Messages)
+
+	resp, err = client.Post(fmt.Sprintf("http://%s/other", address), "text/plain", bytes.NewBufferString("data"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.NoError(t, s.Stop())
+	require.Equal(t, "stopped", s.Health()[0].State)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			cfg:     config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid method",
+			cfg:     config.Metadata{"method": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid auth type",
+			cfg:     config.Metadata{"auth_type": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "basic auth without password",
+			cfg:     config.Metadata{"auth_type": "basic", "username": "user"},
+			wantErr: true,
+		},
+		{
+			name:    "bearer auth without token",
+			cfg:     config.Metadata{"auth_type": "bearer"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid address",
+			cfg:     config.Metadata{"address": "localhost"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package fanout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+)
+
+// Targets sends the requests of a source to its targets, to one target in turn in load balancing mode and to all of them otherwise
+type Targets struct {
+	targets           []middleware.Middleware
+	roundRobin        *roundrobin.RoundRobin
+	loadBalancingMode bool
+}
+
+func New(targets []middleware.Middleware, properties config.Metadata) *Targets {
+	t := &Targets{
+		targets:    targets,
+		roundRobin: roundrobin.NewRoundRobin(len(targets)),
+	}
+	if properties != nil {
+		mode, ok := properties["load-balancing"]
+		if ok && mode == "true" {
+			t.loadBalancingMode = true
+		}
+	}
+	return t
+}
+
+// Do sends the request to the targets concurrently and fails only when all the targets failed. The response of the first target
+// which succeeded is returned, or the response and error of the first target when all of them failed.
+func (t *Targets) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	if t.loadBalancingMode {
+		return t.targets[t.roundRobin.Next()].Do(ctx, request)
+	}
+	results := make([]interface{}, len(t.targets))
+	errs := make([]error, len(t.targets))
+	wg := sync.WaitGroup{}
+	wg.Add(len(t.targets))
+	for i, target := range t.targets {
+		go func(i int, target middleware.Middleware) {
+			defer wg.Done()
+			results[i], errs[i] = target.Do(ctx, request)
+		}(i, target)
+	}
+	wg.Wait()
+	for i := range t.targets {
+		if errs[i] == nil {
+			return results[i], nil
+		}
+	}
+	if len(t.targets) == 0 {
+		return nil, nil
+	}
+	return results[0], errs[0]
+}
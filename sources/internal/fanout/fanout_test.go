@@ -0,0 +1,84 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTarget struct {
+	setResponse interface{}
+	setError    error
+	calls       int32
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	atomic.AddInt32(&m.calls, 1)
+	return m.setResponse, m.setError
+}
+
+func TestTargets_Do(t *testing.T) {
+	failed := func() *mockTarget { return &mockTarget{setResponse: "failed", setError: fmt.Errorf("target error")} }
+	succeeded := func(resp string) *mockTarget { return &mockTarget{setResponse: resp} }
+	tests := []struct {
+		name     string
+		targets  []*mockTarget
+		wantResp interface{}
+		wantErr  string
+	}{
+		{
+			name:     "all succeeded",
+			targets:  []*mockTarget{succeeded("first"), succeeded("second")},
+			wantResp: "first",
+		},
+		{
+			name:     "some failed",
+			targets:  []*mockTarget{failed(), succeeded("second")},
+			wantResp: "second",
+		},
+		{
+			name:     "all failed",
+			targets:  []*mockTarget{failed(), {setError: fmt.Errorf("other error")}},
+			wantResp: "failed",
+			wantErr:  "target error",
+		},
+		{
+			name: "no targets",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var targets []middleware.Middleware
+			for _, target := range tt.targets {
+				targets = append(targets, target)
+			}
+			resp, err := New(targets, config.Metadata{}).Do(context.Background(), "request")
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantResp, resp)
+			for _, target := range tt.targets {
+				require.EqualValues(t, 1, target.calls)
+			}
+		})
+	}
+}
+
+func TestTargets_LoadBalancing(t *testing.T) {
+	first, second := &mockTarget{setResponse: "first"}, &mockTarget{setResponse: "second"}
+	targets := New([]middleware.Middleware{first, second}, config.Metadata{"load-balancing": "true"})
+	for _, want := range []string{"first", "second", "first"} {
+		resp, err := targets.Do(context.Background(), "request")
+		require.NoError(t, err)
+		require.Equal(t, want, resp)
+	}
+	require.EqualValues(t, 2, first.calls)
+	require.EqualValues(t, 1, second.calls)
+}
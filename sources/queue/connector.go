@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.queue",
+		Aliases:     []string{"kubemq.queue"},
+		Description: "kubemq queue subscriber, receives messages from a queue channel and acks them once processed",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "kubemq server address (gRPC interface)",
+				Default:     defaultAddress,
+			},
+			{
+				Name:        "transport",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server transport, rest is supported by the transaction receive mode",
+				Default:     "grpc",
+				Options:     []string{"grpc", "rest"},
+			},
+			{
+				Name:        "uri",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server rest interface uri, sets the rest transport",
+			},
+			{
+				Name:        "client_id",
+				Kind:        config.PropertyKindString,
+				Description: "set client id",
+			},
+			{
+				Name:        "auth_token",
+				Kind:        config.PropertyKindString,
+				Description: "set authentication token",
+			},
+			{
+				Name:        "tls_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "tls_cert_data",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate pem data",
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set channel to subscribe",
+				Required:    true,
+			},
+			{
+				Name:        "sources",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many concurrent sources to subscribe",
+				Default:     fmt.Sprintf("%d", defaultSources),
+				Min:         1,
+				Max:         100,
+			},
+			{
+				Name:        "batch_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many messages to pull from queue",
+				Default:     "1",
+				Min:         1,
+				Max:         1024,
+			},
+			{
+				Name:        "wait_timeout",
+				Kind:        config.PropertyKindInt,
+				Description: "set how long to wait for messages to arrive in seconds",
+				Default:     fmt.Sprintf("%d", defaultWaitTimeout),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+			{
+				Name:        "receive_mode",
+				Kind:        config.PropertyKindString,
+				Description: "set how messages are received, poll - batches over the queues stream, transaction - one message at a time with a visibility timeout",
+				Default:     receiveModePoll,
+				Options:     []string{receiveModePoll, receiveModeTransaction},
+			},
+			{
+				Name:        "visibility_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set how long a received message is invisible to other receivers in transaction mode",
+				Default:     fmt.Sprintf("%d", defaultVisibilitySeconds),
+				Min:         1,
+				Max:         12 * 60 * 60,
+			},
+			{
+				Name:        "auto_extend_visibility",
+				Kind:        config.PropertyKindBool,
+				Description: "set extending the visibility of messages which are still processed in transaction mode",
+				Default:     "true",
+			},
+			{
+				Name:        "on_failure",
+				Kind:        config.PropertyKindString,
+				Description: "set how a message which failed on all its targets is settled",
+				Default:     onFailureNack,
+				Options:     []string{onFailureNack, onFailureAck, onFailureRequeue},
+			},
+			{
+				Name:        "delivery",
+				Kind:        config.PropertyKindString,
+				Description: "set how many of the targets must succeed for a message to be delivered, at-least-one, all or quorum of the targets",
+				Default:     deliveryAtLeastOne,
+				Options:     []string{deliveryAtLeastOne, deliveryAll, deliveryQuorum},
+			},
+			{
+				Name:        "requeue_channel",
+				Kind:        config.PropertyKindString,
+				Description: "set the queue channel failed messages are requeued to in requeue on failure mode",
+			},
+		},
+	}
+}
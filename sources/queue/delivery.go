@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	deliveryAtLeastOne = "at-least-one"
+	deliveryAll        = "all"
+	deliveryQuorum     = "quorum"
+
+	deliveriesTTL           = time.Hour
+	deliveriesMaxSize       = 10000
+	deliveriesPruneInterval = time.Minute
+)
+
+var deliveryModes = map[string]string{
+	"":                 deliveryAtLeastOne,
+	deliveryAtLeastOne: deliveryAtLeastOne,
+	deliveryAll:        deliveryAll,
+	deliveryQuorum:     deliveryQuorum,
+}
+
+// delivered returns true when enough of the targets succeeded for the delivery mode
+func delivered(mode string, succeeded, targets int) bool {
+	switch mode {
+	case deliveryAll:
+		return succeeded == targets
+	case deliveryQuorum:
+		return succeeded*2 > targets
+	default:
+		return succeeded > 0
+	}
+}
+
+type delivery struct {
+	succeeded map[int]bool
+	updatedAt time.Time
+}
+
+// deliveries remembers which targets already succeeded for a nacked message, so its redelivery is sent only to the targets
+// which failed. Messages which are not redelivered to this source, such as messages received by another bridge, expire after
+// an hour, and the oldest messages are evicted when the max size is reached.
+type deliveries struct {
+	sync.Mutex
+	list    map[string]*delivery
+	ttl     time.Duration
+	maxSize int
+	pruned  time.Time
+}
+
+func newDeliveries() *deliveries {
+	return &deliveries{
+		list:    map[string]*delivery{},
+		ttl:     deliveriesTTL,
+		maxSize: deliveriesMaxSize,
+		pruned:  time.Now(),
+	}
+}
+
+// succeeded returns the targets which succeeded on the previous deliveries of a message
+func (d *deliveries) succeeded(key string) map[int]bool {
+	d.Lock()
+	defer d.Unlock()
+	list := map[int]bool{}
+	item, ok := d.list[key]
+	if !ok {
+		return list
+	}
+	if time.Since(item.updatedAt) > d.ttl {
+		delete(d.list, key)
+		return list
+	}
+	for target := range item.succeeded {
+		list[target] = true
+	}
+	return list
+}
+
+func (d *deliveries) remember(key string, succeeded map[int]bool) {
+	if key == "" || len(succeeded) == 0 {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	now := time.Now()
+	if now.Sub(d.pruned) >= deliveriesPruneInterval {
+		d.prune(now.Add(-d.ttl))
+		d.pruned = now
+	}
+	if _, ok := d.list[key]; !ok && len(d.list) >= d.maxSize {
+		d.evictOldest()
+	}
+	d.list[key] = &delivery{
+		succeeded: succeeded,
+		updatedAt: now,
+	}
+}
+
+func (d *deliveries) forget(key string) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.list, key)
+}
+
+func (d *deliveries) prune(before time.Time) {
+	for key, item := range d.list {
+		if item.updatedAt.Before(before) {
+			delete(d.list, key)
+		}
+	}
+}
+
+func (d *deliveries) evictOldest() {
+	oldestKey := ""
+	var oldest time.Time
+	for key, item := range d.list {
+		if oldestKey == "" || item.updatedAt.Before(oldest) {
+			oldestKey, oldest = key, item.updatedAt
+		}
+	}
+	delete(d.list, oldestKey)
+}
+
+func (d *deliveries) size() int {
+	d.Lock()
+	defer d.Unlock()
+	return len(d.list)
+}
@@ -3,24 +3,57 @@ package queue
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 )
 
 const (
-	defaultAddress     = "0.0.0.0:50000"
-	defaultWaitTimeout = 5
-	defaultSources     = 1
+	defaultAddress           = "0.0.0.0:50000"
+	defaultWaitTimeout       = 5
+	defaultSources           = 1
+	defaultVisibilitySeconds = 30
+
+	receiveModePoll        = "poll"
+	receiveModeTransaction = "transaction"
+
+	onFailureNack    = "nack"
+	onFailureAck     = "ack"
+	onFailureRequeue = "requeue"
 )
 
+var receiveModes = map[string]string{
+	"":                     receiveModePoll,
+	receiveModePoll:        receiveModePoll,
+	receiveModeTransaction: receiveModeTransaction,
+}
+
+var onFailureModes = map[string]string{
+	"":               onFailureNack,
+	onFailureNack:    onFailureNack,
+	onFailureAck:     onFailureAck,
+	onFailureRequeue: onFailureRequeue,
+}
+
 type options struct {
 	host        string
 	port        int
+	transport   string
+	uri         string
 	clientId    string
 	authToken   string
+	certFile    string
+	certData    string
 	channel     string
 	sources     int
 	batchSize   int
 	waitTimeout int
+
+	receiveMode          string
+	visibilitySeconds    int
+	autoExtendVisibility bool
+	onFailure            string
+	requeueChannel       string
+	delivery             string
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -30,7 +63,13 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
+	o.transport, o.uri, err = pool.ParseTransport(cfg, o.host, o.port)
+	if err != nil {
+		return options{}, err
+	}
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.certFile = cfg.ParseString("tls_cert_file", "")
+	o.certData = cfg.ParseString("tls_cert_data", "")
 
 	o.clientId = cfg.ParseString("client_id", uuid.New().String())
 
@@ -51,5 +90,29 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing wait timeout value, %w", err)
 	}
+	o.receiveMode, err = cfg.ParseStringMap("receive_mode", receiveModes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing receive mode value, %w", err)
+	}
+	if o.receiveMode == receiveModePoll && o.transport == pool.TransportRest {
+		return options{}, fmt.Errorf("error parsing receive mode value, the poll receive mode is not supported by the rest transport, use the transaction receive mode")
+	}
+	o.visibilitySeconds, err = cfg.ParseIntWithRange("visibility_seconds", defaultVisibilitySeconds, 1, 12*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing visibility seconds value, %w", err)
+	}
+	o.autoExtendVisibility = cfg.ParseBool("auto_extend_visibility", true)
+	o.onFailure, err = cfg.ParseStringMap("on_failure", onFailureModes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing on failure value, %w", err)
+	}
+	o.requeueChannel = cfg.ParseString("requeue_channel", "")
+	if o.onFailure == onFailureRequeue && o.requeueChannel == "" {
+		return options{}, fmt.Errorf("error parsing requeue channel value, requeue channel is required for the requeue on failure mode")
+	}
+	o.delivery, err = cfg.ParseStringMap("delivery", deliveryModes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing delivery value, %w", err)
+	}
 	return o, nil
 }
@@ -3,18 +3,25 @@ package queue
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemq-go/queues_stream"
+	pb "github.com/kubemq-io/protobuf/go"
 )
 
 type Source struct {
-	opts options
+	opts     options
+	cancel   context.CancelFunc
+	trackers []*health.Tracker
 
 	log               *logger.Logger
 	targets           []middleware.Middleware
@@ -23,24 +30,45 @@ type Source struct {
 	roundRobin        *roundrobin.RoundRobin
 	loadBalancingMode bool
 	bindingName       string
+	deliveries        *deliveries
+	// concurrent processes the messages of a polled batch concurrently, so they are aggregated into the same batch
+	concurrent bool
 }
 
 func New() *Source {
-	return &Source{}
+	return &Source{
+		deliveries: newDeliveries(),
+	}
+}
+
+func (s *Source) getQueuesClient(ctx context.Context) (*queues_stream.QueuesStreamClient, error) {
+	return pool.GetQueuesStreamClient(ctx, pool.Options{
+		Host:      s.opts.host,
+		Port:      s.opts.port,
+		Transport: s.opts.transport,
+		Uri:       s.opts.uri,
+		AuthToken: s.opts.authToken,
+		CertFile:  s.opts.certFile,
+		CertData:  s.opts.certData,
+		ClientId:  s.clientId(),
+	})
+}
+
+func (s *Source) getClient(ctx context.Context) (*kubemq.Client, error) {
+	return pool.GetClient(ctx, pool.Options{
+		Host:      s.opts.host,
+		Port:      s.opts.port,
+		Transport: s.opts.transport,
+		Uri:       s.opts.uri,
+		AuthToken: s.opts.authToken,
+		CertFile:  s.opts.certFile,
+		CertData:  s.opts.certData,
+		ClientId:  s.clientId(),
+	})
 }
 
-func (s *Source) getQueuesClient(ctx context.Context, id int) (*queues_stream.QueuesStreamClient, error) {
-	return queues_stream.NewQueuesStreamClient(ctx,
-		queues_stream.WithAddress(s.opts.host, s.opts.port),
-		queues_stream.WithClientId(fmt.Sprintf("kubemq-bridges_%s_%s", s.bindingName, s.opts.clientId)),
-		queues_stream.WithCheckConnection(true),
-		queues_stream.WithAutoReconnect(true),
-		queues_stream.WithAuthToken(s.opts.authToken),
-		queues_stream.WithConnectionNotificationFunc(
-			func(msg string) {
-				s.log.Infof(fmt.Sprintf("connection: %d, %s", id, msg))
-			}),
-	)
+func (s *Source) clientId() string {
+	return fmt.Sprintf("kubemq-bridges_%s_%s", s.bindingName, s.opts.clientId)
 }
 
 func (s *Source) onError(err error) {
@@ -59,6 +87,15 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		return err
 	}
 	s.bindingName = bindingName
+	if middleware.AggregateWaits(properties) {
+		if s.opts.receiveMode == receiveModeTransaction {
+			return fmt.Errorf("aggregation with aggregate wait is not supported in transaction receive mode, which receives one message at a time")
+		}
+		s.concurrent = true
+	}
+	for i := 0; i < s.opts.sources; i++ {
+		s.trackers = append(s.trackers, health.NewTracker(fmt.Sprintf("%s/%d", s.opts.channel, i+1)))
+	}
 	return nil
 }
 
@@ -71,26 +108,39 @@ func (s *Source) Start(ctx context.Context, target []middleware.Middleware) erro
 		}
 	}
 	s.targets = target
-	for i := 0; i < s.opts.sources; i++ {
-		client, err := s.getQueuesClient(ctx, i+1)
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, tracker := range s.trackers {
+		tracker.Connecting()
+		if s.opts.receiveMode == receiveModeTransaction {
+			client, err := s.getClient(ctx)
+			if err != nil {
+				tracker.Failed(err)
+				return err
+			}
+			go s.runTransactions(ctx, client, tracker)
+			continue
+		}
+		client, err := s.getQueuesClient(ctx)
 		if err != nil {
+			tracker.Failed(err)
 			return err
 		}
-		go s.run(ctx, client)
+		go s.run(ctx, client, tracker)
 	}
 	return nil
 }
 
-func (s *Source) run(ctx context.Context, client *queues_stream.QueuesStreamClient) {
+func (s *Source) run(ctx context.Context, client *queues_stream.QueuesStreamClient, tracker *health.Tracker) {
 	defer func() {
-		_ = client.Close()
+		pool.ReleaseQueuesStreamClient(client)
 	}()
 	for {
 		if s.isStopped {
 			return
 		}
-		err := s.processQueueMessage(ctx, client)
+		err := s.processQueueMessage(ctx, client, tracker)
 		if err != nil {
+			tracker.Failed(err)
 			s.log.Error(err.Error())
 			time.Sleep(time.Second)
 		}
@@ -103,7 +153,7 @@ func (s *Source) run(ctx context.Context, client *queues_stream.QueuesStreamClie
 	}
 }
 
-func (s *Source) processQueueMessage(ctx context.Context, client *queues_stream.QueuesStreamClient) error {
+func (s *Source) processQueueMessage(ctx context.Context, client *queues_stream.QueuesStreamClient, tracker *health.Tracker) error {
 	pr := queues_stream.NewPollRequest().
 		SetChannel(s.opts.channel).
 		SetMaxItems(s.opts.batchSize).
@@ -114,41 +164,173 @@ func (s *Source) processQueueMessage(ctx context.Context, client *queues_stream.
 	if err != nil {
 		return err
 	}
+	tracker.Subscribed()
 	if !pollResp.HasMessages() {
 		return nil
 	}
-	for _, message := range pollResp.Messages {
-		if s.loadBalancingMode {
-			_, err := s.targets[s.roundRobin.Next()].Do(ctx, message)
-			if err != nil {
-				if message.Policy.MaxReceiveCount < 1024 && message.Policy.MaxReceiveCount != message.Attributes.ReceiveCount {
-					return message.NAck()
-				}
-			}
+	messages := make([]polledMessage, len(pollResp.Messages))
+	for i, message := range pollResp.Messages {
+		messages[i] = message
+	}
+	s.processMessages(ctx, messages, tracker)
+	return nil
+}
 
-		} else {
-			wasExecuted := false
-			for _, target := range s.targets {
-				_, err := target.Do(ctx, message)
-				if err == nil {
-					wasExecuted = true
-				}
-			}
-			if !wasExecuted {
-				if message.Policy.MaxReceiveCount < 1024 && message.Policy.MaxReceiveCount != message.Attributes.ReceiveCount {
-					return message.NAck()
-				}
-			}
+// polledMessage is a queue message received by a poll request
+type polledMessage interface {
+	queueMessage
+	GetChannel() string
+	GetAttributes() *pb.QueueMessageAttributes
+	GetPolicy() *pb.QueueMessagePolicy
+}
+
+// processMessages processes and settles each message of a polled batch, a message which fails to settle does not stop the batch.
+// The messages are processed in order, or concurrently when they are aggregated, so the batch is not sent one message at a time.
+func (s *Source) processMessages(ctx context.Context, messages []polledMessage, tracker *health.Tracker) {
+	if !s.concurrent {
+		for _, message := range messages {
+			s.processMessage(ctx, message, tracker)
 		}
-		err = message.Ack()
-		if err != nil {
-			return err
+		return
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(len(messages))
+	for _, message := range messages {
+		go func(message polledMessage) {
+			defer wg.Done()
+			s.processMessage(ctx, message, tracker)
+		}(message)
+	}
+	wg.Wait()
+}
+
+func (s *Source) processMessage(ctx context.Context, message polledMessage, tracker *health.Tracker) {
+	tracker.Message()
+	key := messageKey(message.GetChannel(), message.GetAttributes().GetSequence())
+	executed := s.process(ctx, key, message)
+	if err := s.settle(message, key, executed, message.GetAttributes().GetReceiveCount(), message.GetPolicy().GetMaxReceiveCount()); err != nil {
+		s.log.Errorf("error settling queue message, sequence: %d, %s", message.GetAttributes().GetSequence(), err.Error())
+	}
+}
+
+func (s *Source) runTransactions(ctx context.Context, client *kubemq.Client, tracker *health.Tracker) {
+	defer func() {
+		pool.ReleaseClient(client)
+	}()
+	for {
+		if s.isStopped {
+			return
+		}
+		err := s.processTransaction(ctx, client, tracker)
+		if err != nil && ctx.Err() == nil {
+			tracker.Failed(err)
+			s.log.Error(err.Error())
+			time.Sleep(time.Second)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+
 		}
 	}
-	return nil
+}
+
+// processTransaction receives a single message with a visibility timeout, which is extended while the targets are still processing it
+func (s *Source) processTransaction(ctx context.Context, client *kubemq.Client, tracker *health.Tracker) error {
+	stream := client.NewStreamQueueMessage().
+		SetClientId(s.clientId()).
+		SetChannel(s.opts.channel)
+	defer stream.Close()
+	message, err := stream.Next(ctx, int32(s.opts.visibilitySeconds), int32(s.opts.waitTimeout))
+	if err != nil {
+		if isNoMessageError(err) {
+			tracker.Subscribed()
+			return nil
+		}
+		return err
+	}
+	tracker.Subscribed()
+	if message == nil {
+		return nil
+	}
+	tracker.Message()
+	tx := newTransaction(message)
+	if s.opts.autoExtendVisibility {
+		tx.extendVisibility(s.opts.visibilitySeconds, s.onError)
+	}
+	key := messageKey(message.Channel, message.GetAttributes().GetSequence())
+	executed := s.process(ctx, key, message)
+	tx.stop()
+	return s.settle(tx, key, executed, message.GetAttributes().GetReceiveCount(), message.GetPolicy().GetMaxReceiveCount())
+}
+
+// process sends the message to the next target in load balancing mode, or to all the targets, and returns true when the
+// message was delivered according to the delivery mode. A redelivered message is sent only to the targets which failed before.
+func (s *Source) process(ctx context.Context, key string, message interface{}) bool {
+	if s.loadBalancingMode {
+		_, err := s.targets[s.roundRobin.Next()].Do(ctx, message)
+		return err == nil
+	}
+	succeeded := s.deliveries.succeeded(key)
+	for i, target := range s.targets {
+		if succeeded[i] {
+			continue
+		}
+		_, err := target.Do(ctx, message)
+		if err == nil {
+			succeeded[i] = true
+		}
+	}
+	if delivered(s.opts.delivery, len(succeeded), len(s.targets)) {
+		s.deliveries.forget(key)
+		return true
+	}
+	s.deliveries.remember(key, succeeded)
+	return false
+}
+
+// settle acks a delivered message, a failed message is acked, requeued to the requeue channel or nacked according to the on failure mode
+func (s *Source) settle(message queueMessage, key string, executed bool, receiveCount, maxReceiveCount int32) error {
+	if executed {
+		return message.Ack()
+	}
+	switch s.opts.onFailure {
+	case onFailureNack:
+		// a nacked message is redelivered, so a message which reached its max receive count is acked
+		if maxReceiveCount < 1024 && maxReceiveCount != receiveCount {
+			return message.NAck()
+		}
+		s.deliveries.forget(key)
+		return message.Ack()
+	case onFailureRequeue:
+		s.deliveries.forget(key)
+		return message.ReQueue(s.opts.requeueChannel)
+	default:
+		s.deliveries.forget(key)
+		return message.Ack()
+	}
+}
+
+// messageKey identifies a message across its redeliveries by its channel sequence, which is kept when a message is redelivered
+func messageKey(channel string, sequence uint64) string {
+	if sequence == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", channel, sequence)
 }
 
 func (s *Source) Stop() error {
 	s.isStopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	for _, tracker := range s.trackers {
+		tracker.Stopped()
+	}
 	return nil
 }
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses(s.trackers)
+}
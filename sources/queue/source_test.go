@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"github.com/kubemq-io/kubemq-go"
+	pb "github.com/kubemq-io/protobuf/go"
 	"github.com/stretchr/testify/require"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -128,6 +133,7 @@ func TestClient_Init(t *testing.T) {
 	tests := []struct {
 		name       string
 		connection config.Metadata
+		properties config.Metadata
 		wantErr    bool
 	}{
 		{
@@ -150,6 +156,61 @@ func TestClient_Init(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "init - rest transport with poll",
+			connection: config.Metadata{
+				"address":   "localhost:50000",
+				"channel":   "some-channel",
+				"transport": "rest",
+			},
+			wantErr: true,
+		},
+		{
+			name: "init - rest transport with transaction",
+			connection: config.Metadata{
+				"address":      "localhost:50000",
+				"channel":      "some-channel",
+				"transport":    "rest",
+				"receive_mode": "transaction",
+			},
+			wantErr: false,
+		},
+		{
+			name: "init - bad receive mode",
+			connection: config.Metadata{
+				"address":      "localhost:50000",
+				"channel":      "some-channel",
+				"receive_mode": "bad",
+			},
+			wantErr: true,
+		},
+		{
+			name: "init - bad visibility seconds",
+			connection: config.Metadata{
+				"address":            "localhost:50000",
+				"channel":            "some-channel",
+				"visibility_seconds": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "init - bad delivery",
+			connection: config.Metadata{
+				"address":  "localhost:50000",
+				"channel":  "some-channel",
+				"delivery": "most",
+			},
+			wantErr: true,
+		},
+		{
+			name: "init - requeue without channel",
+			connection: config.Metadata{
+				"address":    "localhost:50000",
+				"channel":    "some-channel",
+				"on_failure": "requeue",
+			},
+			wantErr: true,
+		},
 		{
 			name: "init - bad channel",
 			connection: config.Metadata{
@@ -201,16 +262,378 @@ func TestClient_Init(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "init - aggregation",
+			connection: config.Metadata{
+				"address": "localhost:50000",
+				"channel": "some-channel",
+			},
+			properties: config.Metadata{"aggregate_format": "json"},
+			wantErr:    false,
+		},
+		{
+			name: "init - aggregation without wait in transaction mode",
+			connection: config.Metadata{
+				"address":      "localhost:50000",
+				"channel":      "some-channel",
+				"receive_mode": "transaction",
+			},
+			properties: config.Metadata{"aggregate_format": "json", "aggregate_wait": "false"},
+			wantErr:    false,
+		},
+		{
+			name: "init - aggregation with wait in transaction mode",
+			connection: config.Metadata{
+				"address":      "localhost:50000",
+				"channel":      "some-channel",
+				"receive_mode": "transaction",
+			},
+			properties: config.Metadata{"aggregate_format": "json"},
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
 			c := New()
-			if err := c.Init(ctx, tt.connection, config.Metadata{}, "", nil); (err != nil) != tt.wantErr {
+			if err := c.Init(ctx, tt.connection, tt.properties, "", nil); (err != nil) != tt.wantErr {
 				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 		})
 	}
 }
+
+type mockQueueMessage struct {
+	sync.Mutex
+	calls    []string
+	extended int
+	setError error
+}
+
+func (m *mockQueueMessage) call(name string) error {
+	m.Lock()
+	defer m.Unlock()
+	m.calls = append(m.calls, name)
+	return m.setError
+}
+
+func (m *mockQueueMessage) Ack() error                   { return m.call("ack") }
+func (m *mockQueueMessage) NAck() error                  { return m.call("nack") }
+func (m *mockQueueMessage) Reject() error                { return m.call("nack") }
+func (m *mockQueueMessage) ReQueue(channel string) error { return m.call("requeue:" + channel) }
+func (m *mockQueueMessage) Resend(channel string) error  { return m.call("requeue:" + channel) }
+func (m *mockQueueMessage) ExtendVisibility(value int32) error {
+	m.Lock()
+	defer m.Unlock()
+	m.extended++
+	return nil
+}
+
+func TestSource_Settle(t *testing.T) {
+	tests := []struct {
+		name            string
+		onFailure       string
+		executed        bool
+		receiveCount    int32
+		maxReceiveCount int32
+		want            string
+	}{
+		{
+			name:      "executed",
+			onFailure: onFailureRequeue,
+			executed:  true,
+			want:      "ack",
+		},
+		{
+			name:            "nack",
+			onFailure:       onFailureNack,
+			receiveCount:    1,
+			maxReceiveCount: 3,
+			want:            "nack",
+		},
+		{
+			name:            "nack - max receive count reached",
+			onFailure:       onFailureNack,
+			receiveCount:    3,
+			maxReceiveCount: 3,
+			want:            "ack",
+		},
+		{
+			name:      "ack",
+			onFailure: onFailureAck,
+			want:      "ack",
+		},
+		{
+			name:      "requeue",
+			onFailure: onFailureRequeue,
+			want:      "requeue:retry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			s.opts.onFailure = tt.onFailure
+			s.opts.requeueChannel = "retry"
+			message := &mockQueueMessage{}
+			require.NoError(t, s.settle(message, "", tt.executed, tt.receiveCount, tt.maxReceiveCount))
+			require.Equal(t, []string{tt.want}, message.calls)
+			tx := newTransaction(message)
+			message.calls = nil
+			require.NoError(t, s.settle(tx, "", tt.executed, tt.receiveCount, tt.maxReceiveCount))
+			require.Equal(t, []string{tt.want}, message.calls)
+		})
+	}
+}
+
+type countingTarget struct {
+	sync.Mutex
+	setError error
+	received int
+}
+
+func (c *countingTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.received++
+	return nil, c.setError
+}
+
+func TestSource_Process(t *testing.T) {
+	tests := []struct {
+		name          string
+		delivery      string
+		failed        int
+		targets       int
+		wantDelivered bool
+	}{
+		{
+			name:          "at least one",
+			delivery:      deliveryAtLeastOne,
+			failed:        2,
+			targets:       3,
+			wantDelivered: true,
+		},
+		{
+			name:          "at least one - all failed",
+			delivery:      deliveryAtLeastOne,
+			failed:        3,
+			targets:       3,
+			wantDelivered: false,
+		},
+		{
+			name:          "all",
+			delivery:      deliveryAll,
+			failed:        0,
+			targets:       3,
+			wantDelivered: true,
+		},
+		{
+			name:          "all - partial failure",
+			delivery:      deliveryAll,
+			failed:        1,
+			targets:       3,
+			wantDelivered: false,
+		},
+		{
+			name:          "quorum",
+			delivery:      deliveryQuorum,
+			failed:        1,
+			targets:       3,
+			wantDelivered: true,
+		},
+		{
+			name:          "quorum - half failed",
+			delivery:      deliveryQuorum,
+			failed:        2,
+			targets:       4,
+			wantDelivered: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			s.opts.delivery = tt.delivery
+			for i := 0; i < tt.targets; i++ {
+				target := &countingTarget{}
+				if i < tt.failed {
+					target.setError = fmt.Errorf("some-error")
+				}
+				s.targets = append(s.targets, target)
+			}
+			require.Equal(t, tt.wantDelivered, s.process(context.Background(), "q/1", kubemq.NewQueueMessage()))
+			if tt.wantDelivered {
+				require.Equal(t, 0, s.deliveries.size())
+			}
+		})
+	}
+}
+
+func TestSource_ProcessRedelivery(t *testing.T) {
+	failed := &countingTarget{setError: fmt.Errorf("some-error")}
+	succeeded := &countingTarget{}
+	s := New()
+	s.opts.delivery = deliveryAll
+	s.opts.onFailure = onFailureNack
+	s.targets = []middleware.Middleware{failed, succeeded}
+	message := &mockQueueMessage{}
+
+	executed := s.process(context.Background(), "q/1", kubemq.NewQueueMessage())
+	require.False(t, executed)
+	require.NoError(t, s.settle(message, "q/1", executed, 1, 3))
+	require.Equal(t, 1, s.deliveries.size())
+
+	// the redelivery is sent only to the failed target
+	failed.setError = nil
+	executed = s.process(context.Background(), "q/1", kubemq.NewQueueMessage())
+	require.True(t, executed)
+	require.NoError(t, s.settle(message, "q/1", executed, 2, 3))
+	require.Equal(t, 2, failed.received)
+	require.Equal(t, 1, succeeded.received)
+	require.Equal(t, []string{"nack", "ack"}, message.calls)
+	require.Equal(t, 0, s.deliveries.size())
+
+	// a message which is not redelivered is forgotten
+	failed.setError = fmt.Errorf("some-error")
+	s.opts.onFailure = onFailureAck
+	executed = s.process(context.Background(), "q/2", kubemq.NewQueueMessage())
+	require.NoError(t, s.settle(message, "q/2", executed, 1, 3))
+	require.Equal(t, 0, s.deliveries.size())
+}
+
+type mockPolledMessage struct {
+	*pb.QueueMessage
+	*mockQueueMessage
+}
+
+func newPolledMessage(sequence uint64, settleError error) *mockPolledMessage {
+	return &mockPolledMessage{
+		QueueMessage: &pb.QueueMessage{
+			Channel:    "q",
+			Attributes: &pb.QueueMessageAttributes{Sequence: sequence, ReceiveCount: 1},
+			Policy:     &pb.QueueMessagePolicy{MaxReceiveCount: 3},
+		},
+		mockQueueMessage: &mockQueueMessage{setError: settleError},
+	}
+}
+
+func TestSource_ProcessMessages(t *testing.T) {
+	failed := &countingTarget{setError: fmt.Errorf("some-error")}
+	succeeded := &countingTarget{}
+	s := New()
+	s.log = logger.NewLogger("queue")
+	s.opts.delivery = deliveryAll
+	s.opts.onFailure = onFailureNack
+	s.targets = []middleware.Middleware{failed, succeeded}
+	// the messages have no message id, they are told apart by their sequence
+	messages := []*mockPolledMessage{
+		newPolledMessage(1, fmt.Errorf("settle error")),
+		newPolledMessage(2, nil),
+		newPolledMessage(3, nil),
+	}
+	var polled []polledMessage
+	for _, message := range messages {
+		polled = append(polled, message)
+	}
+	s.processMessages(context.Background(), polled, health.NewTracker("q/1"))
+	// a message which failed to settle does not stop the batch
+	for _, message := range messages {
+		require.Equal(t, []string{"nack"}, message.calls)
+	}
+	require.Equal(t, 3, failed.received)
+	require.Equal(t, 3, succeeded.received)
+	require.Equal(t, 3, s.deliveries.size())
+}
+
+func TestSource_ProcessMessagesAggregated(t *testing.T) {
+	s := New()
+	s.log = logger.NewLogger("queue")
+	s.opts.onFailure = onFailureNack
+	s.concurrent = true
+	// the target waits for all the messages of the polled batch, as a waiting aggregation does
+	var arrived int32
+	all := make(chan struct{})
+	s.targets = []middleware.Middleware{middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		if atomic.AddInt32(&arrived, 1) == 3 {
+			close(all)
+		}
+		select {
+		case <-all:
+			return nil, nil
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("batch window ended")
+		}
+	})}
+	messages := []*mockPolledMessage{
+		newPolledMessage(1, nil),
+		newPolledMessage(2, nil),
+		newPolledMessage(3, nil),
+	}
+	var polled []polledMessage
+	for _, message := range messages {
+		polled = append(polled, message)
+	}
+	s.processMessages(context.Background(), polled, health.NewTracker("q/1"))
+	for _, message := range messages {
+		require.Equal(t, []string{"ack"}, message.calls)
+	}
+}
+
+func TestDeliveries_Prune(t *testing.T) {
+	d := newDeliveries()
+	d.remember("", map[int]bool{0: true})
+	d.remember("q/1", map[int]bool{})
+	require.Equal(t, 0, d.size())
+	d.remember("q/1", map[int]bool{0: true})
+	d.remember("q/3", map[int]bool{0: true})
+	d.list["q/1"].updatedAt = time.Now().Add(-2 * deliveriesTTL)
+	d.list["q/3"].updatedAt = time.Now().Add(-2 * deliveriesTTL)
+	// an expired message is not used, even before it is pruned
+	require.Empty(t, d.succeeded("q/1"))
+	require.Equal(t, 1, d.size())
+	// expired messages are pruned on the next prune interval, below the max size
+	d.pruned = time.Now().Add(-deliveriesPruneInterval)
+	d.remember("q/2", map[int]bool{1: true})
+	require.Equal(t, 1, d.size())
+	require.Equal(t, map[int]bool{1: true}, d.succeeded("q/2"))
+}
+
+func TestDeliveries_MaxSize(t *testing.T) {
+	d := newDeliveries()
+	d.maxSize = 2
+	d.remember("q/1", map[int]bool{0: true})
+	d.remember("q/2", map[int]bool{0: true})
+	d.list["q/1"].updatedAt = time.Now().Add(-time.Minute)
+	d.remember("q/2", map[int]bool{1: true})
+	require.Equal(t, 2, d.size())
+	// the oldest message is evicted for a new message
+	d.remember("q/3", map[int]bool{0: true})
+	require.Equal(t, 2, d.size())
+	require.Empty(t, d.succeeded("q/1"))
+	require.Equal(t, map[int]bool{1: true}, d.succeeded("q/2"))
+	require.Equal(t, map[int]bool{0: true}, d.succeeded("q/3"))
+}
+
+func TestTransaction_ExtendVisibility(t *testing.T) {
+	message := &mockQueueMessage{}
+	tx := newTransaction(message)
+	tx.extendVisibility(1, func(err error) {
+		require.NoError(t, err)
+	})
+	time.Sleep(1200 * time.Millisecond)
+	tx.stop()
+	tx.stop()
+	message.Lock()
+	extended := message.extended
+	message.Unlock()
+	require.Equal(t, 2, extended)
+	time.Sleep(600 * time.Millisecond)
+	require.Equal(t, extended, message.extended)
+}
+
+func TestIsNoMessageError(t *testing.T) {
+	require.True(t, isNoMessageError(fmt.Errorf("Error 138: no new message in queue, wait time expired")))
+	require.True(t, isNoMessageError(fmt.Errorf("no new queue message available")))
+	require.False(t, isNoMessageError(fmt.Errorf("connection refused")))
+}
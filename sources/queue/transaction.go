@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// queueMessage is a received queue message, which is settled once its targets are done
+type queueMessage interface {
+	Ack() error
+	NAck() error
+	ReQueue(channel string) error
+}
+
+// streamMessage is the settlement api of a kubemq.QueueMessage received with a visibility timeout
+type streamMessage interface {
+	Ack() error
+	Reject() error
+	Resend(channel string) error
+	ExtendVisibility(value int32) error
+}
+
+// transaction settles a message received with a visibility timeout, and keeps it invisible while its targets are still
+// processing it. The message stream handles a single request at a time, so the settlement and the extensions are serialized.
+type transaction struct {
+	sync.Mutex
+	message  streamMessage
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func newTransaction(message streamMessage) *transaction {
+	return &transaction{
+		message: message,
+		done:    make(chan struct{}),
+	}
+}
+
+func (t *transaction) Ack() error {
+	t.Lock()
+	defer t.Unlock()
+	return t.message.Ack()
+}
+
+func (t *transaction) NAck() error {
+	t.Lock()
+	defer t.Unlock()
+	return t.message.Reject()
+}
+
+func (t *transaction) ReQueue(channel string) error {
+	t.Lock()
+	defer t.Unlock()
+	return t.message.Resend(channel)
+}
+
+// extendVisibility resets the message visibility every half of the visibility timeout until the transaction is stopped
+func (t *transaction) extendVisibility(visibilitySeconds int, onError func(err error)) {
+	interval := time.Duration(visibilitySeconds) * time.Second / 2
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.Lock()
+				err := t.message.ExtendVisibility(int32(visibilitySeconds))
+				t.Unlock()
+				if err != nil {
+					onError(err)
+				}
+			case <-t.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop ends the visibility extension, and waits for an extension in progress
+func (t *transaction) stop() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+	})
+	t.wg.Wait()
+}
+
+// isNoMessageError returns true for the error which is returned when no message arrived within the wait timeout
+func isNoMessageError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no new message") || strings.Contains(msg, "no new queue message")
+}
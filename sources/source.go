@@ -5,18 +5,24 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/sources/command"
 	"github.com/kubemq-io/kubemq-bridges/sources/events"
 	events_store "github.com/kubemq-io/kubemq-bridges/sources/events-store"
+	"github.com/kubemq-io/kubemq-bridges/sources/file"
+	"github.com/kubemq-io/kubemq-bridges/sources/grpc"
+	"github.com/kubemq-io/kubemq-bridges/sources/http"
 	"github.com/kubemq-io/kubemq-bridges/sources/query"
 	"github.com/kubemq-io/kubemq-bridges/sources/queue"
+	"github.com/kubemq-io/kubemq-bridges/sources/timer"
 )
 
 type Source interface {
 	Init(ctx context.Context, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) error
 	Start(ctx context.Context, target []middleware.Middleware) error
 	Stop() error
+	Health() []*health.Status
 }
 
 func Init(ctx context.Context, kind string, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) (Source, error) {
@@ -51,8 +57,58 @@ func Init(ctx context.Context, kind string, connection config.Metadata, properti
 			return nil, err
 		}
 		return source, nil
+	case "source.http":
+		source := http.New()
+		if err := source.Init(ctx, connection, properties, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "source.file":
+		source := file.New()
+		if err := source.Init(ctx, connection, properties, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "source.timer":
+		source := timer.New()
+		if err := source.Init(ctx, connection, properties, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "source.grpc":
+		source := grpc.New()
+		if err := source.Init(ctx, connection, properties, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
 	default:
 		return nil, fmt.Errorf("invalid kind %s for source", kind)
 	}
 
 }
+
+func Connectors() []*config.Connector {
+	return []*config.Connector{
+		command.Connector(),
+		query.Connector(),
+		events.Connector(),
+		events_store.Connector(),
+		queue.Connector(),
+		http.Connector(),
+		file.Connector(),
+		timer.Connector(),
+		grpc.Connector(),
+	}
+}
+
+// Properties returns the binding properties keys supported by the sources
+func Properties() []*config.Property {
+	return []*config.Property{
+		{
+			Name:        "load-balancing",
+			Kind:        config.PropertyKindBool,
+			Description: "send each message to one of the targets in round robin instead of to all of them",
+			Default:     "false",
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package timer
+
+import (
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "source.timer",
+		Description: "timer, sends a templated message on a cron schedule or a fixed interval",
+		Properties: []*config.Property{
+			{
+				Name:        "schedule",
+				Kind:        config.PropertyKindString,
+				Description: "set cron schedule expression, standard 5 fields or a descriptor such as @hourly",
+			},
+			{
+				Name:        "interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set fixed interval in seconds, when schedule is not set",
+				Min:         0,
+				Max:         365 * 24 * 60 * 60,
+			},
+			{
+				Name:        "kind",
+				Kind:        config.PropertyKindString,
+				Description: "set the type of the sent message",
+				Default:     kindEvent,
+				Options:     []string{kindEvent, kindEventStore, kindCommand, kindQuery, kindQueue},
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set message channel",
+				Default:     defaultChannel,
+			},
+			{
+				Name:        "metadata",
+				Kind:        config.PropertyKindString,
+				Description: "set message metadata template",
+			},
+			{
+				Name:        "body",
+				Kind:        config.PropertyKindString,
+				Description: "set message body template",
+			},
+			{
+				Name:        "tags",
+				Kind:        config.PropertyKindString,
+				Description: "set message tags templates as a json object",
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package timer
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	defaultChannel = "timer"
+
+	kindEvent      = "event"
+	kindEventStore = "event_store"
+	kindCommand    = "command"
+	kindQuery      = "query"
+	kindQueue      = "queue"
+)
+
+var kinds = map[string]string{
+	"":             kindEvent,
+	kindEvent:      kindEvent,
+	kindEventStore: kindEventStore,
+	kindCommand:    kindCommand,
+	kindQuery:      kindQuery,
+	kindQueue:      kindQueue,
+}
+
+type options struct {
+	schedule cron.Schedule
+	spec     string
+	kind     string
+	channel  string
+	metadata *template.Template
+	body     *template.Template
+	tags     map[string]*template.Template
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.spec = cfg.ParseString("schedule", "")
+	interval, err := cfg.ParseIntWithRange("interval_seconds", 0, 0, 365*24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing interval seconds value, %w", err)
+	}
+	switch {
+	case o.spec != "" && interval > 0:
+		return options{}, fmt.Errorf("error parsing schedule value, only one of schedule and interval_seconds can be set")
+	case o.spec != "":
+		o.schedule, err = cron.ParseStandard(o.spec)
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing schedule value, %w", err)
+		}
+	case interval > 0:
+		o.spec = fmt.Sprintf("@every %ds", interval)
+		o.schedule = cron.Every(time.Duration(interval) * time.Second)
+	default:
+		return options{}, fmt.Errorf("error parsing schedule value, one of schedule and interval_seconds must be set")
+	}
+	o.kind, err = cfg.ParseStringMap("kind", kinds)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing kind value, %w", err)
+	}
+	o.channel = cfg.ParseString("channel", defaultChannel)
+	o.metadata, err = parseTemplate("metadata", cfg.ParseString("metadata", ""))
+	if err != nil {
+		return options{}, err
+	}
+	o.body, err = parseTemplate("body", cfg.ParseString("body", ""))
+	if err != nil {
+		return options{}, err
+	}
+	tags, err := cfg.MustParseJsonMap("tags")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing tags value, %w", err)
+	}
+	o.tags = map[string]*template.Template{}
+	for key, value := range tags {
+		o.tags[key], err = parseTemplate(fmt.Sprintf("tag %s", key), value)
+		if err != nil {
+			return options{}, err
+		}
+	}
+	return o, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s template, %w", name, err)
+	}
+	return tmpl, nil
+}
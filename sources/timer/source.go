@@ -0,0 +1,174 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/health"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/sources/internal/fanout"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+// tick is the data of a timer message, the metadata, body and tags templates are executed with it
+type tick struct {
+	Id       string
+	Time     time.Time
+	Count    int64
+	Binding  string
+	Hostname string
+	Channel  string
+}
+
+type Source struct {
+	opts        options
+	cancel      context.CancelFunc
+	tracker     *health.Tracker
+	log         *logger.Logger
+	targets     *fanout.Targets
+	properties  config.Metadata
+	bindingName string
+	hostname    string
+	count       int64
+	wg          sync.WaitGroup
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, bindingName string, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("timer")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.bindingName = bindingName
+	s.hostname, _ = os.Hostname()
+	s.tracker = health.NewTracker(fmt.Sprintf("%s/%s", s.opts.channel, s.opts.spec))
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	s.targets = fanout.New(target, s.properties)
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.tracker.Subscribed()
+	s.wg.Add(1)
+	go s.run(ctx)
+	return nil
+}
+
+// run sends a message on each schedule time, a time which is reached while the previous message is still sent is skipped
+func (s *Source) run(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		now := time.Now()
+		next := s.opts.schedule.Next(now)
+		if next.IsZero() {
+			s.log.Infof("schedule %s has no next time", s.opts.spec)
+			return
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case fired := <-timer.C:
+			s.fire(ctx, fired)
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (s *Source) fire(ctx context.Context, fired time.Time) {
+	s.count++
+	msg, err := s.message(tick{
+		Id:       uuid.New().String(),
+		Time:     fired.UTC(),
+		Count:    s.count,
+		Binding:  s.bindingName,
+		Hostname: s.hostname,
+		Channel:  s.opts.channel,
+	})
+	if err != nil {
+		s.tracker.Failed(err)
+		s.log.Errorf("error creating timer message, %s", err.Error())
+		return
+	}
+	s.tracker.Message()
+	if _, err := s.targets.Do(ctx, msg); err != nil {
+		s.log.Errorf("error received from target, %s", err.Error())
+	}
+}
+
+// message returns the timer message in the configured kind
+func (s *Source) message(t tick) (interface{}, error) {
+	metadata, err := execute(s.opts.metadata, t)
+	if err != nil {
+		return nil, err
+	}
+	body, err := execute(s.opts.body, t)
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]string{}
+	for key, tmpl := range s.opts.tags {
+		value, err := execute(tmpl, t)
+		if err != nil {
+			return nil, err
+		}
+		tags[key] = value
+	}
+	switch s.opts.kind {
+	case kindEventStore:
+		return &kubemq.EventStoreReceive{Id: t.Id, Timestamp: t.Time, Channel: t.Channel, Metadata: metadata, Body: []byte(body), Tags: tags}, nil
+	case kindCommand:
+		return &kubemq.CommandReceive{Id: t.Id, Channel: t.Channel, Metadata: metadata, Body: []byte(body), Tags: tags}, nil
+	case kindQuery:
+		return &kubemq.QueryReceive{Id: t.Id, Channel: t.Channel, Metadata: metadata, Body: []byte(body), Tags: tags}, nil
+	case kindQueue:
+		return kubemq.NewQueueMessage().
+			SetId(t.Id).
+			SetChannel(t.Channel).
+			SetMetadata(metadata).
+			SetBody([]byte(body)).
+			SetTags(tags), nil
+	default:
+		return &kubemq.Event{Id: t.Id, Channel: t.Channel, Metadata: metadata, Body: []byte(body), Tags: tags}, nil
+	}
+}
+
+func execute(tmpl *template.Template, t tick) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, t); err != nil {
+		return "", fmt.Errorf("error executing %s template, %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	if s.tracker != nil {
+		s.tracker.Stopped()
+	}
+	return nil
+}
+
+func (s *Source) Health() []*health.Status {
+	return health.Statuses([]*health.Tracker{s.tracker})
+}
@@ -0,0 +1,177 @@
+package timer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTarget struct {
+	sync.Mutex
+	setError error
+	received []interface{}
+}
+
+func (m *mockTarget) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.received = append(m.received, request)
+	return nil, m.setError
+}
+
+func (m *mockTarget) count() int {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.received)
+}
+
+func TestSource_Message(t *testing.T) {
+	now := time.Unix(1600000000, 0).UTC()
+	tests := []struct {
+		name       string
+		connection config.Metadata
+		want       interface{}
+	}{
+		{
+			name: "event",
+			connection: config.Metadata{
+				"interval_seconds": "1",
+				"metadata":         "heartbeat",
+				"body":             `{"count":{{.Count}},"binding":"{{.Binding}}"}`,
+				"tags":             `{"channel":"{{.Channel}}","id":"{{.Id}}"}`,
+			},
+			want: &kubemq.Event{
+				Id:       "id",
+				Channel:  "timer",
+				Metadata: "heartbeat",
+				Body:     []byte(`{"count":3,"binding":"binding"}`),
+				Tags:     map[string]string{"channel": "timer", "id": "id"},
+			},
+		},
+		{
+			name:       "event store",
+			connection: config.Metadata{"interval_seconds": "1", "kind": "event_store", "body": "{{.Time.Unix}}"},
+			want: &kubemq.EventStoreReceive{
+				Id:        "id",
+				Timestamp: now,
+				Channel:   "timer",
+				Body:      []byte("1600000000"),
+				Tags:      map[string]string{},
+			},
+		},
+		{
+			name:       "command",
+			connection: config.Metadata{"schedule": "@hourly", "kind": "command", "channel": "commands"},
+			want:       &kubemq.CommandReceive{Id: "id", Channel: "commands", Body: []byte{}, Tags: map[string]string{}},
+		},
+		{
+			name:       "query",
+			connection: config.Metadata{"schedule": "* * * * *", "kind": "query"},
+			want:       &kubemq.QueryReceive{Id: "id", Channel: "timer", Body: []byte{}, Tags: map[string]string{}},
+		},
+		{
+			name:       "queue",
+			connection: config.Metadata{"interval_seconds": "1", "kind": "queue", "body": "{{.Hostname}}"},
+			want:       kubemq.NewQueueMessage().SetId("id").SetChannel("timer").SetBody([]byte("host")).SetTags(map[string]string{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			require.NoError(t, s.Init(context.Background(), tt.connection, config.Metadata{}, "binding", nil))
+			msg, err := s.message(tick{Id: "id", Time: now, Count: 3, Binding: "binding", Hostname: "host", Channel: s.opts.channel})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, msg)
+		})
+	}
+}
+
+func TestSource_StartStop(t *testing.T) {
+	failed := &mockTarget{setError: fmt.Errorf("target error")}
+	succeeded := &mockTarget{}
+	s := New()
+	require.NoError(t, s.Init(context.Background(), config.Metadata{"interval_seconds": "1"}, config.Metadata{}, "binding", nil))
+	require.NoError(t, s.Start(context.Background(), []middleware.Middleware{failed, succeeded}))
+	require.Equal(t, "subscribed", s.Health()[0].State)
+	require.Eventually(t, func() bool {
+		return succeeded.count() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Stop())
+	require.Equal(t, "stopped", s.Health()[0].State)
+	require.Equal(t, failed.count(), succeeded.count())
+	count := succeeded.count()
+	time.Sleep(1500 * time.Millisecond)
+	require.Equal(t, count, succeeded.count())
+	require.EqualValues(t, count, s.Health()[0].Messages)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "schedule",
+			cfg:     config.Metadata{"schedule": "CRON_TZ=UTC */5 * * * *"},
+			wantErr: false,
+		},
+		{
+			name:    "interval",
+			cfg:     config.Metadata{"interval_seconds": "10"},
+			wantErr: false,
+		},
+		{
+			name:    "no schedule",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "schedule and interval",
+			cfg:     config.Metadata{"schedule": "@hourly", "interval_seconds": "10"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid schedule",
+			cfg:     config.Metadata{"schedule": "* * *"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid kind",
+			cfg:     config.Metadata{"interval_seconds": "10", "kind": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid body template",
+			cfg:     config.Metadata{"interval_seconds": "10", "body": "{{.Count"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid tags",
+			cfg:     config.Metadata{"interval_seconds": "10", "tags": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag template",
+			cfg:     config.Metadata{"interval_seconds": "10", "tags": `{"key":"{{.Count"}`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
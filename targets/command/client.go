@@ -7,6 +7,7 @@ import (
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-go"
 )
 
@@ -30,13 +31,16 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId)),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = pool.GetClient(ctx, pool.Options{
+		Host:      c.opts.host,
+		Port:      c.opts.port,
+		Transport: c.opts.transport,
+		Uri:       c.opts.uri,
+		AuthToken: c.opts.authToken,
+		CertFile:  c.opts.certFile,
+		CertData:  c.opts.certData,
+		ClientId:  fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId),
+	})
 	if err != nil {
 		return err
 	}
@@ -45,7 +49,7 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 
 func (c *Client) Stop() error {
 	if c.client != nil {
-		return c.client.Close()
+		pool.ReleaseClient(c.client)
 	}
 	return nil
 }
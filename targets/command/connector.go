@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"math"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.command",
+		Aliases:     []string{"kubemq.command"},
+		Description: "kubemq command sender, returns the command response to the source",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "kubemq server address (gRPC interface)",
+				Default:     defaultHost,
+			},
+			{
+				Name:        "transport",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server transport, rest connects to the address http interface or to the uri",
+				Default:     "grpc",
+				Options:     []string{"grpc", "rest"},
+			},
+			{
+				Name:        "uri",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server rest interface uri, sets the rest transport",
+			},
+			{
+				Name:        "client_id",
+				Kind:        config.PropertyKindString,
+				Description: "set client id",
+			},
+			{
+				Name:        "auth_token",
+				Kind:        config.PropertyKindString,
+				Description: "set authentication token",
+			},
+			{
+				Name:        "tls_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "tls_cert_data",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate pem data",
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set channel to send request, takes precedence over default_channel",
+			},
+			{
+				Name:        "default_channel",
+				Kind:        config.PropertyKindString,
+				Description: "set default channel to send request, when channel is not set",
+			},
+			{
+				Name:        "timeout_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "sets command request timeout in seconds",
+				Default:     fmt.Sprintf("%d", defaultTimeoutSeconds),
+				Min:         1,
+				Max:         math.MaxInt32,
+			},
+		},
+	}
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"math"
 )
@@ -15,8 +16,12 @@ const (
 type options struct {
 	host           string
 	port           int
+	transport      string
+	uri            string
 	clientId       string
 	authToken      string
+	certFile       string
+	certData       string
 	channel        string
 	defaultChannel string
 	timeoutSeconds int
@@ -29,7 +34,13 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
+	o.transport, o.uri, err = pool.ParseTransport(cfg, o.host, o.port)
+	if err != nil {
+		return options{}, err
+	}
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.certFile = cfg.ParseString("tls_cert_file", "")
+	o.certData = cfg.ParseString("tls_cert_data", "")
 	o.clientId = cfg.ParseString("client_id", uuid.New().String())
 	o.channel = cfg.ParseString("channel", "")
 	if o.channel != "" {
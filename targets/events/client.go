@@ -7,6 +7,7 @@ import (
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-go"
 )
 
@@ -16,6 +17,7 @@ const (
 )
 
 type Client struct {
+	cancel context.CancelFunc
 	log    *logger.Logger
 	opts   options
 	client *kubemq.Client
@@ -36,24 +38,31 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId)),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = pool.GetClient(ctx, pool.Options{
+		Host:      c.opts.host,
+		Port:      c.opts.port,
+		Transport: c.opts.transport,
+		Uri:       c.opts.uri,
+		AuthToken: c.opts.authToken,
+		CertFile:  c.opts.certFile,
+		CertData:  c.opts.certData,
+		ClientId:  fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId),
+	})
 	if err != nil {
 		return err
 	}
 	c.sendCh = make(chan *kubemq.Event, 1)
+	ctx, c.cancel = context.WithCancel(ctx)
 	go c.runStreamProcessing(ctx)
 	return nil
 }
 
 func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
 	if c.client != nil {
-		return c.client.Close()
+		pool.ReleaseClient(c.client)
 	}
 	return nil
 }
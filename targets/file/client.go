@@ -0,0 +1,62 @@
+package file
+
+import (
+	"context"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/jsonl"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+type Client struct {
+	log    *logger.Logger
+	opts   options
+	writer *jsonl.Writer
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("file")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	c.writer, err = jsonl.NewWriter(jsonl.WriterOptions{
+		Dir:     c.opts.dir,
+		Prefix:  c.opts.prefix,
+		MaxSize: int64(c.opts.maxSizeMB) * 1024 * 1024,
+		MaxAge:  time.Duration(c.opts.rotateIntervalSeconds) * time.Second,
+		Gzip:    c.opts.gzip,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) Stop() error {
+	if c.writer != nil {
+		return c.writer.Close()
+	}
+	return nil
+}
+
+// Do appends the message as a json line to the current file
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	record, err := jsonl.NewRecord(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writer.Write(record); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,77 @@
+package file
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/jsonl"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do(t *testing.T) {
+	dir := t.TempDir()
+	c := New()
+	require.NoError(t, c.Init(context.Background(), config.Metadata{"dir": dir, "gzip": "true"}, "test", nil))
+	event := &kubemq.Event{Id: "1", Channel: "events", Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}}
+	result, err := c.Do(context.Background(), event)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	_, err = c.Do(context.Background(), "bad request")
+	require.Error(t, err)
+	require.NoError(t, c.Stop())
+
+	files, err := jsonl.Files(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	var records []*jsonl.Record
+	_, err = jsonl.ReadFile(files[0], 0, func(record *jsonl.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	msg, err := records[0].Message()
+	require.NoError(t, err)
+	require.Equal(t, event, msg)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			cfg:     config.Metadata{"dir": "./data"},
+			wantErr: false,
+		},
+		{
+			name:    "missing dir",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max size",
+			cfg:     config.Metadata{"dir": "./data", "max_size_mb": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid rotate interval",
+			cfg:     config.Metadata{"dir": "./data", "rotate_interval_seconds": "-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.file",
+		Description: "file writer, appends the messages as json lines to rotating files",
+		Properties: []*config.Property{
+			{
+				Name:        "dir",
+				Kind:        config.PropertyKindString,
+				Description: "set files directory",
+				Required:    true,
+			},
+			{
+				Name:        "file_prefix",
+				Kind:        config.PropertyKindString,
+				Description: "set file names prefix",
+				Default:     defaultPrefix,
+			},
+			{
+				Name:        "max_size_mb",
+				Kind:        config.PropertyKindInt,
+				Description: "set file size in mb to rotate a file at, 0 disables size rotation",
+				Default:     fmt.Sprintf("%d", defaultMaxSizeMB),
+				Min:         0,
+				Max:         1024 * 1024,
+			},
+			{
+				Name:        "rotate_interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set how long a file is written before it is rotated, 0 disables time rotation",
+				Default:     fmt.Sprintf("%d", defaultRotateIntervalSeconds),
+				Min:         0,
+				Max:         365 * 24 * 60 * 60,
+			},
+			{
+				Name:        "gzip",
+				Kind:        config.PropertyKindBool,
+				Description: "set gzip compression of the files",
+				Default:     "false",
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultPrefix                = "messages"
+	defaultMaxSizeMB             = 100
+	defaultRotateIntervalSeconds = 3600
+)
+
+type options struct {
+	dir                   string
+	prefix                string
+	maxSizeMB             int
+	rotateIntervalSeconds int
+	gzip                  bool
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.dir, err = cfg.MustParseString("dir")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing dir value, %w", err)
+	}
+	o.prefix = cfg.ParseString("file_prefix", defaultPrefix)
+	o.maxSizeMB, err = cfg.ParseIntWithRange("max_size_mb", defaultMaxSizeMB, 0, 1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max size mb value, %w", err)
+	}
+	o.rotateIntervalSeconds, err = cfg.ParseIntWithRange("rotate_interval_seconds", defaultRotateIntervalSeconds, 0, 365*24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing rotate interval seconds value, %w", err)
+	}
+	o.gzip = cfg.ParseBool("gzip", false)
+	return o, nil
+}
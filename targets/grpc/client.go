@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/bridgespb"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+type Client struct {
+	log    *logger.Logger
+	opts   options
+	conn   *grpc.ClientConn
+	client bridgespb.BridgeClient
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("grpc")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	creds := insecure.NewCredentials()
+	if c.opts.tls {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	c.conn, err = grpc.DialContext(ctx, c.opts.address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(c.opts.maxMessageSize), grpc.MaxCallRecvMsgSize(c.opts.maxMessageSize)))
+	if err != nil {
+		return fmt.Errorf("error connecting to %s, %w", c.opts.address, err)
+	}
+	c.client = bridgespb.NewBridgeClient(c.conn)
+	return nil
+}
+
+func (c *Client) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.opts.insecureSkipVerify}
+	if c.opts.caCertFile != "" {
+		data, err := os.ReadFile(c.opts.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading ca certificate, %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("error loading ca certificate, no valid certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.opts.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.opts.certFile, c.opts.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading tls certificate, %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (c *Client) Stop() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// Do sends the message to the bridge service, the response is returned as a query response,
+// a response which was not executed is returned together with an error
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	msg, err := bridgespb.NewMessage(request)
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.channel != "" {
+		msg.Channel = c.opts.channel
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.opts.timeoutSeconds)*time.Second)
+	defer cancel()
+	if c.opts.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.opts.token)
+	}
+	resp, err := c.client.Send(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	response := resp.ToQueryResponse()
+	if !response.Executed {
+		if response.Error == "" {
+			response.Error = "message was not executed"
+		}
+		return response, fmt.Errorf("%s", response.Error)
+	}
+	return response, nil
+}
@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/bridgespb"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockServer struct {
+	bridgespb.UnimplementedBridgeServer
+	received      *bridgespb.Message
+	authorization string
+}
+
+func (m *mockServer) Send(ctx context.Context, msg *bridgespb.Message) (*bridgespb.Response, error) {
+	m.received = msg
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 {
+		m.authorization = values[0]
+	}
+	if msg.Channel == "fail" {
+		return &bridgespb.Response{Id: msg.Id, Error: "service error", Body: []byte("failed")}, nil
+	}
+	return &bridgespb.Response{Id: msg.Id, Executed: true, Body: msg.Body, Tags: map[string]string{"key": "value"}}, nil
+}
+
+func startServer(t *testing.T) (string, *mockServer) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	mock := &mockServer{}
+	bridgespb.RegisterBridgeServer(server, mock)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), mock
+}
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name        string
+		connection  config.Metadata
+		request     interface{}
+		wantErr     bool
+		wantMessage *bridgespb.Message
+		wantAuth    string
+	}{
+		{
+			name:        "event",
+			connection:  config.Metadata{},
+			request:     &kubemq.Event{Id: "1", Channel: "events", Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+			wantMessage: &bridgespb.Message{Id: "1", Type: bridgespb.Type_TYPE_EVENT, Channel: "events", Metadata: "metadata", Body: []byte("body"), Tags: map[string]string{"key": "value"}},
+		},
+		{
+			name:        "query with channel override and token",
+			connection:  config.Metadata{"channel": "override", "token": "token"},
+			request:     &kubemq.QueryReceive{Id: "2", Channel: "queries", Body: []byte("body")},
+			wantMessage: &bridgespb.Message{Id: "2", Type: bridgespb.Type_TYPE_QUERY, Channel: "override", Body: []byte("body")},
+			wantAuth:    "Bearer token",
+		},
+		{
+			name:        "not executed",
+			connection:  config.Metadata{},
+			request:     &kubemq.CommandReceive{Id: "3", Channel: "fail"},
+			wantErr:     true,
+			wantMessage: &bridgespb.Message{Id: "3", Type: bridgespb.Type_TYPE_COMMAND, Channel: "fail"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, mock := startServer(t)
+			tt.connection["address"] = address
+			c := New()
+			require.NoError(t, c.Init(context.Background(), tt.connection, "test", nil))
+			defer func() {
+				_ = c.Stop()
+			}()
+			result, err := c.Do(context.Background(), tt.request)
+			response, ok := result.(*kubemq.QueryResponse)
+			require.True(t, ok)
+			if tt.wantErr {
+				require.EqualError(t, err, "service error")
+				require.False(t, response.Executed)
+				require.Equal(t, "failed", string(response.Body))
+			} else {
+				require.NoError(t, err)
+				require.True(t, response.Executed)
+				require.Equal(t, tt.wantMessage.Body, response.Body)
+				require.Equal(t, map[string]string{"key": "value"}, response.Tags)
+			}
+			require.Equal(t, tt.wantMessage.Id, mock.received.Id)
+			require.Equal(t, tt.wantMessage.Type, mock.received.Type)
+			require.Equal(t, tt.wantMessage.Channel, mock.received.Channel)
+			require.Equal(t, tt.wantMessage.Metadata, mock.received.Metadata)
+			require.Equal(t, tt.wantMessage.Body, mock.received.Body)
+			require.Equal(t, tt.wantMessage.Tags, mock.received.Tags)
+			require.Equal(t, tt.wantAuth, mock.authorization)
+		})
+	}
+}
+
+func TestClient_Do_UnknownRequest(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Init(context.Background(), config.Metadata{"address": "localhost:50052"}, "test", nil))
+	_, err := c.Do(context.Background(), "request")
+	require.Error(t, err)
+	require.NoError(t, c.Stop())
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantTLS bool
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  config.Metadata{"address": "localhost:50052"},
+		},
+		{
+			name:    "tls from ca cert",
+			cfg:     config.Metadata{"address": "localhost:50052", "ca_cert_file": "./ca.pem"},
+			wantTLS: true,
+		},
+		{
+			name:    "missing address",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"address": "localhost:50052", "cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			cfg:     config.Metadata{"address": "localhost:50052", "timeout_seconds": "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTLS, o.tls)
+		})
+	}
+}
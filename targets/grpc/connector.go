@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.grpc",
+		Description: "grpc bridge service client, sends the messages to a source.grpc compatible service",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "set bridge service address",
+				Required:    true,
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set message channel, overrides the channel of the sent messages",
+			},
+			{
+				Name:        "token",
+				Kind:        config.PropertyKindString,
+				Description: "set bearer authentication token",
+			},
+			{
+				Name:        "tls",
+				Kind:        config.PropertyKindBool,
+				Description: "set tls connection",
+				Default:     "false",
+			},
+			{
+				Name:        "ca_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls root ca certificate file path",
+			},
+			{
+				Name:        "cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls client certificate file path",
+			},
+			{
+				Name:        "key_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls client key file path",
+			},
+			{
+				Name:        "insecure_skip_verify",
+				Kind:        config.PropertyKindBool,
+				Description: "set skipping tls server certificate verification",
+				Default:     "false",
+			},
+			{
+				Name:        "max_message_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set maximum sent and received message size in bytes",
+				Default:     fmt.Sprintf("%d", defaultMaxMessageSize),
+				Min:         1,
+				Max:         1024 * 1024 * 1024,
+			},
+			{
+				Name:        "timeout_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set request timeout in seconds",
+				Default:     fmt.Sprintf("%d", defaultTimeoutSeconds),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+		},
+	}
+}
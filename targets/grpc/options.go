@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultMaxMessageSize = 4 * 1024 * 1024
+	defaultTimeoutSeconds = 30
+)
+
+type options struct {
+	address            string
+	channel            string
+	token              string
+	tls                bool
+	caCertFile         string
+	certFile           string
+	keyFile            string
+	insecureSkipVerify bool
+	maxMessageSize     int
+	timeoutSeconds     int
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	host, port, err := cfg.MustParseAddress("address", "")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing address value, %w", err)
+	}
+	o.address = fmt.Sprintf("%s:%d", host, port)
+	o.channel = cfg.ParseString("channel", "")
+	o.token = cfg.ParseString("token", "")
+	o.caCertFile = cfg.ParseString("ca_cert_file", "")
+	o.certFile = cfg.ParseString("cert_file", "")
+	o.keyFile = cfg.ParseString("key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls values, both cert_file and key_file must be set")
+	}
+	o.insecureSkipVerify = cfg.ParseBool("insecure_skip_verify", false)
+	o.tls = cfg.ParseBool("tls", false) || o.caCertFile != "" || o.certFile != "" || o.insecureSkipVerify
+	o.maxMessageSize, err = cfg.ParseIntWithRange("max_message_size", defaultMaxMessageSize, 1, 1024*1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max message size value, %w", err)
+	}
+	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, 24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	return o, nil
+}
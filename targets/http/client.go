@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	TagStatus = "http_status"
+
+	tagsPrefix = "http_"
+)
+
+// excludedHeaders are message tags which are not sent as request headers
+var excludedHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"transfer-encoding": true,
+	"authorization":     true,
+}
+
+// message is the data of a bridged message, the url template is executed with it
+type message struct {
+	Id       string
+	Channel  string
+	Metadata string
+	Body     []byte
+	Tags     map[string]string
+}
+
+// urlMessage is the url template data, its fields are escaped so a message value cannot change the url structure,
+// the unescaped fields are set in Raw
+type urlMessage struct {
+	Id       string
+	Channel  string
+	Metadata string
+	Body     string
+	Tags     map[string]string
+	Raw      *message
+}
+
+type Client struct {
+	log    *logger.Logger
+	opts   options
+	client *resty.Client
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("http")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	c.client = resty.New().
+		SetTimeout(time.Duration(c.opts.timeoutSeconds) * time.Second).
+		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: c.opts.insecureSkipVerify})
+	if c.opts.caCertFile != "" {
+		c.client.SetRootCertificate(c.opts.caCertFile)
+	}
+	if c.opts.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.opts.certFile, c.opts.keyFile)
+		if err != nil {
+			return fmt.Errorf("error loading tls certificate, %w", err)
+		}
+		c.client.SetCertificates(cert)
+	}
+	switch c.opts.authType {
+	case authBasic:
+		c.client.SetBasicAuth(c.opts.username, c.opts.password)
+	case authBearer:
+		c.client.SetAuthToken(c.opts.token)
+	}
+	return nil
+}
+
+func (c *Client) Stop() error {
+	return nil
+}
+
+// Do sends the message as a http request, the response is returned as a query response with the status code in the http_status tag,
+// a response with a non 2xx status is returned together with an error
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	var msg *message
+	switch val := request.(type) {
+	case *kubemq.CommandReceive:
+		msg = &message{Id: val.Id, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	case *kubemq.Event:
+		msg = &message{Id: val.Id, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	case *kubemq.EventStoreReceive:
+		msg = &message{Id: val.Id, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	case *kubemq.QueryReceive:
+		msg = &message{Id: val.Id, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	case *kubemq.QueueMessage:
+		msg = &message{Id: val.MessageID, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	case *queues_stream.QueueMessage:
+		msg = &message{Id: val.MessageID, Channel: val.Channel, Metadata: val.Metadata, Body: val.Body, Tags: val.Tags}
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+	url, err := c.parseUrl(msg)
+	if err != nil {
+		return nil, err
+	}
+	req := c.client.R().SetContext(ctx)
+	if msg.Body != nil {
+		req.SetBody(msg.Body)
+	}
+	if c.opts.tagsAsHeaders {
+		for key, value := range msg.Tags {
+			if excludedHeaders[strings.ToLower(key)] || strings.HasPrefix(key, tagsPrefix) {
+				continue
+			}
+			req.SetHeader(key, value)
+		}
+	}
+	req.SetHeaders(c.opts.headers)
+	resp, err := req.Execute(c.opts.method, url)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseResponse(msg, resp)
+}
+
+// escapeUrlValue escapes all the characters of a value except the unreserved ones, so it is safe in a url path or query
+func escapeUrlValue(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func (c *Client) parseUrl(msg *message) (string, error) {
+	data := &urlMessage{
+		Id:       escapeUrlValue(msg.Id),
+		Channel:  escapeUrlValue(msg.Channel),
+		Metadata: escapeUrlValue(msg.Metadata),
+		Body:     escapeUrlValue(string(msg.Body)),
+		Tags:     map[string]string{},
+		Raw:      msg,
+	}
+	for key, value := range msg.Tags {
+		data.Tags[key] = escapeUrlValue(value)
+	}
+	buf := &bytes.Buffer{}
+	if err := c.opts.url.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("error executing url template, %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (c *Client) parseResponse(msg *message, resp *resty.Response) (*kubemq.QueryResponse, error) {
+	tags := map[string]string{}
+	for key, values := range resp.Header() {
+		tags[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	tags[TagStatus] = strconv.Itoa(resp.StatusCode())
+	response := &kubemq.QueryResponse{
+		QueryId:    msg.Id,
+		Executed:   resp.IsSuccess(),
+		ExecutedAt: resp.ReceivedAt(),
+		Body:       resp.Body(),
+		Tags:       tags,
+	}
+	if !resp.IsSuccess() {
+		response.Error = fmt.Sprintf("http request failed with status %s", resp.Status())
+		return response, fmt.Errorf("%s", response.Error)
+	}
+	return response, nil
+}
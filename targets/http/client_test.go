@@ -0,0 +1,254 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	method  string
+	path    string
+	body    string
+	headers http.Header
+}
+
+func setupServer(t *testing.T, status int, body string) (*httptest.Server, *received) {
+	r := &received{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		r.method = req.Method
+		r.path = req.URL.RequestURI()
+		r.body = string(data)
+		r.headers = req.Header.Clone()
+		w.Header().Set("X-Response", "response-header")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, r
+}
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name        string
+		connection  config.Metadata
+		request     interface{}
+		status      int
+		wantErr     bool
+		wantMethod  string
+		wantPath    string
+		wantBody    string
+		wantHeaders map[string]string
+		wantNoHdrs  []string
+	}{
+		{
+			name:       "query with url template",
+			connection: config.Metadata{"url": "{{.Server}}/orders/{{.Channel}}/{{.Tags.id}}?missing={{.Tags.missing}}"},
+			request: &kubemq.QueryReceive{
+				Id:      "1",
+				Channel: "created",
+				Body:    []byte("data"),
+				Tags:    map[string]string{"id": "10"},
+			},
+			status:     http.StatusOK,
+			wantMethod: http.MethodPost,
+			wantPath:   "/orders/created/10?missing=",
+			wantBody:   "data",
+		},
+		{
+			name:       "query with escaped url values",
+			connection: config.Metadata{"url": "{{.Server}}/orders/{{.Tags.id}}?channel={{.Channel}}&path={{.Raw.Tags.path}}&raw={{queryEscape .Raw.Metadata}}"},
+			request: &kubemq.QueryReceive{
+				Id:       "1",
+				Channel:  "a&admin=true",
+				Metadata: "x y",
+				Tags:     map[string]string{"id": "../admin?x=1#top", "path": "a/b"},
+			},
+			status:     http.StatusOK,
+			wantMethod: http.MethodPost,
+			wantPath:   "/orders/..%2Fadmin%3Fx%3D1%23top?channel=a%26admin%3Dtrue&path=a/b&raw=x+y",
+		},
+		{
+			name: "tags as headers with static headers",
+			connection: config.Metadata{
+				"url":     "{{.Server}}/",
+				"method":  "put",
+				"headers": `{"X-Static":"static","X-Override":"static"}`,
+			},
+			request: &kubemq.Event{
+				Channel: "events",
+				Body:    []byte("event"),
+				Tags: map[string]string{
+					"X-Tag":          "tag",
+					"X-Override":     "tag",
+					"Authorization":  "Bearer tag",
+					"http_method":    "GET",
+					"content-length": "100",
+				},
+			},
+			status:     http.StatusCreated,
+			wantMethod: http.MethodPut,
+			wantPath:   "/",
+			wantBody:   "event",
+			wantHeaders: map[string]string{
+				"X-Tag":      "tag",
+				"X-Static":   "static",
+				"X-Override": "static",
+			},
+			wantNoHdrs: []string{"Authorization", "Http_method"},
+		},
+		{
+			name:       "tags as headers disabled",
+			connection: config.Metadata{"url": "{{.Server}}/", "tags_as_headers": "false"},
+			request: queues_stream.NewQueueMessage().
+				SetChannel("queue").
+				SetBody([]byte("queue")).
+				SetTags(map[string]string{"X-Tag": "tag"}),
+			status:     http.StatusOK,
+			wantMethod: http.MethodPost,
+			wantPath:   "/",
+			wantBody:   "queue",
+			wantNoHdrs: []string{"X-Tag"},
+		},
+		{
+			name:        "basic auth",
+			connection:  config.Metadata{"url": "{{.Server}}/", "auth_type": "basic", "username": "user", "password": "pass"},
+			request:     &kubemq.CommandReceive{Channel: "command"},
+			status:      http.StatusOK,
+			wantMethod:  http.MethodPost,
+			wantPath:    "/",
+			wantHeaders: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+		},
+		{
+			name:        "bearer auth",
+			connection:  config.Metadata{"url": "{{.Server}}/", "auth_type": "bearer", "token": "token"},
+			request:     &kubemq.EventStoreReceive{Channel: "events-store"},
+			status:      http.StatusOK,
+			wantMethod:  http.MethodPost,
+			wantPath:    "/",
+			wantHeaders: map[string]string{"Authorization": "Bearer token"},
+		},
+		{
+			name:       "error status",
+			connection: config.Metadata{"url": "{{.Server}}/"},
+			request:    kubemq.NewQueueMessage().SetChannel("queue"),
+			status:     http.StatusNotFound,
+			wantErr:    true,
+			wantMethod: http.MethodPost,
+			wantPath:   "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, r := setupServer(t, tt.status, "response")
+			connection := config.Metadata{}
+			for key, value := range tt.connection {
+				connection[key] = value
+			}
+			connection["url"] = server.URL + connection["url"][len("{{.Server}}"):]
+			c := New()
+			require.NoError(t, c.Init(context.Background(), connection, "test", nil))
+			result, err := c.Do(context.Background(), tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			response, ok := result.(*kubemq.QueryResponse)
+			require.True(t, ok)
+			require.Equal(t, !tt.wantErr, response.Executed)
+			require.Equal(t, "response", string(response.Body))
+			require.Equal(t, "response-header", response.Tags["x-response"])
+			require.Equal(t, strconv.Itoa(tt.status), response.Tags[TagStatus])
+			require.Equal(t, tt.wantMethod, r.method)
+			require.Equal(t, tt.wantPath, r.path)
+			require.Equal(t, tt.wantBody, r.body)
+			for key, value := range tt.wantHeaders {
+				require.Equal(t, value, r.headers.Get(key))
+			}
+			for _, key := range tt.wantNoHdrs {
+				require.Empty(t, r.headers.Get(key))
+			}
+		})
+	}
+}
+
+func TestClient_Do_UnknownRequest(t *testing.T) {
+	c := New()
+	require.NoError(t, c.Init(context.Background(), config.Metadata{"url": "http://localhost"}, "test", nil))
+	_, err := c.Do(context.Background(), "request")
+	require.Error(t, err)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			cfg:     config.Metadata{"url": "http://localhost/{{.Channel}}"},
+			wantErr: false,
+		},
+		{
+			name:    "missing url",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid url template",
+			cfg:     config.Metadata{"url": "http://localhost/{{.Channel"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid method",
+			cfg:     config.Metadata{"url": "http://localhost", "method": "head"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid headers",
+			cfg:     config.Metadata{"url": "http://localhost", "headers": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			cfg:     config.Metadata{"url": "http://localhost", "timeout_seconds": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "basic auth without username",
+			cfg:     config.Metadata{"url": "http://localhost", "auth_type": "basic"},
+			wantErr: true,
+		},
+		{
+			name:    "bearer auth without token",
+			cfg:     config.Metadata{"url": "http://localhost", "auth_type": "bearer"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"url": "http://localhost", "cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
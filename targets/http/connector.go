@@ -0,0 +1,91 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.http",
+		Description: "http sender, sends each message as a http request and returns the response",
+		Properties: []*config.Property{
+			{
+				Name:        "url",
+				Kind:        config.PropertyKindString,
+				Description: "set request url, a go template of the message fields Id, Channel, Metadata and Tags",
+				Required:    true,
+			},
+			{
+				Name:        "method",
+				Kind:        config.PropertyKindString,
+				Description: "set request method",
+				Default:     defaultMethod,
+				Options:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			},
+			{
+				Name:        "headers",
+				Kind:        config.PropertyKindString,
+				Description: "set request headers as a json object",
+			},
+			{
+				Name:        "tags_as_headers",
+				Kind:        config.PropertyKindBool,
+				Description: "set whether message tags are sent as request headers",
+				Default:     "true",
+			},
+			{
+				Name:        "timeout_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set request timeout seconds",
+				Default:     fmt.Sprintf("%d", defaultTimeoutSeconds),
+				Min:         1,
+				Max:         24 * 60 * 60,
+			},
+			{
+				Name:        "auth_type",
+				Kind:        config.PropertyKindString,
+				Description: "set request authentication type",
+				Default:     authNone,
+				Options:     []string{authNone, authBasic, authBearer},
+			},
+			{
+				Name:        "username",
+				Kind:        config.PropertyKindString,
+				Description: "set basic authentication username",
+			},
+			{
+				Name:        "password",
+				Kind:        config.PropertyKindString,
+				Description: "set basic authentication password",
+			},
+			{
+				Name:        "token",
+				Kind:        config.PropertyKindString,
+				Description: "set bearer authentication token",
+			},
+			{
+				Name:        "ca_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls ca certificate file path",
+			},
+			{
+				Name:        "cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls client certificate file path",
+			},
+			{
+				Name:        "key_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls client key file path",
+			},
+			{
+				Name:        "insecure_skip_verify",
+				Kind:        config.PropertyKindBool,
+				Description: "set whether to skip the server certificate verification",
+				Default:     "false",
+			},
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultMethod         = http.MethodPost
+	defaultTimeoutSeconds = 30
+
+	authNone   = "none"
+	authBasic  = "basic"
+	authBearer = "bearer"
+)
+
+// urlFuncs are the escaping functions of the url template, for the unescaped message fields
+var urlFuncs = template.FuncMap{
+	"pathEscape":  url.PathEscape,
+	"queryEscape": url.QueryEscape,
+}
+
+var methods = map[string]string{
+	"":                defaultMethod,
+	http.MethodGet:    http.MethodGet,
+	http.MethodPost:   http.MethodPost,
+	http.MethodPut:    http.MethodPut,
+	http.MethodPatch:  http.MethodPatch,
+	http.MethodDelete: http.MethodDelete,
+}
+
+var authTypes = map[string]string{
+	"":         authNone,
+	authNone:   authNone,
+	authBasic:  authBasic,
+	authBearer: authBearer,
+}
+
+type options struct {
+	url                *template.Template
+	method             string
+	headers            map[string]string
+	tagsAsHeaders      bool
+	timeoutSeconds     int
+	authType           string
+	username           string
+	password           string
+	token              string
+	caCertFile         string
+	certFile           string
+	keyFile            string
+	insecureSkipVerify bool
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	url, err := cfg.MustParseString("url")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing url value, %w", err)
+	}
+	o.url, err = template.New("url").Option("missingkey=zero").Funcs(urlFuncs).Parse(url)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing url template, %w", err)
+	}
+	o.method, err = config.Metadata{"method": strings.ToUpper(cfg.ParseString("method", ""))}.ParseStringMap("method", methods)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing method value, %w", err)
+	}
+	o.headers, err = cfg.MustParseJsonMap("headers")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing headers value, %w", err)
+	}
+	o.tagsAsHeaders = cfg.ParseBool("tags_as_headers", true)
+	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, 24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	o.authType, err = cfg.ParseStringMap("auth_type", authTypes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing auth type value, %w", err)
+	}
+	switch o.authType {
+	case authBasic:
+		o.username, err = cfg.MustParseString("username")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing username value, %w", err)
+		}
+		o.password = cfg.ParseString("password", "")
+	case authBearer:
+		o.token, err = cfg.MustParseString("token")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing token value, %w", err)
+		}
+	}
+	o.caCertFile = cfg.ParseString("ca_cert_file", "")
+	o.certFile = cfg.ParseString("cert_file", "")
+	o.keyFile = cfg.ParseString("key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls values, both cert_file and key_file must be set")
+	}
+	o.insecureSkipVerify = cfg.ParseBool("insecure_skip_verify", false)
+	return o, nil
+}
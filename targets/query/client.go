@@ -2,11 +2,15 @@ package query
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-go"
 )
 
@@ -14,6 +18,8 @@ type Client struct {
 	log    *logger.Logger
 	opts   options
 	client *kubemq.Client
+	cache  *cache.Cache
+	send   func(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error)
 }
 
 func New() *Client {
@@ -30,22 +36,29 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId)),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = pool.GetClient(ctx, pool.Options{
+		Host:      c.opts.host,
+		Port:      c.opts.port,
+		Transport: c.opts.transport,
+		Uri:       c.opts.uri,
+		AuthToken: c.opts.authToken,
+		CertFile:  c.opts.certFile,
+		CertData:  c.opts.certData,
+		ClientId:  fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId),
+	})
 	if err != nil {
 		return err
 	}
+	if c.opts.cacheMode == cacheModeLocal {
+		c.cache = cache.New(c.opts.cacheSize, c.opts.cacheTTL)
+	}
+	c.send = c.sendQuery
 	return nil
 }
 
 func (c *Client) Stop() error {
 	if c.client != nil {
-		return c.client.Close()
+		pool.ReleaseClient(c.client)
 	}
 	return nil
 }
@@ -70,6 +83,28 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 		query.SetChannel(c.opts.defaultChannel)
 	}
 	query.SetTimeout(time.Duration(c.opts.timeoutSeconds) * time.Second)
+	switch c.opts.cacheMode {
+	case cacheModeServer:
+		query.SetCacheKey(cacheKey(query)).SetCacheTTL(c.opts.cacheTTL)
+	case cacheModeLocal:
+		key := cacheKey(query)
+		if cached, ok := c.cache.Get(key); ok {
+			resp := *cached.(*kubemq.QueryResponse)
+			resp.QueryId = query.Id
+			resp.CacheHit = true
+			return &resp, nil
+		}
+		queryResponse, err := c.send(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.Set(key, queryResponse)
+		return queryResponse, nil
+	}
+	return c.send(ctx, query)
+}
+
+func (c *Client) sendQuery(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
 	queryResponse, err := c.client.SetQuery(query).Send(ctx)
 	if err != nil {
 		return nil, err
@@ -80,6 +115,12 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 	return queryResponse, nil
 }
 
+// cacheKey returns the cache key of a query, its channel and the hash of its body
+func cacheKey(query *kubemq.Query) string {
+	hash := sha256.Sum256(query.Body)
+	return query.Channel + ":" + hex.EncodeToString(hash[:])
+}
+
 func (c *Client) parseEvent(event *kubemq.Event) *kubemq.Query {
 	return kubemq.NewQuery().
 		SetBody(event.Body).
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
 
 	"github.com/kubemq-io/kubemq-go"
 
@@ -403,3 +404,70 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Cache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tests := []struct {
+		name         string
+		cacheMode    string
+		wantSent     int
+		wantCacheHit bool
+	}{
+		{
+			name:      "no cache",
+			cacheMode: "",
+			wantSent:  3,
+		},
+		{
+			name:         "local cache",
+			cacheMode:    cacheModeLocal,
+			wantSent:     2,
+			wantCacheHit: true,
+		},
+		{
+			name:      "server cache",
+			cacheMode: cacheModeServer,
+			wantSent:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseOptions(config.Metadata{
+				"address":           "localhost:50000",
+				"channel":           "query",
+				"cache_mode":        tt.cacheMode,
+				"cache_ttl_seconds": "30",
+			})
+			require.NoError(t, err)
+			c := New()
+			c.opts = opts
+			if tt.cacheMode == cacheModeLocal {
+				c.cache = cache.New(opts.cacheSize, opts.cacheTTL)
+			}
+			var sent []*kubemq.Query
+			c.send = func(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
+				sent = append(sent, query)
+				return &kubemq.QueryResponse{QueryId: query.Id, Executed: true, Body: query.Body}, nil
+			}
+			for _, body := range []string{"a", "a", "b"} {
+				_, err := c.Do(ctx, &kubemq.Event{Id: body, Body: []byte(body)})
+				require.NoError(t, err)
+			}
+			require.Len(t, sent, tt.wantSent)
+			resp, err := c.Do(ctx, &kubemq.Event{Id: "id", Body: []byte("b")})
+			require.NoError(t, err)
+			queryResponse := resp.(*kubemq.QueryResponse)
+			require.Equal(t, tt.wantCacheHit, queryResponse.CacheHit)
+			require.Equal(t, []byte("b"), queryResponse.Body)
+			require.Equal(t, "id", queryResponse.QueryId)
+			if tt.cacheMode == cacheModeServer {
+				require.Equal(t, sent[0].CacheKey, sent[1].CacheKey)
+				require.NotEqual(t, sent[0].CacheKey, sent[2].CacheKey)
+				require.Equal(t, 30*time.Second, sent[0].CacheTTL)
+			}
+		})
+	}
+	_, err := parseOptions(config.Metadata{"address": "localhost:50000", "channel": "query", "cache_mode": "redis"})
+	require.Error(t, err)
+}
@@ -3,23 +3,41 @@ package query
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"math"
+	"time"
 )
 
 const (
 	defaultHost           = "localhost:5000"
 	defaultTimeoutSeconds = 600
+
+	cacheModeLocal  = "local"
+	cacheModeServer = "server"
 )
 
+var cacheModeMap = map[string]string{
+	"":              "",
+	cacheModeLocal:  cacheModeLocal,
+	cacheModeServer: cacheModeServer,
+}
+
 type options struct {
 	host           string
 	port           int
+	transport      string
+	uri            string
 	clientId       string
 	channel        string
 	authToken      string
+	certFile       string
+	certData       string
 	defaultChannel string
 	timeoutSeconds int
+	cacheMode      string
+	cacheTTL       time.Duration
+	cacheSize      int
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -29,7 +47,13 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
+	o.transport, o.uri, err = pool.ParseTransport(cfg, o.host, o.port)
+	if err != nil {
+		return options{}, err
+	}
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.certFile = cfg.ParseString("tls_cert_file", "")
+	o.certData = cfg.ParseString("tls_cert_data", "")
 	o.clientId = cfg.ParseString("client_id", uuid.New().String())
 	o.channel = cfg.ParseString("channel", "")
 	if o.channel != "" {
@@ -45,5 +69,18 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
 	}
+	o.cacheMode, err = cfg.ParseStringMap("cache_mode", cacheModeMap)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache mode value, %w", err)
+	}
+	cacheTTLSeconds, err := cfg.ParseIntWithRange("cache_ttl_seconds", 60, 1, 7*24*60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache ttl seconds value, %w", err)
+	}
+	o.cacheTTL = time.Duration(cacheTTLSeconds) * time.Second
+	o.cacheSize, err = cfg.ParseIntWithRange("cache_size", 10000, 1, 10000000)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache size value, %w", err)
+	}
 	return o, nil
 }
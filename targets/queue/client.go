@@ -6,6 +6,7 @@ import (
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemq-go/queues_stream"
 )
@@ -13,7 +14,9 @@ import (
 type Client struct {
 	log          *logger.Logger
 	opts         options
+	clientId     string
 	streamClient *queues_stream.QueuesStreamClient
+	client       *kubemq.Client
 }
 
 func New() *Client {
@@ -30,17 +33,23 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 	if err != nil {
 		return err
 	}
-	c.streamClient, err = queues_stream.NewQueuesStreamClient(ctx,
-		queues_stream.WithAddress(c.opts.host, c.opts.port),
-		queues_stream.WithClientId(fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId)),
-		queues_stream.WithCheckConnection(true),
-		queues_stream.WithAutoReconnect(true),
-		queues_stream.WithAuthToken(c.opts.authToken),
-		queues_stream.WithConnectionNotificationFunc(
-			func(msg string) {
-				c.log.Infof(msg)
-			}),
-	)
+	c.clientId = fmt.Sprintf("kubemq-bridges_%s_%s", bindingName, c.opts.clientId)
+	poolOpts := pool.Options{
+		Host:      c.opts.host,
+		Port:      c.opts.port,
+		Transport: c.opts.transport,
+		Uri:       c.opts.uri,
+		AuthToken: c.opts.authToken,
+		CertFile:  c.opts.certFile,
+		CertData:  c.opts.certData,
+		ClientId:  c.clientId,
+	}
+	// the queues stream client is grpc only, the rest transport sends the messages in batches with the kubemq client
+	if c.opts.transport == pool.TransportRest {
+		c.client, err = pool.GetClient(ctx, poolOpts)
+	} else {
+		c.streamClient, err = pool.GetQueuesStreamClient(ctx, poolOpts)
+	}
 	if err != nil {
 		return err
 	}
@@ -49,7 +58,10 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingNa
 
 func (c *Client) Stop() error {
 	if c.streamClient != nil {
-		return c.streamClient.Close()
+		pool.ReleaseQueuesStreamClient(c.streamClient)
+	}
+	if c.client != nil {
+		pool.ReleaseClient(c.client)
 	}
 	return nil
 }
@@ -72,6 +84,9 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 	default:
 		return nil, fmt.Errorf("unknown request type")
 	}
+	if c.client != nil {
+		return nil, c.sendRest(ctx, messages)
+	}
 	results, err := c.streamClient.Send(ctx, messages...)
 	if err != nil {
 		return nil, err
@@ -84,6 +99,26 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 	return nil, nil
 }
 
+func (c *Client) sendRest(ctx context.Context, messages []*queues_stream.QueueMessage) error {
+	var batch []*kubemq.QueueMessage
+	for _, message := range messages {
+		if message.ClientID == "" {
+			message.ClientID = c.clientId
+		}
+		batch = append(batch, &kubemq.QueueMessage{QueueMessage: message.QueueMessage})
+	}
+	results, err := c.client.SendQueueMessages(ctx, batch)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.IsError {
+			return fmt.Errorf(result.Error)
+		}
+	}
+	return nil
+}
+
 func (c *Client) parseEvent(event *kubemq.Event, channels []string) []*queues_stream.QueueMessage {
 	var messages []*queues_stream.QueueMessage
 	if len(channels) == 0 {
@@ -2,9 +2,13 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"github.com/kubemq-io/kubemq-go"
+	pb "github.com/kubemq-io/protobuf/go"
+	"net/http"
+	"net/http/httptest"
 
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -328,3 +332,50 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_DoRest(t *testing.T) {
+	var received *pb.QueueMessagesBatchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ping":
+			_, _ = w.Write([]byte(`{"is_error":false,"data":{"Host":"test"}}`))
+		case "/queue/send_batch":
+			received = &pb.QueueMessagesBatchRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+			results := &pb.QueueMessagesBatchResponse{}
+			for _, msg := range received.Messages {
+				result := &pb.SendQueueMessageResult{MessageID: msg.MessageID}
+				if msg.Channel == "bad" {
+					result.IsError = true
+					result.Error = "bad channel"
+				}
+				results.Results = append(results.Results, result)
+			}
+			data, _ := json.Marshal(results)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"is_error": false, "data": json.RawMessage(data)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := New()
+	require.NoError(t, c.Init(ctx, config.Metadata{"uri": server.URL, "channels": "q1,q2", "client_id": "rest"}, "test", nil))
+	defer func() {
+		require.NoError(t, c.Stop())
+	}()
+	require.Nil(t, c.streamClient)
+	_, err := c.Do(ctx, &kubemq.Event{Id: "id", Channel: "events", Body: []byte("data")})
+	require.NoError(t, err)
+	require.Len(t, received.Messages, 2)
+	require.Equal(t, "q1", received.Messages[0].Channel)
+	require.Equal(t, "data", string(received.Messages[1].Body))
+	require.Equal(t, "kubemq-bridges_test_rest", received.Messages[0].ClientID)
+
+	c.opts.channels = []string{"bad"}
+	_, err = c.Do(ctx, &kubemq.Event{Id: "id", Channel: "events"})
+	require.EqualError(t, err, "bad channel")
+}
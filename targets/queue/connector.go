@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"math"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.queue",
+		Aliases:     []string{"kubemq.queue"},
+		Description: "kubemq queue sender",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "kubemq server address (gRPC interface)",
+				Default:     defaultHost,
+			},
+			{
+				Name:        "transport",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server transport, rest connects to the address http interface or to the uri",
+				Default:     "grpc",
+				Options:     []string{"grpc", "rest"},
+			},
+			{
+				Name:        "uri",
+				Kind:        config.PropertyKindString,
+				Description: "set kubemq server rest interface uri, sets the rest transport",
+			},
+			{
+				Name:        "client_id",
+				Kind:        config.PropertyKindString,
+				Description: "set client id",
+			},
+			{
+				Name:        "auth_token",
+				Kind:        config.PropertyKindString,
+				Description: "set authentication token",
+			},
+			{
+				Name:        "tls_cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "tls_cert_data",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate pem data",
+			},
+			{
+				Name:        "channel",
+				Kind:        config.PropertyKindString,
+				Description: "set channel to send to, takes precedence over channels",
+			},
+			{
+				Name:        "channels",
+				Kind:        config.PropertyKindList,
+				Description: "set comma separated list of channels to send to, when channel is not set",
+			},
+			{
+				Name:        "expiration_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set default expiration seconds for each queue message, 0 - no expiration",
+				Default:     "0",
+				Min:         0,
+				Max:         math.MaxInt32,
+			},
+			{
+				Name:        "delay_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set default delay seconds for each queue message, 0 - no delay",
+				Default:     "0",
+				Min:         0,
+				Max:         math.MaxInt32,
+			},
+			{
+				Name:        "max_receive_count",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many failed queue messages before routes to dead-letter queue, 0 - no routes to dead-letter queue",
+				Default:     "0",
+				Min:         0,
+				Max:         math.MaxInt32,
+			},
+			{
+				Name:        "dead_letter_queue",
+				Kind:        config.PropertyKindString,
+				Description: "set dead-letter queue",
+			},
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package stdout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
+)
+
+type line struct {
+	Time    time.Time    `json:"time"`
+	Binding string       `json:"binding"`
+	Message *tap.Message `json:"message"`
+}
+
+type Client struct {
+	sync.Mutex
+	log     *logger.Logger
+	opts    options
+	binding string
+	out     io.Writer
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("stdout")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	c.binding = bindingName
+	c.out = os.Stdout
+	return nil
+}
+
+func (c *Client) Stop() error {
+	return nil
+}
+
+// Do prints the message as a single line, with the truncation and redaction rules applied
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	l := &line{
+		Time:    time.Now().UTC(),
+		Binding: c.binding,
+		Message: tap.NewMessage(c.opts.rules, request),
+	}
+	var data []byte
+	switch c.opts.format {
+	case formatText:
+		data = []byte(formatLine(l))
+	default:
+		var err error
+		data, err = json.Marshal(l)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.Lock()
+	defer c.Unlock()
+	if _, err := fmt.Fprintf(c.out, "%s\n", data); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func formatLine(l *line) string {
+	m := l.Message
+	sb := &strings.Builder{}
+	_, _ = fmt.Fprintf(sb, "%s [%s] %s channel=%s id=%s", l.Time.Format(time.RFC3339Nano), l.Binding, m.Type, m.Channel, m.Id)
+	if m.Metadata != "" {
+		_, _ = fmt.Fprintf(sb, " metadata=%q", m.Metadata)
+	}
+	keys := make([]string, 0, len(m.Tags))
+	for key := range m.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(sb, " tag.%s=%q", key, m.Tags[key])
+	}
+	_, _ = fmt.Fprintf(sb, " body_size=%d body=%q", m.BodySize, m.Body)
+	if m.Truncated {
+		sb.WriteString(" (truncated)")
+	}
+	return sb.String()
+}
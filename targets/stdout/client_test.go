@@ -0,0 +1,92 @@
+package stdout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection config.Metadata
+		request    interface{}
+		wantLine   string
+	}{
+		{
+			name:       "json",
+			connection: config.Metadata{"max_body_size": "4"},
+			request:    &kubemq.Event{Id: "1", Channel: "ch", Body: []byte("long body"), Tags: map[string]string{"token": "t", "key": "value"}},
+			wantLine:   `"message":{"type":"event","id":"1","channel":"ch","body":"long","body_size":9,"truncated":true,"tags":{"key":"value","token":"[REDACTED]"}}}`,
+		},
+		{
+			name:       "text",
+			connection: config.Metadata{"format": "text", "redact_pattern": "secret"},
+			request:    &kubemq.QueryReceive{Id: "2", Channel: "ch", Metadata: "a secret", Body: []byte("body"), Tags: map[string]string{"b": "2", "a": "1"}},
+			wantLine:   `[test] query channel=ch id=2 metadata="a [REDACTED]" tag.a="1" tag.b="2" body_size=4 body="body"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			require.NoError(t, c.Init(context.Background(), tt.connection, "test", nil))
+			out := &bytes.Buffer{}
+			c.out = out
+			resp, err := c.Do(context.Background(), tt.request)
+			require.NoError(t, err)
+			require.Nil(t, resp)
+			line := out.String()
+			require.True(t, strings.HasSuffix(line, "\n"))
+			require.Contains(t, line, tt.wantLine)
+			if c.opts.format == formatJson {
+				require.True(t, json.Valid([]byte(line)))
+			}
+			require.NoError(t, c.Stop())
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			cfg:     config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid format",
+			cfg:     config.Metadata{"format": "xml"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max body size",
+			cfg:     config.Metadata{"max_body_size": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid redact pattern",
+			cfg:     config.Metadata{"redact_pattern": "("},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package stdout
+
+import (
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.stdout",
+		Description: "console writer, prints the messages to the bridge standard output for troubleshooting",
+		Properties: []*config.Property{
+			{
+				Name:        "format",
+				Kind:        config.PropertyKindString,
+				Description: "set the printed line format",
+				Default:     formatJson,
+				Options:     []string{formatJson, formatText},
+			},
+			{
+				Name:        "max_body_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set max body size in bytes to print, 0 - no truncation",
+				Default:     "1024",
+				Min:         0,
+				Max:         1024 * 1024 * 1024,
+			},
+			{
+				Name:        "redact_tags",
+				Kind:        config.PropertyKindString,
+				Description: "set comma separated tags keys which values are redacted",
+				Default:     "authorization,password,token,secret",
+			},
+			{
+				Name:        "redact_pattern",
+				Kind:        config.PropertyKindString,
+				Description: "set regular expression which matches are redacted from the body and metadata",
+				Default:     "",
+			},
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package stdout
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tap"
+)
+
+const (
+	formatJson = "json"
+	formatText = "text"
+)
+
+var formats = map[string]string{
+	"":         formatJson,
+	formatJson: formatJson,
+	formatText: formatText,
+}
+
+type options struct {
+	format string
+	rules  tap.Rules
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.format, err = cfg.ParseStringMap("format", formats)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing format value, %w", err)
+	}
+	o.rules, err = tap.ParseRules(cfg, "")
+	if err != nil {
+		return options{}, err
+	}
+	return o, nil
+}
@@ -8,8 +8,13 @@ import (
 	"github.com/kubemq-io/kubemq-bridges/targets/command"
 	"github.com/kubemq-io/kubemq-bridges/targets/events"
 	events_store "github.com/kubemq-io/kubemq-bridges/targets/events-store"
+	"github.com/kubemq-io/kubemq-bridges/targets/file"
+	"github.com/kubemq-io/kubemq-bridges/targets/grpc"
+	"github.com/kubemq-io/kubemq-bridges/targets/http"
 	"github.com/kubemq-io/kubemq-bridges/targets/query"
 	"github.com/kubemq-io/kubemq-bridges/targets/queue"
+	"github.com/kubemq-io/kubemq-bridges/targets/stdout"
+	"github.com/kubemq-io/kubemq-bridges/targets/websocket"
 )
 
 type Target interface {
@@ -51,8 +56,53 @@ func Init(ctx context.Context, kind string, connection config.Metadata, bindingN
 			return nil, err
 		}
 		return target, nil
+	case "target.http":
+		target := http.New()
+		if err := target.Init(ctx, connection, bindingName, log); err != nil {
+			return nil, err
+		}
+		return target, nil
+	case "target.file":
+		target := file.New()
+		if err := target.Init(ctx, connection, bindingName, log); err != nil {
+			return nil, err
+		}
+		return target, nil
+	case "target.grpc":
+		target := grpc.New()
+		if err := target.Init(ctx, connection, bindingName, log); err != nil {
+			return nil, err
+		}
+		return target, nil
+	case "target.websocket":
+		target := websocket.New()
+		if err := target.Init(ctx, connection, bindingName, log); err != nil {
+			return nil, err
+		}
+		return target, nil
+	case "target.stdout":
+		target := stdout.New()
+		if err := target.Init(ctx, connection, bindingName, log); err != nil {
+			return nil, err
+		}
+		return target, nil
 	default:
 		return nil, fmt.Errorf("invalid kind %s for target", kind)
 	}
 
 }
+
+func Connectors() []*config.Connector {
+	return []*config.Connector{
+		command.Connector(),
+		query.Connector(),
+		events.Connector(),
+		events_store.Connector(),
+		queue.Connector(),
+		http.Connector(),
+		file.Connector(),
+		grpc.Connector(),
+		websocket.Connector(),
+		stdout.Connector(),
+	}
+}
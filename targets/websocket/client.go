@@ -0,0 +1,277 @@
+package websocket
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	writeTimeout      = 10 * time.Second
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// message is the json sent to the clients, a body which is not valid utf8 is sent base64 encoded
+type message struct {
+	Id           string            `json:"id"`
+	Channel      string            `json:"channel"`
+	Metadata     string            `json:"metadata,omitempty"`
+	Body         string            `json:"body"`
+	BodyEncoding string            `json:"body_encoding,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+func newMessage(id, channel, metadata string, body []byte, tags map[string]string) *message {
+	msg := &message{Id: id, Channel: channel, Metadata: metadata, Tags: tags}
+	if utf8.Valid(body) {
+		msg.Body = string(body)
+	} else {
+		msg.Body = base64.StdEncoding.EncodeToString(body)
+		msg.BodyEncoding = "base64"
+	}
+	return msg
+}
+
+type Client struct {
+	log      *logger.Logger
+	opts     options
+	hub      *hub
+	server   *http.Server
+	upgrader websocket.Upgrader
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Init(ctx context.Context, connection config.Metadata, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("websocket")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	c.hub = newHub(c.opts.slowClient)
+	c.upgrader = websocket.Upgrader{CheckOrigin: c.checkOrigin}
+	mux := http.NewServeMux()
+	mux.Handle(c.opts.path, c.handler())
+	mux.Handle(c.opts.statsPath, c.statsHandler())
+	c.server = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", c.opts.host, c.opts.port),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	listener, err := net.Listen("tcp", c.server.Addr)
+	if err != nil {
+		return fmt.Errorf("error on listening to %s, %w", c.server.Addr, err)
+	}
+	go func() {
+		var err error
+		if c.opts.certFile != "" {
+			err = c.server.ServeTLS(listener, c.opts.certFile, c.opts.keyFile)
+		} else {
+			err = c.server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.log.Errorf("websocket server error, %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (c *Client) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range c.opts.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized checks the bearer token of a request, browsers which can not set headers on websocket and event source requests
+// can send the token as a query parameter
+func (c *Client) authorized(r *http.Request) bool {
+	if c.opts.token == "" {
+		return true
+	}
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); header != "" {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(c.opts.token)) == 1
+}
+
+func (c *Client) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.authorized(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if websocket.IsWebSocketUpgrade(r) {
+			c.serveWebSocket(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if !c.checkOrigin(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		c.serveSSE(w, r)
+	})
+}
+
+func (c *Client) statsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.authorized(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(c.hub.stats())
+	})
+}
+
+func (c *Client) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		c.log.Errorf("error upgrading websocket connection, %s", err.Error())
+		return
+	}
+	cl := newClient(uuid.New().String(), TransportWebSocket, r.RemoteAddr, parseFilter(r.URL.Query()), c.opts.bufferSize)
+	c.hub.add(cl)
+	defer func() {
+		c.hub.remove(cl)
+		_ = conn.Close()
+	}()
+	// the reader detects a closed connection, messages sent by the client are ignored
+	go func() {
+		defer cl.close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	ping := time.NewTicker(time.Duration(c.opts.pingIntervalSeconds) * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case data := <-cl.sendCh:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+			atomic.AddInt64(&cl.sent, 1)
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-cl.done:
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeTimeout))
+			return
+		}
+	}
+}
+
+func (c *Client) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	cl := newClient(uuid.New().String(), TransportSSE, r.RemoteAddr, parseFilter(r.URL.Query()), c.opts.bufferSize)
+	c.hub.add(cl)
+	defer c.hub.remove(cl)
+	ping := time.NewTicker(time.Duration(c.opts.pingIntervalSeconds) * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case data := <-cl.sendCh:
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+			atomic.AddInt64(&cl.sent, 1)
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-cl.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (c *Client) Stop() error {
+	if c.hub != nil {
+		c.hub.closeAll()
+	}
+	if c.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return c.server.Shutdown(ctx)
+}
+
+// Do broadcasts the message to the connected clients which filters match it
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	var msg *message
+	switch val := request.(type) {
+	case *kubemq.Event:
+		msg = newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.EventStoreReceive:
+		msg = newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.CommandReceive:
+		msg = newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.QueryReceive:
+		msg = newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *kubemq.QueueMessage:
+		msg = newMessage(val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+	case *queues_stream.QueueMessage:
+		msg = newMessage(val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	c.hub.broadcast(msg, data)
+	return nil, nil
+}
@@ -0,0 +1,220 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func startClient(t *testing.T, connection config.Metadata) (*Client, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	connection["address"] = address
+	c := New()
+	require.NoError(t, c.Init(context.Background(), connection, "test", nil))
+	t.Cleanup(func() {
+		_ = c.Stop()
+	})
+	return c, address
+}
+
+func waitClients(t *testing.T, c *Client, count int) {
+	require.Eventually(t, func() bool {
+		return len(c.hub.stats()) == count
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClient_WebSocket(t *testing.T) {
+	c, address := startClient(t, config.Metadata{"path": "/events"})
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/events?channel=orders.*&tag=type:created", address), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	waitClients(t, c, 1)
+
+	_, err = c.Do(context.Background(), &kubemq.Event{Id: "1", Channel: "alerts", Body: []byte("filtered")})
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), &kubemq.Event{Id: "2", Channel: "orders.created", Body: []byte("filtered"), Tags: map[string]string{"type": "updated"}})
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), &kubemq.QueryReceive{Id: "3", Channel: "orders.created", Body: []byte{0xff, 0xfe}, Tags: map[string]string{"type": "created"}})
+	require.NoError(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	msg := &message{}
+	require.NoError(t, json.Unmarshal(data, msg))
+	require.Equal(t, &message{Id: "3", Channel: "orders.created", Body: "//4=", BodyEncoding: "base64", Tags: map[string]string{"type": "created"}}, msg)
+	require.Eventually(t, func() bool {
+		return c.hub.stats()[0].Sent == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, conn.Close())
+	waitClients(t, c, 0)
+}
+
+func TestClient_SSE(t *testing.T) {
+	c, address := startClient(t, config.Metadata{"token": "token"})
+	resp, err := http.Get(fmt.Sprintf("http://%s/", address))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("http://%s/?token=token&channel=events", address))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitClients(t, c, 1)
+
+	_, err = c.Do(context.Background(), kubemq.NewQueueMessage().SetId("1").SetChannel("events").SetBody([]byte("body")))
+	require.NoError(t, err)
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "data: "))
+	msg := &message{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), msg))
+	require.Equal(t, "1", msg.Id)
+	require.Equal(t, "body", msg.Body)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/stats", address), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+	statsResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer statsResp.Body.Close()
+	var stats []*ClientStats
+	require.NoError(t, json.NewDecoder(statsResp.Body).Decode(&stats))
+	require.Len(t, stats, 1)
+	require.Equal(t, TransportSSE, stats[0].Transport)
+	require.Equal(t, []string{"events"}, stats[0].Filter.Channels)
+
+	require.NoError(t, c.Stop())
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "\n", string(rest))
+}
+
+func TestHub_Backpressure(t *testing.T) {
+	tests := []struct {
+		name        string
+		slowClient  string
+		wantDropped int64
+		wantClosed  bool
+	}{
+		{
+			name:        "drop",
+			slowClient:  slowClientDrop,
+			wantDropped: 3,
+		},
+		{
+			name:        "disconnect",
+			slowClient:  slowClientDisconnect,
+			wantDropped: 1,
+			wantClosed:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHub(tt.slowClient)
+			cl := newClient("1", TransportSSE, "remote", filter{}, 2)
+			h.add(cl)
+			msg := &message{Channel: "events"}
+			for i := 0; i < 5; i++ {
+				h.broadcast(msg, []byte("data"))
+			}
+			stats := h.stats()
+			require.Len(t, stats, 1)
+			require.Equal(t, tt.wantDropped, stats[0].Dropped)
+			require.Equal(t, 2, stats[0].Pending)
+			select {
+			case <-cl.done:
+				require.True(t, tt.wantClosed)
+			default:
+				require.False(t, tt.wantClosed)
+			}
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		msg   *message
+		want  bool
+	}{
+		{name: "no filter", query: "", msg: &message{Channel: "a"}, want: true},
+		{name: "channel", query: "channel=a&channel=b", msg: &message{Channel: "b"}, want: true},
+		{name: "channel wildcard", query: "channel=orders.*", msg: &message{Channel: "orders.created"}, want: true},
+		{name: "channel mismatch", query: "channel=orders.*", msg: &message{Channel: "alerts.created"}, want: false},
+		{name: "tags", query: "tag=a:1&tag=b:2", msg: &message{Channel: "a", Tags: map[string]string{"a": "1", "b": "2"}}, want: true},
+		{name: "tags mismatch", query: "tag=a:1&tag=b:2", msg: &message{Channel: "a", Tags: map[string]string{"a": "1"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, parseFilter(query).match(tt.msg))
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			cfg:     config.Metadata{},
+			wantErr: false,
+		},
+		{
+			name:    "same path and stats path",
+			cfg:     config.Metadata{"path": "/stats"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid buffer size",
+			cfg:     config.Metadata{"buffer_size": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid slow client",
+			cfg:     config.Metadata{"slow_client": "block"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"cert_file": "./cert.pem"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+func Connector() *config.Connector {
+	return &config.Connector{
+		Kind:        "target.websocket",
+		Description: "websocket and server sent events server, broadcasts the messages to the connected clients",
+		Properties: []*config.Property{
+			{
+				Name:        "address",
+				Kind:        config.PropertyKindString,
+				Description: "set listening address",
+				Default:     defaultAddress,
+			},
+			{
+				Name:        "path",
+				Kind:        config.PropertyKindString,
+				Description: "set websocket and server sent events path",
+				Default:     defaultPath,
+			},
+			{
+				Name:        "stats_path",
+				Kind:        config.PropertyKindString,
+				Description: "set connected clients stats path",
+				Default:     defaultStatsPath,
+			},
+			{
+				Name:        "token",
+				Kind:        config.PropertyKindString,
+				Description: "set bearer authentication token, sent as a header or as a token query parameter",
+			},
+			{
+				Name:        "allowed_origins",
+				Kind:        config.PropertyKindString,
+				Description: "set comma separated list of allowed origins",
+				Default:     "*",
+			},
+			{
+				Name:        "buffer_size",
+				Kind:        config.PropertyKindInt,
+				Description: "set how many messages are buffered for each client",
+				Default:     fmt.Sprintf("%d", defaultBufferSize),
+				Min:         1,
+				Max:         1000000,
+			},
+			{
+				Name:        "slow_client",
+				Kind:        config.PropertyKindString,
+				Description: "set what to do with a client which buffer is full",
+				Default:     slowClientDrop,
+				Options:     []string{slowClientDrop, slowClientDisconnect},
+			},
+			{
+				Name:        "ping_interval_seconds",
+				Kind:        config.PropertyKindInt,
+				Description: "set keep alive ping interval in seconds",
+				Default:     fmt.Sprintf("%d", defaultPingIntervalSeconds),
+				Min:         1,
+				Max:         60 * 60,
+			},
+			{
+				Name:        "cert_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls certificate file path",
+			},
+			{
+				Name:        "key_file",
+				Kind:        config.PropertyKindString,
+				Description: "set tls key file path",
+			},
+		},
+	}
+}
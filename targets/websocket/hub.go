@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+)
+
+// filter selects the messages a client receives, by channel patterns and by tags values
+type filter struct {
+	Channels []string          `json:"channels,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// parseFilter parses the channel and tag query parameters, for example ?channel=orders.*&tag=type:created
+func parseFilter(query url.Values) filter {
+	f := filter{
+		Channels: query["channel"],
+		Tags:     map[string]string{},
+	}
+	for _, tag := range query["tag"] {
+		key, value, _ := strings.Cut(tag, ":")
+		f.Tags[key] = value
+	}
+	return f
+}
+
+// match returns true when the message channel matches one of the channel patterns, and the message has all the tags values
+func (f filter) match(msg *message) bool {
+	if len(f.Channels) > 0 {
+		matched := false
+		for _, pattern := range f.Channels {
+			if ok, _ := path.Match(pattern, msg.Channel); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for key, value := range f.Tags {
+		if msg.Tags[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+type client struct {
+	id          string
+	transport   string
+	remote      string
+	filter      filter
+	connectedAt time.Time
+	sendCh      chan []byte
+	done        chan struct{}
+	closeOnce   sync.Once
+	sent        int64
+	dropped     int64
+}
+
+func newClient(id, transport, remote string, f filter, bufferSize int) *client {
+	return &client{
+		id:          id,
+		transport:   transport,
+		remote:      remote,
+		filter:      f,
+		connectedAt: time.Now(),
+		sendCh:      make(chan []byte, bufferSize),
+		done:        make(chan struct{}),
+	}
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// ClientStats is a report of a connected client
+type ClientStats struct {
+	Id          string    `json:"id"`
+	Transport   string    `json:"transport"`
+	Remote      string    `json:"remote"`
+	Filter      filter    `json:"filter"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Pending     int       `json:"pending"`
+	Sent        int64     `json:"sent"`
+	Dropped     int64     `json:"dropped"`
+}
+
+// hub broadcasts the messages to the connected clients, a message is dropped for a client which buffer is full,
+// so a slow client never blocks the target
+type hub struct {
+	sync.RWMutex
+	clients    map[string]*client
+	slowClient string
+}
+
+func newHub(slowClient string) *hub {
+	return &hub{
+		clients:    map[string]*client{},
+		slowClient: slowClient,
+	}
+}
+
+func (h *hub) add(c *client) {
+	h.Lock()
+	defer h.Unlock()
+	h.clients[c.id] = c
+}
+
+func (h *hub) remove(c *client) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.clients, c.id)
+	c.close()
+}
+
+func (h *hub) broadcast(msg *message, data []byte) {
+	h.RLock()
+	defer h.RUnlock()
+	for _, c := range h.clients {
+		if !c.filter.match(msg) {
+			continue
+		}
+		select {
+		case c.sendCh <- data:
+		case <-c.done:
+		default:
+			atomic.AddInt64(&c.dropped, 1)
+			if h.slowClient == slowClientDisconnect {
+				c.close()
+			}
+		}
+	}
+}
+
+func (h *hub) closeAll() {
+	h.Lock()
+	defer h.Unlock()
+	for id, c := range h.clients {
+		c.close()
+		delete(h.clients, id)
+	}
+}
+
+func (h *hub) stats() []*ClientStats {
+	h.RLock()
+	defer h.RUnlock()
+	var list []*ClientStats
+	for _, c := range h.clients {
+		list = append(list, &ClientStats{
+			Id:          c.id,
+			Transport:   c.transport,
+			Remote:      c.remote,
+			Filter:      c.filter,
+			ConnectedAt: c.connectedAt,
+			Pending:     len(c.sendCh),
+			Sent:        atomic.LoadInt64(&c.sent),
+			Dropped:     atomic.LoadInt64(&c.dropped),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ConnectedAt.Before(list[j].ConnectedAt)
+	})
+	return list
+}
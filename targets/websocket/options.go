@@ -0,0 +1,83 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultAddress             = "0.0.0.0:8085"
+	defaultPath                = "/"
+	defaultStatsPath           = "/stats"
+	defaultBufferSize          = 100
+	defaultPingIntervalSeconds = 30
+
+	slowClientDrop       = "drop"
+	slowClientDisconnect = "disconnect"
+)
+
+var slowClientPolicies = map[string]string{
+	"":                   slowClientDrop,
+	slowClientDrop:       slowClientDrop,
+	slowClientDisconnect: slowClientDisconnect,
+}
+
+type options struct {
+	host                string
+	port                int
+	path                string
+	statsPath           string
+	token               string
+	allowedOrigins      []string
+	bufferSize          int
+	slowClient          string
+	pingIntervalSeconds int
+	certFile            string
+	keyFile             string
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.host, o.port, err = cfg.MustParseAddress("address", defaultAddress)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing address value, %w", err)
+	}
+	o.path = cfg.ParseString("path", defaultPath)
+	if !strings.HasPrefix(o.path, "/") {
+		o.path = "/" + o.path
+	}
+	o.statsPath = cfg.ParseString("stats_path", defaultStatsPath)
+	if !strings.HasPrefix(o.statsPath, "/") {
+		o.statsPath = "/" + o.statsPath
+	}
+	if o.statsPath == o.path {
+		return options{}, fmt.Errorf("error parsing stats path value, stats path must be different than path")
+	}
+	o.token = cfg.ParseString("token", "")
+	for _, origin := range strings.Split(cfg.ParseString("allowed_origins", "*"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			o.allowedOrigins = append(o.allowedOrigins, origin)
+		}
+	}
+	o.bufferSize, err = cfg.ParseIntWithRange("buffer_size", defaultBufferSize, 1, 1000000)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing buffer size value, %w", err)
+	}
+	o.slowClient, err = cfg.ParseStringMap("slow_client", slowClientPolicies)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing slow client value, %w", err)
+	}
+	o.pingIntervalSeconds, err = cfg.ParseIntWithRange("ping_interval_seconds", defaultPingIntervalSeconds, 1, 60*60)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing ping interval seconds value, %w", err)
+	}
+	o.certFile = cfg.ParseString("cert_file", "")
+	o.keyFile = cfg.ParseString("key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls values, both cert_file and key_file must be set")
+	}
+	return o, nil
+}